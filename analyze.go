@@ -2,28 +2,51 @@ package graft
 
 import (
 	"fmt"
+	"go/token"
 	"strings"
 
 	"github.com/grindlemire/graft/internal/typeaware"
 )
 
+// DynamicNodeID is the NodeID reported for a node whose ID could not be
+// determined statically, such as one built from values known only at runtime.
+const DynamicNodeID = typeaware.DynamicID
+
+// DuplicateIDError is returned by [AnalyzeDir] and [ValidateDeps] when more
+// than one graft.Register call registers the same node ID, which would
+// panic at runtime. Use errors.As to inspect the duplicated IDs and their
+// registration sites.
+type DuplicateIDError = typeaware.DuplicateIDError
+
 // AnalysisResult contains the result of analyzing a node's dependency usage.
 //
 // It captures both declared dependencies (in DependsOn) and used dependencies
 // (via Dep[T] calls), allowing detection of mismatches.
 type AnalysisResult struct {
-	// NodeID is the ID field value from the analyzed node.
+	// NodeID is the ID field value from the analyzed node, or DynamicNodeID
+	// if it could not be determined statically.
 	NodeID string
 
 	// File is the path to the source file containing the node.
 	File string
 
+	// Line is the line of the graft.Register call in File, or 0 if unknown.
+	Line int
+
 	// DeclaredDeps are the dependency IDs listed in the DependsOn field.
 	DeclaredDeps []string
 
 	// UsedDeps are the dependency IDs accessed via Dep[T] calls in Run.
 	UsedDeps []string
 
+	// DeclaredDepPositions maps each declared dependency ID to the position
+	// of the DependsOn element naming it, when known.
+	DeclaredDepPositions map[string]token.Position
+
+	// UsedDepPositions maps each used dependency ID to the position of its
+	// first Dep[T] call, when known.
+	UsedDepPositions map[string]token.Position
+
 	// Undeclared are dependencies used but not declared in DependsOn.
 	// These will cause runtime errors.
 	Undeclared []string
@@ -32,10 +55,47 @@ type AnalysisResult struct {
 	// These indicate dead code or missing implementation.
 	Unused []string
 
+	// Suppressed are findings silenced by a //graft:ignore directive on the
+	// node's graft.Register call, such as "unused:cache". They are not
+	// included in Undeclared or Unused.
+	Suppressed []string
+
 	// Cycles are circular dependency paths this node participates in.
 	// Each cycle is represented as a path of node IDs forming a loop.
 	// For example: ["svc5", "svc5-2", "svc5"] indicates svc5 → svc5-2 → svc5.
 	Cycles [][]string
+
+	// IsLeaf is true if no other analyzed node declares this node in DependsOn.
+	IsLeaf bool
+
+	// IsUnreachable is true if the node is a leaf and the analyzed code never
+	// executes it: there is no graft.ExecuteFor[T] call for its output type and
+	// no graft.Execute call that runs the whole graph.
+	IsUnreachable bool
+
+	// HasNestedExecution is true if the node's Run function (or a helper it
+	// calls) invokes graft.Execute or graft.ExecuteFor[T], whose dependencies
+	// are hidden from the outer graph.
+	HasNestedExecution bool
+
+	// DepCallsOutsideRun are the functions reached from the node's Run
+	// function that call graft.Dep[T] and are also called from code outside
+	// every node's Run function, where the Dep call fails at runtime.
+	DepCallsOutsideRun []string
+
+	// IsTest is true if the node is registered (or patched) in a _test.go
+	// file. Test files are only analyzed when tests are included.
+	IsTest bool
+
+	// IsOverride is true if the node is a replacement passed to
+	// graft.Patch[T] rather than a registration. Its NodeID is the ID of the
+	// node it replaces, and unused dependencies are not reported since a
+	// mock commonly ignores inputs that the real node needs.
+	IsOverride bool
+
+	// Warnings are non-fatal observations that deserve attention but do not
+	// count as issues (e.g., a node with unusually many dependencies).
+	Warnings []string
 }
 
 // HasIssues returns true if there are undeclared, unused dependencies, or cycles.
@@ -43,6 +103,39 @@ func (r AnalysisResult) HasIssues() bool {
 	return len(r.Undeclared) > 0 || len(r.Unused) > 0 || len(r.Cycles) > 0
 }
 
+// HasWarnings returns true if the analysis produced any warnings.
+func (r AnalysisResult) HasWarnings() bool {
+	return len(r.Warnings) > 0
+}
+
+// Diagnostics returns one compiler-style line per undeclared or unused
+// dependency, positioned at the Dep[T] call or DependsOn element involved.
+// When that position is unknown, the node's registration is used instead.
+//
+// Example output:
+//
+//	nodes/db/db.go:42:10: undeclared dep "cache"
+//	nodes/db/db.go:21:25: unused dep "config"
+func (r AnalysisResult) Diagnostics() []string {
+	var diags []string
+	for _, dep := range r.Undeclared {
+		diags = append(diags, fmt.Sprintf("%s: undeclared dep %q", r.depPosition(r.UsedDepPositions, dep), dep))
+	}
+	for _, dep := range r.Unused {
+		diags = append(diags, fmt.Sprintf("%s: unused dep %q", r.depPosition(r.DeclaredDepPositions, dep), dep))
+	}
+	return diags
+}
+
+// depPosition returns the position of dep in positions, falling back to the
+// node's registration.
+func (r AnalysisResult) depPosition(positions map[string]token.Position, dep string) token.Position {
+	if pos, ok := positions[dep]; ok {
+		return pos
+	}
+	return token.Position{Filename: r.File, Line: r.Line}
+}
+
 // String returns a human-readable summary of issues.
 //
 // Returns "NodeID: OK" if there are no issues, otherwise returns
@@ -101,27 +194,92 @@ var AnalyzeDirDebug = false
 //	    }
 //	}
 func AnalyzeDir(dir string) ([]typeaware.Result, error) {
+	return AnalyzeDirWithOptions(dir, AnalyzeOptions{})
+}
+
+// AnalyzeOptions configures [AnalyzeDirWithOptions].
+type AnalyzeOptions struct {
+	// IncludeTests also analyzes _test.go files. Nodes registered there are
+	// reported with IsTest set, and the Node[T] values passed to
+	// graft.Patch[T] are checked as overrides of the nodes they replace
+	// (see [AnalysisResult.IsOverride]). Overrides installed with
+	// graft.PatchValue[T] or graft.MergeRegistry have no Run function to
+	// check and are never reported.
+	IncludeTests bool
+}
+
+// AnalyzeDirWithOptions is like [AnalyzeDir] with additional options.
+//
+// Example:
+//
+//	results, err := graft.AnalyzeDirWithOptions("./nodes", graft.AnalyzeOptions{
+//	    IncludeTests: true,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, r := range results {
+//	    if r.IsTest && r.HasIssues() {
+//	        fmt.Println(r.String())
+//	    }
+//	}
+func AnalyzeDirWithOptions(dir string, opts AnalyzeOptions) ([]typeaware.Result, error) {
 	cfg := typeaware.Config{
-		WorkDir: dir,
-		Debug:   AnalyzeDirDebug,
+		WorkDir:      dir,
+		Debug:        AnalyzeDirDebug,
+		IncludeTests: opts.IncludeTests,
 	}
 	analyzer := typeaware.New(cfg)
 	return analyzer.Analyze(dir)
 }
 
+// AnalyzeWorkspace is like [AnalyzeDir] for multi-module repositories. It
+// reads the go.work file in workspaceRoot and analyzes every package of
+// every module it uses, so a node in one module can depend on a node
+// registered in a sibling module.
+//
+// Example:
+//
+//	results, err := graft.AnalyzeWorkspace(".")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func AnalyzeWorkspace(workspaceRoot string) ([]typeaware.Result, error) {
+	cfg := typeaware.Config{
+		WorkDir: workspaceRoot,
+		Debug:   AnalyzeDirDebug,
+	}
+	analyzer := typeaware.New(cfg)
+	return analyzer.AnalyzeWorkspace(workspaceRoot)
+}
+
 // ValidateDeps is a convenience function that returns an error if any
-// dependency issues are found.
+// dependency issues are found. Warnings are not treated as errors.
 //
 // Pass "." for the current directory or a specific path. This is useful
 // for CI integration or programmatic validation.
 //
+// If dir is inside a Go workspace (a go.work file exists in dir or any
+// parent, and GOWORK is not "off"), the whole workspace is validated with
+// [AnalyzeWorkspace].
+//
 // Example:
 //
 //	if err := graft.ValidateDeps("./nodes"); err != nil {
 //	    log.Fatal(err)
 //	}
 func ValidateDeps(dir string) error {
-	results, err := AnalyzeDir(dir)
+	root, err := typeaware.FindWorkspaceRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	var results []typeaware.Result
+	if root != "" {
+		results, err = AnalyzeWorkspace(root)
+	} else {
+		results, err = AnalyzeDir(dir)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,171 @@
+package graft
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+// AnalysisDiff describes how one node's analysis changed between two
+// versions of a directory. See [AnalyzeDiff].
+type AnalysisDiff struct {
+	// NodeID identifies the node in either version.
+	NodeID string
+
+	// Added is true if the node only exists in the after version.
+	Added bool
+
+	// Deleted is true if the node only exists in the before version.
+	Deleted bool
+
+	// AddedDeps are dependencies declared in DependsOn after but not before.
+	AddedDeps []string
+
+	// RemovedDeps are dependencies declared in DependsOn before but not after.
+	RemovedDeps []string
+
+	// AddedUndeclared are undeclared dependencies introduced by the change.
+	AddedUndeclared []string
+
+	// RemovedUndeclared are undeclared dependencies fixed by the change.
+	RemovedUndeclared []string
+}
+
+// AnalyzeDiff runs [AnalyzeDir] on two versions of a directory, matches
+// nodes by ID, and reports what changed. Nodes whose analysis is identical
+// in both versions are omitted; the rest are sorted by ID.
+//
+// Example:
+//
+//	diffs, err := graft.AnalyzeDiff("./base/nodes", "./nodes")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	graft.PrintAnalysisDiff(os.Stdout, diffs)
+func AnalyzeDiff(dirBefore, dirAfter string) ([]AnalysisDiff, error) {
+	before, err := AnalyzeDir(dirBefore)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s: %w", dirBefore, err)
+	}
+	after, err := AnalyzeDir(dirAfter)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s: %w", dirAfter, err)
+	}
+	return diffAnalysis(before, after), nil
+}
+
+// diffAnalysis compares two sets of analysis results by node ID.
+func diffAnalysis(before, after []typeaware.Result) []AnalysisDiff {
+	beforeByID := make(map[string]typeaware.Result, len(before))
+	for _, r := range before {
+		beforeByID[r.NodeID] = r
+	}
+	afterByID := make(map[string]typeaware.Result, len(after))
+	for _, r := range after {
+		afterByID[r.NodeID] = r
+	}
+
+	ids := make([]string, 0, len(beforeByID)+len(afterByID))
+	for id := range beforeByID {
+		ids = append(ids, id)
+	}
+	for id := range afterByID {
+		if _, ok := beforeByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var diffs []AnalysisDiff
+	for _, id := range ids {
+		b, inBefore := beforeByID[id]
+		a, inAfter := afterByID[id]
+		d := AnalysisDiff{
+			NodeID:            id,
+			Added:             !inBefore,
+			Deleted:           !inAfter,
+			AddedDeps:         stringsMissing(a.DeclaredDeps, b.DeclaredDeps),
+			RemovedDeps:       stringsMissing(b.DeclaredDeps, a.DeclaredDeps),
+			AddedUndeclared:   stringsMissing(a.Undeclared, b.Undeclared),
+			RemovedUndeclared: stringsMissing(b.Undeclared, a.Undeclared),
+		}
+		if d.Added || d.Deleted || len(d.AddedDeps) > 0 || len(d.RemovedDeps) > 0 ||
+			len(d.AddedUndeclared) > 0 || len(d.RemovedUndeclared) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// stringsMissing returns the sorted, unique values in from that are not in other.
+func stringsMissing(from, other []string) []string {
+	exclude := make(map[string]bool, len(other))
+	for _, s := range other {
+		exclude[s] = true
+	}
+
+	var out []string
+	for _, s := range from {
+		if !exclude[s] {
+			out = append(out, s)
+			exclude[s] = true
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PrintAnalysisDiff writes diffs in a +/- format suitable for CI pull
+// request comments. Added nodes are prefixed with "+", deleted nodes with
+// "-" and changed nodes with "~", followed by one indented line per changed
+// dependency.
+//
+// Example output:
+//
+//	~ node api
+//	    + dep cache
+//	    - dep db
+//	    - undeclared metrics
+//	+ node cache
+//	    + dep config
+//	- node legacy
+//	    - dep config
+func PrintAnalysisDiff(w io.Writer, diffs []AnalysisDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(w, "No dependency changes")
+		return err
+	}
+
+	for _, d := range diffs {
+		marker := "~"
+		switch {
+		case d.Added:
+			marker = "+"
+		case d.Deleted:
+			marker = "-"
+		}
+		if _, err := fmt.Fprintf(w, "%s node %s\n", marker, d.NodeID); err != nil {
+			return err
+		}
+
+		lines := []struct {
+			prefix string
+			ids    []string
+		}{
+			{"+ dep", d.AddedDeps},
+			{"- dep", d.RemovedDeps},
+			{"+ undeclared", d.AddedUndeclared},
+			{"- undeclared", d.RemovedUndeclared},
+		}
+		for _, l := range lines {
+			for _, id := range l.ids {
+				if _, err := fmt.Fprintf(w, "    %s %s\n", l.prefix, id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
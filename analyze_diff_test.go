@@ -0,0 +1,93 @@
+package graft
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+func TestDiffAnalysis(t *testing.T) {
+	type tc struct {
+		before []typeaware.Result
+		after  []typeaware.Result
+		want   []AnalysisDiff
+	}
+
+	tests := map[string]tc{
+		"unchanged": {
+			before: []typeaware.Result{{NodeID: "api", DeclaredDeps: []string{"db"}}},
+			after:  []typeaware.Result{{NodeID: "api", DeclaredDeps: []string{"db"}}},
+		},
+		"changed deps": {
+			before: []typeaware.Result{{NodeID: "api", DeclaredDeps: []string{"db"}, Undeclared: []string{"metrics"}}},
+			after:  []typeaware.Result{{NodeID: "api", DeclaredDeps: []string{"cache", "metrics"}}},
+			want: []AnalysisDiff{{
+				NodeID:            "api",
+				AddedDeps:         []string{"cache", "metrics"},
+				RemovedDeps:       []string{"db"},
+				RemovedUndeclared: []string{"metrics"},
+			}},
+		},
+		"added and deleted nodes": {
+			before: []typeaware.Result{{NodeID: "legacy", DeclaredDeps: []string{"config"}}},
+			after:  []typeaware.Result{{NodeID: "cache", DeclaredDeps: []string{"config"}, Undeclared: []string{"db"}}},
+			want: []AnalysisDiff{
+				{NodeID: "cache", Added: true, AddedDeps: []string{"config"}, AddedUndeclared: []string{"db"}},
+				{NodeID: "legacy", Deleted: true, RemovedDeps: []string{"config"}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diffAnalysis(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffAnalysis() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintAnalysisDiff(t *testing.T) {
+	type tc struct {
+		diffs []AnalysisDiff
+		want  string
+	}
+
+	tests := map[string]tc{
+		"no changes": {
+			want: "No dependency changes\n",
+		},
+		"all kinds": {
+			diffs: []AnalysisDiff{
+				{NodeID: "api", AddedDeps: []string{"cache"}, RemovedDeps: []string{"db"}, RemovedUndeclared: []string{"metrics"}},
+				{NodeID: "cache", Added: true, AddedDeps: []string{"config"}, AddedUndeclared: []string{"db"}},
+				{NodeID: "legacy", Deleted: true, RemovedDeps: []string{"config"}},
+			},
+			want: `~ node api
+    + dep cache
+    - dep db
+    - undeclared metrics
++ node cache
+    + dep config
+    + undeclared db
+- node legacy
+    - dep config
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PrintAnalysisDiff(&buf, tt.diffs); err != nil {
+				t.Fatalf("PrintAnalysisDiff error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
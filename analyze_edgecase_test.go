@@ -1,6 +1,7 @@
 package graft
 
 import (
+	"errors"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -581,6 +582,19 @@ func TestAnalyzeDirTypeVariations(t *testing.T) {
 				"app":    {declared: []string{"config"}, used: []string{"config"}},
 			},
 		},
+		"cross_package_alias": {
+			dir:        "examples/edgecases/cross_package_alias",
+			wantNodes:  3,
+			wantIssues: 0,
+			wantDeps: map[string]struct {
+				declared []string
+				used     []string
+			}{
+				"config": {declared: []string{}, used: []string{}},
+				"app":    {declared: []string{"config"}, used: []string{"config"}},
+				"report": {declared: []string{"config"}, used: []string{"config"}},
+			},
+		},
 	}
 
 	for name, tt := range tests {
@@ -820,6 +834,36 @@ func TestAnalyzeDirTypeConflicts(t *testing.T) {
 	}
 }
 
+// TestAnalyzeDirDuplicateIDs tests that the same ID registered in two
+// packages is a fatal analysis error.
+func TestAnalyzeDirDuplicateIDs(t *testing.T) {
+	absDir, err := filepath.Abs("examples/edgecases/duplicate_ids")
+	if err != nil {
+		t.Fatalf("failed to get absolute path: %v", err)
+	}
+
+	_, err = AnalyzeDir(absDir)
+
+	var dupErr *DuplicateIDError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("expected DuplicateIDError, got: %v", err)
+	}
+
+	positions := dupErr.Duplicates["config"]
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 registrations of config, got %v", dupErr.Duplicates)
+	}
+	for i, want := range []string{"envconfig/config.go", "fileconfig/config.go"} {
+		if !strings.HasSuffix(positions[i].Filename, want) {
+			t.Errorf("registration %d in %s, want %s", i, positions[i].Filename, want)
+		}
+	}
+
+	if err := ValidateDeps(absDir); !errors.As(err, &dupErr) {
+		t.Errorf("ValidateDeps: expected DuplicateIDError, got: %v", err)
+	}
+}
+
 // Helper functions
 
 // findNode finds a node by ID in the results.
@@ -0,0 +1,106 @@
+package graft
+
+import (
+	"strconv"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+// AnalysisSummaryStats aggregates [AnalyzeDir] results into codebase-wide
+// dependency hygiene metrics. See [AnalysisSummary].
+type AnalysisSummaryStats struct {
+	// TotalNodes is the number of analyzed nodes.
+	TotalNodes int
+
+	// NodesWithIssues is the number of nodes with undeclared or unused
+	// dependencies or cycles.
+	NodesWithIssues int
+
+	// TotalUndeclaredDeps is the number of undeclared dependencies across
+	// all nodes.
+	TotalUndeclaredDeps int
+
+	// TotalUnusedDeps is the number of unused dependencies across all nodes.
+	TotalUnusedDeps int
+
+	// AverageDepsPerNode is the mean number of declared dependencies, or 0
+	// if there are no nodes.
+	AverageDepsPerNode float64
+
+	// MaxDepsPerNode is the highest number of declared dependencies of any
+	// node.
+	MaxDepsPerNode int
+
+	// MaxDepsNodeID is the node with MaxDepsPerNode declared dependencies,
+	// the smallest ID on ties, or "" if there are no nodes.
+	MaxDepsNodeID string
+}
+
+// AnalysisSummary computes [AnalysisSummaryStats] for results. Patch
+// overrides (IsOverride) are skipped, since they describe replacements of
+// nodes that are already counted.
+//
+// Example:
+//
+//	results, err := graft.AnalyzeDir("./nodes")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	stats := graft.AnalysisSummary(results)
+//	fmt.Printf("%d/%d nodes with issues\n", stats.NodesWithIssues, stats.TotalNodes)
+func AnalysisSummary(results []typeaware.Result) AnalysisSummaryStats {
+	var stats AnalysisSummaryStats
+	totalDeps := 0
+
+	for _, r := range results {
+		if r.IsOverride {
+			continue
+		}
+
+		stats.TotalNodes++
+		if r.HasIssues() {
+			stats.NodesWithIssues++
+		}
+		stats.TotalUndeclaredDeps += len(r.Undeclared)
+		stats.TotalUnusedDeps += len(r.Unused)
+
+		deps := len(r.DeclaredDeps)
+		totalDeps += deps
+		if stats.MaxDepsNodeID == "" || deps > stats.MaxDepsPerNode ||
+			(deps == stats.MaxDepsPerNode && r.NodeID < stats.MaxDepsNodeID) {
+			stats.MaxDepsPerNode = deps
+			stats.MaxDepsNodeID = r.NodeID
+		}
+	}
+
+	if stats.TotalNodes > 0 {
+		stats.AverageDepsPerNode = float64(totalDeps) / float64(stats.TotalNodes)
+	}
+	return stats
+}
+
+// ToPrometheusLabels returns the stats as snake_case label names mapped to
+// their formatted values, for attaching to a metric or info series.
+//
+// Example:
+//
+//	for name, value := range stats.ToPrometheusLabels() {
+//	    fmt.Printf("%s=%q\n", name, value)
+//	}
+//
+// Example output:
+//
+//	total_nodes="12"
+//	average_deps_per_node="2.50"
+//	max_deps_node_id="api"
+func (s AnalysisSummaryStats) ToPrometheusLabels() map[string]string {
+	return map[string]string{
+		"total_nodes":           strconv.Itoa(s.TotalNodes),
+		"nodes_with_issues":     strconv.Itoa(s.NodesWithIssues),
+		"total_undeclared_deps": strconv.Itoa(s.TotalUndeclaredDeps),
+		"total_unused_deps":     strconv.Itoa(s.TotalUnusedDeps),
+		"average_deps_per_node": strconv.FormatFloat(s.AverageDepsPerNode, 'f', 2, 64),
+		"max_deps_per_node":     strconv.Itoa(s.MaxDepsPerNode),
+		"max_deps_node_id":      s.MaxDepsNodeID,
+	}
+}
@@ -0,0 +1,82 @@
+package graft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+func TestAnalysisSummary(t *testing.T) {
+	type tc struct {
+		results []typeaware.Result
+		want    AnalysisSummaryStats
+	}
+
+	tests := map[string]tc{
+		"empty": {},
+		"mixed": {
+			results: []typeaware.Result{
+				{NodeID: "config"},
+				{NodeID: "db", DeclaredDeps: []string{"config"}},
+				{NodeID: "cache", DeclaredDeps: []string{"config", "db"}, Unused: []string{"db"}},
+				{NodeID: "api", DeclaredDeps: []string{"cache", "db"}, Undeclared: []string{"config", "metrics"}},
+				{NodeID: "api", IsOverride: true, DeclaredDeps: []string{"a", "b", "c", "d"}},
+			},
+			want: AnalysisSummaryStats{
+				TotalNodes:          4,
+				NodesWithIssues:     2,
+				TotalUndeclaredDeps: 2,
+				TotalUnusedDeps:     1,
+				AverageDepsPerNode:  1.25,
+				MaxDepsPerNode:      2,
+				MaxDepsNodeID:       "api",
+			},
+		},
+		"cycle counts as issue": {
+			results: []typeaware.Result{
+				{NodeID: "a", DeclaredDeps: []string{"a"}, Cycles: [][]string{{"a", "a"}}},
+			},
+			want: AnalysisSummaryStats{
+				TotalNodes:         1,
+				NodesWithIssues:    1,
+				AverageDepsPerNode: 1,
+				MaxDepsPerNode:     1,
+				MaxDepsNodeID:      "a",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := AnalysisSummary(tt.results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnalysisSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysisSummaryStatsToPrometheusLabels(t *testing.T) {
+	stats := AnalysisSummaryStats{
+		TotalNodes:          4,
+		NodesWithIssues:     2,
+		TotalUndeclaredDeps: 2,
+		TotalUnusedDeps:     1,
+		AverageDepsPerNode:  1.25,
+		MaxDepsPerNode:      2,
+		MaxDepsNodeID:       "api",
+	}
+
+	want := map[string]string{
+		"total_nodes":           "4",
+		"nodes_with_issues":     "2",
+		"total_undeclared_deps": "2",
+		"total_unused_deps":     "1",
+		"average_deps_per_node": "1.25",
+		"max_deps_per_node":     "2",
+		"max_deps_node_id":      "api",
+	}
+	if got := stats.ToPrometheusLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPrometheusLabels() = %v, want %v", got, want)
+	}
+}
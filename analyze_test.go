@@ -1,7 +1,10 @@
 package graft
 
 import (
+	"go/token"
+	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -603,3 +606,1390 @@ func TestCheckDepsValidWithIssues(t *testing.T) {
 		t.Error("CheckDepsValid() should return results with issues for undeclared_multiple")
 	}
 }
+
+// TestAnalyzeDirReachability tests leaf and unreachable node detection.
+func TestAnalyzeDirReachability(t *testing.T) {
+	type tc struct {
+		files           map[string]string
+		dir             string
+		wantLeaf        []string
+		wantUnreachable []string
+	}
+
+	nodes := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type App struct{}
+type Orphan struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (App, error) {
+			_, err := graft.Dep[Config](ctx)
+			return App{}, err
+		},
+	})
+	graft.Register(graft.Node[Orphan]{
+		ID:  "orphan",
+		Run: func(ctx context.Context) (Orphan, error) { return Orphan{}, nil },
+	})
+}
+`
+
+	tests := map[string]tc{
+		"no execution calls": {
+			files: map[string]string{
+				"nodes.go": nodes,
+				"main.go":  "package main\n\nfunc main() {}\n",
+			},
+			wantLeaf:        []string{"app", "orphan"},
+			wantUnreachable: []string{"app", "orphan"},
+		},
+		"ExecuteFor targets a leaf": {
+			files: map[string]string{
+				"nodes.go": nodes,
+				"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+func main() {
+	_, _, _ = graft.ExecuteFor[App](context.Background())
+}
+`,
+			},
+			wantLeaf:        []string{"app", "orphan"},
+			wantUnreachable: []string{"orphan"},
+		},
+		"Execute runs the whole graph": {
+			files: map[string]string{
+				"nodes.go": nodes,
+				"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+func main() {
+	_, _ = graft.Execute(context.Background())
+}
+`,
+			},
+			wantLeaf:        []string{"app", "orphan"},
+			wantUnreachable: []string{},
+		},
+		"ExecuteForMultiple targets every type argument": {
+			files: map[string]string{
+				"nodes.go": nodes,
+				"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+func main() {
+	_, _, _, _ = graft.ExecuteForMultiple[App, Orphan](context.Background())
+}
+`,
+			},
+			wantLeaf:        []string{"app", "orphan"},
+			wantUnreachable: []string{},
+		},
+		"examples/diamond": {
+			dir:             "examples/diamond",
+			wantLeaf:        []string{"api"},
+			wantUnreachable: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := tt.dir
+			if tt.files != nil {
+				dir = setupTestModule(t, tt.files)
+			}
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				t.Fatalf("failed to get absolute path: %v", err)
+			}
+
+			results, err := AnalyzeDir(absDir)
+			if err != nil {
+				t.Fatalf("AnalyzeDir(%q) error: %v", dir, err)
+			}
+
+			var gotLeaf, gotUnreachable []string
+			for _, r := range results {
+				if r.IsLeaf {
+					gotLeaf = append(gotLeaf, r.NodeID)
+				}
+				if r.IsUnreachable {
+					gotUnreachable = append(gotUnreachable, r.NodeID)
+				}
+			}
+
+			if !equalStringSlices(gotLeaf, tt.wantLeaf) {
+				t.Errorf("leaf nodes = %v, want %v", gotLeaf, tt.wantLeaf)
+			}
+			if !equalStringSlices(gotUnreachable, tt.wantUnreachable) {
+				t.Errorf("unreachable nodes = %v, want %v", gotUnreachable, tt.wantUnreachable)
+			}
+		})
+	}
+}
+
+// TestDepInNestedClosure tests that Dep calls made outside the Run function
+// body itself (closures, helper functions, methods) are attributed to the node.
+func TestDepInNestedClosure(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{ Host string }
+type DB struct{}
+type Cache struct{}
+type App struct{}
+type Report struct{}
+
+type loader struct{ ctx context.Context }
+
+func (l loader) db() (DB, error) {
+	return graft.Dep[DB](l.ctx)
+}
+
+func loadCache(ctx context.Context) (Cache, error) {
+	return graft.Dep[Cache](ctx)
+}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:  "db",
+		Run: func(ctx context.Context) (DB, error) { return DB{}, nil },
+	})
+	graft.Register(graft.Node[Cache]{
+		ID:  "cache",
+		Run: func(ctx context.Context) (Cache, error) { return Cache{}, nil },
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config", "db", "cache"},
+		Run: func(ctx context.Context) (App, error) {
+			var host string
+			func() {
+				cfg, _ := graft.Dep[Config](ctx)
+				host = cfg.Host
+			}()
+			_ = host
+			if _, err := (loader{ctx: ctx}).db(); err != nil {
+				return App{}, err
+			}
+			_, err := loadCache(ctx)
+			return App{}, err
+		},
+	})
+	graft.Register(graft.Node[Report]{
+		ID: "report",
+		Run: func(ctx context.Context) (Report, error) {
+			get := func() error {
+				_, err := graft.Dep[Config](ctx)
+				return err
+			}
+			return Report{}, get()
+		},
+	})
+}
+
+func main() {}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	app := findNode(results, "app")
+	if app.HasIssues() {
+		t.Errorf("app: expected no issues, got %s", app.String())
+	}
+	if !equalStringSlices(app.UsedDeps, []string{"config", "db", "cache"}) {
+		t.Errorf("app: used = %v, want [config db cache]", app.UsedDeps)
+	}
+
+	report := findNode(results, "report")
+	if !equalStringSlices(report.Undeclared, []string{"config"}) {
+		t.Errorf("report: undeclared = %v, want [config]", report.Undeclared)
+	}
+}
+
+func TestAnalyzeEmptyNodeID(t *testing.T) {
+	type tc struct {
+		id      string
+		wantErr bool
+	}
+
+	tests := map[string]tc{
+		"missing ID": {
+			id:      "",
+			wantErr: true,
+		},
+		"empty string ID": {
+			id:      `ID: "",`,
+			wantErr: true,
+		},
+		"constant ID": {
+			id: `ID: "config",`,
+		},
+		"variable ID": {
+			id: `ID: configID,`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+
+var configID graft.ID = "config"
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		` + tt.id + `
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+}
+
+func main() {}
+`
+			tmpDir := setupTestModule(t, map[string]string{
+				"main.go": code,
+			})
+
+			results, err := AnalyzeDir(tmpDir)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "registered with empty ID") {
+					t.Fatalf("expected empty ID error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AnalyzeDir error: %v", err)
+			}
+			if len(results) != 1 || results[0].Line != 14 {
+				t.Errorf("expected one result at line 14, got %+v", results)
+			}
+		})
+	}
+}
+
+// setupTestWorkspace creates a temporary go.work workspace containing one
+// module per entry in modules (directory name -> files). Each module is
+// named after its directory and may import the others.
+func setupTestWorkspace(t *testing.T, modules map[string]map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	graftPath, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatalf("failed to get absolute path: %v", err)
+	}
+
+	goWork := "go 1.25.1\n\nuse (\n"
+	for dir, files := range modules {
+		goWork += "\t./" + dir + "\n"
+
+		goMod := `module ` + dir + `
+
+go 1.25.1
+
+require github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
+
+replace github.com/grindlemire/graft => ` + graftPath + `
+`
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create module dir: %v", err)
+		}
+		files["go.mod"] = goMod
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(root, dir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write %s/%s: %v", dir, name, err)
+			}
+		}
+	}
+	goWork += ")\n"
+	if err := os.WriteFile(filepath.Join(root, "go.work"), []byte(goWork), 0644); err != nil {
+		t.Fatalf("failed to write go.work: %v", err)
+	}
+
+	return root
+}
+
+func TestAnalyzeWorkspace(t *testing.T) {
+	root := setupTestWorkspace(t, map[string]map[string]string{
+		"shared": {
+			"config.go": `package shared
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{ Host string }
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+}
+`,
+		},
+		"svc": {
+			"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"shared"
+)
+
+type API struct{}
+
+func init() {
+	graft.Register(graft.Node[API]{
+		ID: "api",
+		Run: func(ctx context.Context) (API, error) {
+			_, err := graft.Dep[shared.Config](ctx)
+			return API{}, err
+		},
+	})
+}
+
+func main() {
+	graft.ExecuteFor[API](context.Background())
+}
+`,
+		},
+	})
+
+	results, err := AnalyzeWorkspace(root)
+	if err != nil {
+		t.Fatalf("AnalyzeWorkspace error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 nodes across both modules, got %d: %v", len(results), results)
+	}
+
+	api := findNode(results, "api")
+	if !equalStringSlices(api.Undeclared, []string{"config"}) {
+		t.Errorf("api: undeclared = %v, want [config]", api.Undeclared)
+	}
+
+	// ValidateDeps from inside a module picks up the whole workspace
+	err = ValidateDeps(filepath.Join(root, "svc"))
+	if err == nil || !strings.Contains(err.Error(), "undeclared deps: [config]") {
+		t.Errorf("ValidateDeps error = %v, want undeclared config", err)
+	}
+}
+
+func TestAnalyzeDepsHelpers(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+type Cache struct{}
+type API struct{}
+type Worker struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: graft.DepsOf[Config](),
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+	graft.Register(graft.Node[Cache]{
+		ID:        "cache",
+		DependsOn: graft.Deps("config", "config"),
+		Run: func(ctx context.Context) (Cache, error) {
+			_, err := graft.Dep[Config](ctx)
+			return Cache{}, err
+		},
+	})
+	graft.Register(graft.Node[API]{
+		ID:        "api",
+		DependsOn: graft.DepsOf3[DB, Cache, Config](),
+		Run: func(ctx context.Context) (API, error) {
+			if _, err := graft.Dep[DB](ctx); err != nil {
+				return API{}, err
+			}
+			_, err := graft.Dep[Cache](ctx)
+			return API{}, err
+		},
+	})
+	graft.Register(graft.Node[Worker]{
+		ID:        "worker",
+		DependsOn: graft.Deps(),
+		Run: func(ctx context.Context) (Worker, error) {
+			_, err := graft.Dep[DB](ctx)
+			return Worker{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	type want struct {
+		declared   []string
+		undeclared []string
+		unused     []string
+	}
+	for id, w := range map[string]want{
+		"db":     {declared: []string{"config"}},
+		"cache":  {declared: []string{"config", "config"}},
+		"api":    {declared: []string{"db", "cache", "config"}, unused: []string{"config"}},
+		"worker": {declared: []string{}, undeclared: []string{"db"}},
+	} {
+		r := findNode(results, id)
+		if !equalStringSlices(r.DeclaredDeps, w.declared) {
+			t.Errorf("%s: declared = %v, want %v", id, r.DeclaredDeps, w.declared)
+		}
+		if !equalStringSlices(r.Undeclared, w.undeclared) {
+			t.Errorf("%s: undeclared = %v, want %v", id, r.Undeclared, w.undeclared)
+		}
+		if !equalStringSlices(r.Unused, w.unused) {
+			t.Errorf("%s: unused = %v, want %v", id, r.Unused, w.unused)
+		}
+	}
+}
+
+func TestAnalyzeConstantExpressionIDs(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grindlemire/graft"
+)
+
+const base = "config"
+
+const (
+	v0 = iota
+	v1
+	v2
+)
+
+const configID graft.ID = base + "-v2"
+
+var dbID = graft.ID("db-" + base)
+
+var cacheID = graft.ID(fmt.Sprintf("cache-v%d", v2))
+
+var suffix = "-" + base
+
+type Config struct{}
+type DB struct{}
+type Cache struct{}
+type App struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  configID,
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        dbID,
+		DependsOn: []graft.ID{configID},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+	graft.Register(graft.Node[Cache]{
+		ID:        cacheID,
+		DependsOn: []graft.ID{dbID},
+		Run: func(ctx context.Context) (Cache, error) {
+			_, err := graft.Dep[DB](ctx)
+			return Cache{}, err
+		},
+	})
+	graft.Register(graft.Node[App]{
+		ID:        graft.ID(fmt.Sprintf("%s%s", "app", suffix)),
+		DependsOn: []graft.ID{cacheID, graft.ID("db-" + base), configID},
+		Run: func(ctx context.Context) (App, error) {
+			if _, err := graft.Dep[Cache](ctx); err != nil {
+				return App{}, err
+			}
+			if _, err := graft.Dep[DB](ctx); err != nil {
+				return App{}, err
+			}
+			_, err := graft.Dep[Config](ctx)
+			return App{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	type tc struct {
+		id       string
+		declared []string
+	}
+
+	tests := map[string]tc{
+		"constant concatenation":           {id: "config-v2", declared: []string{}},
+		"package variable concatenation":   {id: "db-config", declared: []string{"config-v2"}},
+		"Sprintf with iota constant":       {id: "cache-v2", declared: []string{"db-config"}},
+		"Sprintf of variable and constant": {id: "app-config", declared: []string{"cache-v2", "db-config", "config-v2"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := findNode(results, tt.id)
+			if r.NodeID != tt.id {
+				t.Fatalf("node %q not found in %v", tt.id, results)
+			}
+			if !equalStringSlices(r.DeclaredDeps, tt.declared) {
+				t.Errorf("declared = %v, want %v", r.DeclaredDeps, tt.declared)
+			}
+			if r.HasIssues() {
+				t.Errorf("unexpected issues: %s", r.String())
+			}
+		})
+	}
+}
+
+func TestAnalyzeDirWithOptionsIncludeTests(t *testing.T) {
+	code := `package nodes
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+}
+`
+	testCode := `package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type Probe struct{}
+
+func init() {
+	graft.Register(graft.Node[Probe]{
+		ID: "probe",
+		Run: func(ctx context.Context) (Probe, error) {
+			_, err := graft.Dep[DB](ctx)
+			return Probe{}, err
+		},
+	})
+}
+
+func TestDB(t *testing.T) {
+	_, _, err := graft.ExecuteFor[DB](context.Background(),
+		graft.Patch[DB](graft.Node[DB]{
+			DependsOn: []graft.ID{"config"},
+			Run:       func(ctx context.Context) (DB, error) { return DB{}, nil },
+		}),
+		graft.PatchValue[Config](Config{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"nodes.go":      code,
+		"nodes_test.go": testCode,
+	})
+
+	type tc struct {
+		opts AnalyzeOptions
+		want map[string]typeaware.Result // keyed by NodeID, plus "+override" for overrides
+	}
+
+	tests := map[string]tc{
+		"tests excluded by default": {
+			opts: AnalyzeOptions{},
+			want: map[string]typeaware.Result{
+				"config": {},
+				"db":     {},
+			},
+		},
+		"tests included": {
+			opts: AnalyzeOptions{IncludeTests: true},
+			want: map[string]typeaware.Result{
+				"config":      {},
+				"db":          {},
+				"probe":       {IsTest: true, Undeclared: []string{"db"}},
+				"db+override": {IsTest: true, IsOverride: true},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := AnalyzeDirWithOptions(tmpDir, tt.opts)
+			if err != nil {
+				t.Fatalf("AnalyzeDirWithOptions error: %v", err)
+			}
+
+			got := make(map[string]typeaware.Result)
+			for _, r := range results {
+				key := r.NodeID
+				if r.IsOverride {
+					key += "+override"
+				}
+				if _, dup := got[key]; dup {
+					t.Errorf("node %s reported more than once", key)
+				}
+				got[key] = typeaware.Result{
+					IsTest:     r.IsTest,
+					IsOverride: r.IsOverride,
+					Undeclared: r.Undeclared,
+					Unused:     r.Unused,
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDirDepPositions(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type Cache struct{}
+type DB struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[Cache]{
+		ID:  "cache",
+		Run: func(ctx context.Context) (Cache, error) { return Cache{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Cache](ctx)
+			return DB{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	var db typeaware.Result
+	for _, r := range results {
+		if r.NodeID == "db" {
+			db = r
+		}
+	}
+
+	type tc struct {
+		positions map[string]token.Position
+		dep       string
+		wantLine  int
+		wantCol   int
+	}
+
+	tests := map[string]tc{
+		"DependsOn element": {positions: db.DeclaredDepPositions, dep: "config", wantLine: 24, wantCol: 25},
+		"Dep call":          {positions: db.UsedDepPositions, dep: "cache", wantLine: 26, wantCol: 14},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pos, ok := tt.positions[tt.dep]
+			if !ok {
+				t.Fatalf("no position for %q in %v", tt.dep, tt.positions)
+			}
+			if filepath.Base(pos.Filename) != "main.go" || pos.Line != tt.wantLine || pos.Column != tt.wantCol {
+				t.Errorf("position = %s, want main.go:%d:%d", pos, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+
+	wantDiags := []string{
+		filepath.Join(tmpDir, "main.go") + `:26:14: undeclared dep "cache"`,
+		filepath.Join(tmpDir, "main.go") + `:24:25: unused dep "config"`,
+	}
+	if got := db.Diagnostics(); !reflect.DeepEqual(got, wantDiags) {
+		t.Errorf("Diagnostics() = %q, want %q", got, wantDiags)
+	}
+}
+
+func TestAnalyzeRegistrationStyles(t *testing.T) {
+	type tc struct {
+		code string
+	}
+
+	header := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+
+func runDB(ctx context.Context) (DB, error) {
+	_, err := graft.Dep[Config](ctx)
+	return DB{}, err
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`
+
+	tests := map[string]tc{
+		"init with inline literals": {
+			code: header + `
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{ID: "db", DependsOn: []graft.ID{"config"}, Run: runDB})
+}
+`,
+		},
+		"package-level var nodes": {
+			code: header + `
+var configNode = graft.Node[Config]{
+	ID:  "config",
+	Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+}
+
+var dbNode = graft.Node[DB]{ID: "db", DependsOn: []graft.ID{"config"}, Run: runDB}
+
+func init() {
+	graft.Register(configNode)
+	graft.Register(dbNode)
+}
+`,
+		},
+		"init calling a helper": {
+			code: header + `
+func init() {
+	registerAll()
+}
+
+func registerAll() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{ID: "db", DependsOn: []graft.ID{"config"}, Run: runDB})
+}
+`,
+		},
+		"factory functions": {
+			code: header + `
+func newConfigNode() graft.Node[Config] {
+	return graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	}
+}
+
+func newDBNode() graft.Node[DB] {
+	return graft.Node[DB]{ID: "db", DependsOn: []graft.ID{"config"}, Run: runDB}
+}
+
+func init() {
+	graft.Register(newConfigNode())
+	graft.Register(newDBNode())
+}
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := setupTestModule(t, map[string]string{
+				"main.go": tt.code,
+			})
+
+			results, err := AnalyzeDir(tmpDir)
+			if err != nil {
+				t.Fatalf("AnalyzeDir error: %v", err)
+			}
+
+			got := make(map[string][]string)
+			for _, r := range results {
+				if r.HasIssues() {
+					t.Errorf("unexpected issues: %s", r.String())
+				}
+				got[r.NodeID] = r.UsedDeps
+			}
+			want := map[string][]string{"config": {}, "db": {"config"}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("nodes = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDirDynamicNodeID(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  graft.ID(os.Getenv("CONFIG_NODE")),
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	byID := make(map[string]typeaware.Result)
+	for _, r := range results {
+		byID[r.NodeID] = r
+	}
+
+	dynamic, ok := byID[DynamicNodeID]
+	if !ok {
+		t.Fatalf("expected a result with NodeID %q, got %v", DynamicNodeID, results)
+	}
+	if !dynamic.HasWarnings() {
+		t.Errorf("expected a warning on the dynamic node")
+	}
+
+	db := byID["db"]
+	if db.HasIssues() {
+		t.Errorf("db should not report issues against a dynamic dependency: %s", db.String())
+	}
+	if !db.HasWarnings() {
+		t.Errorf("expected db to warn that its analysis is incomplete")
+	}
+}
+
+func TestAnalyzeDirIgnoreDirectives(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type Pool struct{}
+type DB struct{}
+type Cache struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[Pool]{
+		ID:  "pool",
+		Run: func(ctx context.Context) (Pool, error) { return Pool{}, nil },
+	})
+
+	// pool is initialized for its side effects only
+	//graft:ignore unused:pool
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config", "pool"},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+
+	graft.Register(graft.Node[Cache]{
+		ID:        "cache",
+		DependsOn: []graft.ID{"pool"}, //graft:ignore unused:pool undeclared:config
+		Run: func(ctx context.Context) (Cache, error) {
+			_, err := graft.Dep[Config](ctx)
+			return Cache{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	suppressed := make(map[string][]string)
+	for _, r := range results {
+		if r.HasIssues() {
+			t.Errorf("unexpected issues: %s", r.String())
+		}
+		if len(r.Suppressed) > 0 {
+			suppressed[r.NodeID] = r.Suppressed
+		}
+	}
+
+	want := map[string][]string{
+		"db":    {"unused:pool"},
+		"cache": {"unused:pool", "undeclared:config"},
+	}
+	if !reflect.DeepEqual(suppressed, want) {
+		t.Errorf("Suppressed = %v, want %v", suppressed, want)
+	}
+
+	mock := &mockT{}
+	AssertDepsValid(mock, tmpDir)
+	if len(mock.errors) > 0 {
+		t.Errorf("expected suppressed findings not to be reported, got %v", mock.errors)
+	}
+}
+
+func TestAnalyzeDirDeprecatedNodes(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type LegacyDB struct{}
+type DB struct{}
+type App struct{}
+
+const legacyMessage = "use the db node instead"
+
+func init() {
+	graft.Register(graft.Node[LegacyDB]{
+		ID:         "legacydb",
+		Deprecated: legacyMessage,
+		Run:        func(ctx context.Context) (LegacyDB, error) { return LegacyDB{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:  "db",
+		Run: func(ctx context.Context) (DB, error) { return DB{}, nil },
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"legacydb", "db"},
+		Run: func(ctx context.Context) (App, error) {
+			if _, err := graft.Dep[LegacyDB](ctx); err != nil {
+				return App{}, err
+			}
+			_, err := graft.Dep[DB](ctx)
+			return App{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	warnings := make(map[string][]string)
+	for _, r := range results {
+		for _, w := range r.Warnings {
+			if strings.Contains(w, "deprecated") {
+				warnings[r.NodeID] = append(warnings[r.NodeID], w)
+			}
+		}
+	}
+
+	want := map[string][]string{
+		"app": {`depends on deprecated node "legacydb": use the db node instead`},
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("deprecation warnings = %v, want %v", warnings, want)
+	}
+}
+
+func TestAnalyzeDirDepCallsOutsideRun(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{ Port int }
+type App struct{}
+type Worker struct{}
+
+// port is shared between a node and main
+func port(ctx context.Context) int {
+	cfg, _ := graft.Dep[Config](ctx)
+	return cfg.Port
+}
+
+// configured is only ever called from Run
+func configured(ctx context.Context) bool {
+	_, err := graft.Dep[Config](ctx)
+	return err == nil
+}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{Port: 8080}, nil },
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (App, error) {
+			_ = port(ctx)
+			return App{}, nil
+		},
+	})
+	graft.Register(graft.Node[Worker]{
+		ID:        "worker",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (Worker, error) {
+			_ = configured(ctx)
+			return Worker{}, nil
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+	println(port(context.Background()))
+}
+`,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, r := range results {
+		if len(r.DepCallsOutsideRun) > 0 {
+			got[r.NodeID] = r.DepCallsOutsideRun
+		}
+		if r.NodeID == "app" && !r.HasWarnings() {
+			t.Errorf("app: expected a warning for the shared helper")
+		}
+	}
+
+	want := map[string][]string{
+		"app": {"testmodule.port"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DepCallsOutsideRun = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterInsideSyncOnce(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+
+var once sync.Once
+
+type module struct{ once sync.Once }
+
+func (m *module) register() {
+	m.once.Do(func() {
+		graft.Register(graft.Node[DB]{
+			ID:        "db",
+			DependsOn: []graft.ID{"config", "cache"},
+			Run: func(ctx context.Context) (DB, error) {
+				_, err := graft.Dep[Config](ctx)
+				return DB{}, err
+			},
+		})
+	})
+}
+
+func init() {
+	once.Do(func() {
+		graft.Register(graft.Node[Config]{
+			ID:  "config",
+			Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+		})
+	})
+	(&module{}).register()
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, r := range results {
+		got[r.NodeID] = r.Unused
+	}
+	want := map[string][]string{
+		"config": nil,
+		"db":     {"cache"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes (id -> unused) = %v, want %v", got, want)
+	}
+}
+
+func TestAnalyzeDirReplacedModule(t *testing.T) {
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"context"
+
+	"example.com/old/shared"
+	"github.com/grindlemire/graft"
+)
+
+type App struct{}
+
+func init() {
+	graft.Register(graft.Node[shared.Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (shared.Config, error) { return shared.Config{}, nil },
+	})
+	graft.Register(graft.Node[shared.DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (shared.DB, error) {
+			_, err := graft.Dep[shared.Config](ctx)
+			return shared.DB{}, err
+		},
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (App, error) {
+			_, err := graft.Dep[shared.DB](ctx)
+			return App{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`,
+	})
+
+	// The output types live in a module imported by its old path and
+	// replaced with a local directory
+	localDir := filepath.Join(tmpDir, "local", "shared")
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(localDir, "go.mod"):    "module example.com/old/shared\n\ngo 1.21\n",
+		filepath.Join(localDir, "shared.go"): "package shared\n\ntype Config struct{}\ntype DB struct{}\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goMod, err := os.ReadFile(filepath.Join(tmpDir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod = append(goMod, []byte("\nrequire example.com/old/shared v0.0.0\n\nreplace example.com/old/shared => ./local/shared\n")...)
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goMod, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+
+	type issues struct{ Undeclared, Unused []string }
+	got := make(map[string]issues)
+	for _, r := range results {
+		got[r.NodeID] = issues{r.Undeclared, r.Unused}
+	}
+	want := map[string]issues{
+		"config": {},
+		"db":     {},
+		"app":    {Undeclared: []string{"db"}, Unused: []string{"config"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Execution is a graph execution running in the background, started with
+// [ExecuteAsync]. Its methods are safe for concurrent use.
+type Execution struct {
+	mu      sync.Mutex
+	results Results
+	ready   map[ID]chan struct{} // closed once the node's result is stored
+
+	done  chan struct{} // closed once the execution has ended
+	final Results
+	err   error
+}
+
+// ExecuteAsync starts [Execute] in a new goroutine and returns immediately.
+// Use [Execution.WaitFor] to consume a node's output as soon as it is
+// available, and [Execution.Wait] for the outcome of the whole execution.
+//
+// Example:
+//
+//	exec := graft.ExecuteAsync(ctx)
+//	cfg, err := exec.WaitFor(ctx, "config") // returns before slower nodes finish
+//	results, err := exec.Wait()
+func ExecuteAsync(ctx context.Context, opts ...Option) *Execution {
+	x := &Execution{
+		results: make(Results),
+		ready:   make(map[ID]chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	opts = append(append([]Option{}, opts...), func(c *config) {
+		c.resultHooks = append(c.resultHooks, x.store)
+	})
+	go func() {
+		results, err := Execute(ctx, opts...)
+		x.finish(results, err)
+	}()
+
+	return x
+}
+
+// WaitFor blocks until node id has produced its output and returns it. If
+// the execution ends without running id, WaitFor returns the execution's
+// error, or an error saying the node was not executed. It returns
+// ctx.Err() if ctx is done first.
+//
+// Example:
+//
+//	out, err := exec.WaitFor(ctx, "db")
+//	db := out.(*sql.DB)
+func (x *Execution) WaitFor(ctx context.Context, id ID) (any, error) {
+	x.mu.Lock()
+	if out, ok := x.results[id]; ok {
+		x.mu.Unlock()
+		return out, nil
+	}
+	ready := x.readyChan(id)
+	x.mu.Unlock()
+
+	select {
+	case <-ready:
+	case <-x.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if out, ok := x.results[id]; ok {
+		return out, nil
+	}
+	if x.err != nil {
+		return nil, x.err
+	}
+	return nil, fmt.Errorf("node %s was not executed", id)
+}
+
+// Wait blocks until the execution ends and returns what [Execute] returned.
+//
+// Example:
+//
+//	results, err := exec.Wait()
+func (x *Execution) Wait() (Results, error) {
+	<-x.done
+	return x.final, x.err
+}
+
+// store records a node's output and wakes its waiters.
+func (x *Execution) store(id ID, output any) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.results[id] = output
+	close(x.readyChan(id))
+}
+
+// finish records the outcome of the execution. Results seeded without
+// running a node (for example by WithReplay) become visible to WaitFor here.
+func (x *Execution) finish(results Results, err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for id, out := range results {
+		if _, ok := x.results[id]; !ok {
+			x.results[id] = out
+		}
+	}
+	x.final, x.err = results, err
+	close(x.done)
+}
+
+// readyChan returns the channel closed when id's result is stored. The
+// caller must hold x.mu.
+func (x *Execution) readyChan(id ID) chan struct{} {
+	ch, ok := x.ready[id]
+	if !ok {
+		ch = make(chan struct{})
+		x.ready[id] = ch
+	}
+	return ch
+}
@@ -0,0 +1,102 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecuteAsyncWaitFor(t *testing.T) {
+	release := make(chan struct{})
+	nodes := map[ID]node{
+		"config": makeNode("config", nil, func(ctx context.Context) (any, error) { return "cfg", nil }),
+		"slow": makeNode("slow", []ID{"config"}, func(ctx context.Context) (any, error) {
+			<-release
+			return "done", nil
+		}),
+	}
+
+	exec := ExecuteAsync(context.Background(), WithRegistry(nodes), DisableCache())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	out, err := exec.WaitFor(ctx, "config")
+	if err != nil || out != "cfg" {
+		t.Fatalf("WaitFor(config) = %v, %v; want cfg", out, err)
+	}
+
+	// slow is still blocked, so waiting on it times out
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	if _, err := exec.WaitFor(short, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitFor(slow) error = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	out, err = exec.WaitFor(ctx, "slow")
+	if err != nil || out != "done" {
+		t.Fatalf("WaitFor(slow) = %v, %v; want done", out, err)
+	}
+
+	results, err := exec.Wait()
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Wait() = %v, %v; want 2 results", results, err)
+	}
+}
+
+func TestExecuteAsyncWaitForErrors(t *testing.T) {
+	type tc struct {
+		id        ID
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"failed dependency": {
+			id:        "app",
+			errSubstr: "node db: boom",
+		},
+		"failed node": {
+			id:        "db",
+			errSubstr: "node db: boom",
+		},
+		"completed node of failed execution": {
+			id: "config",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := map[ID]node{
+				"config": makeNode("config", nil, func(ctx context.Context) (any, error) { return "cfg", nil }),
+				"db":     makeNode("db", []ID{"config"}, func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+				"app":    makeNode("app", []ID{"db"}, func(ctx context.Context) (any, error) { return "app", nil }),
+			}
+
+			exec := ExecuteAsync(context.Background(), WithRegistry(nodes), DisableCache())
+			_, err := exec.WaitFor(context.Background(), tt.id)
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestExecuteAsyncUnknownNode(t *testing.T) {
+	nodes := map[ID]node{
+		"config": makeNode("config", nil, func(ctx context.Context) (any, error) { return "cfg", nil }),
+	}
+
+	exec := ExecuteAsync(context.Background(), WithRegistry(nodes), DisableCache())
+	_, err := exec.WaitFor(context.Background(), "missing")
+	if err == nil || !strings.Contains(err.Error(), "node missing was not executed") {
+		t.Fatalf("error = %v, want not executed", err)
+	}
+}
@@ -2,7 +2,11 @@ package graft
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // defaultCache is the global cache used by Execute/ExecuteFor.
@@ -30,15 +34,121 @@ type Cache interface {
 	Set(ctx context.Context, id ID, value any) error
 }
 
+// TaggedCache is an optional extension of [Cache] for implementations that
+// support tag-based eviction. When the cache used by Execute/ExecuteFor
+// implements TaggedCache, the outputs of nodes with CacheTags are tagged
+// after they are stored.
+type TaggedCache interface {
+	Cache
+
+	// Tag associates a cache entry with one or more tags.
+	Tag(ctx context.Context, id ID, tags ...string) error
+
+	// Invalidate removes every entry associated with tag.
+	Invalidate(tag string) error
+
+	// InvalidateByTags removes every entry associated with any of the tags.
+	InvalidateByTags(tags ...string) error
+}
+
+// MultiCache is an optional extension of [Cache] for implementations that
+// can read and write several entries in one round trip, such as a Redis
+// cache using MGET. When the cache used by Execute/ExecuteFor implements
+// MultiCache, the cached outputs of every cacheable node in a level are
+// fetched with a single GetMulti call before the level's nodes start.
+type MultiCache interface {
+	Cache
+
+	// GetMulti retrieves the cached values for ids. Only hits are present
+	// in the returned map.
+	GetMulti(ctx context.Context, ids []ID) (map[ID]any, error)
+
+	// SetMulti stores every entry.
+	SetMulti(ctx context.Context, entries map[ID]any) error
+}
+
+// HasCache is an optional extension of [Cache] for implementations that can
+// check whether an entry exists more cheaply than fetching it, such as a
+// Redis cache using EXISTS. Use [CacheHas] to check any cache.
+type HasCache interface {
+	Cache
+
+	// Has reports whether id has a cached value.
+	Has(ctx context.Context, id ID) (bool, error)
+}
+
+// CacheHas reports whether c has a cached value for id. It uses Has when c
+// implements [HasCache] and falls back to Get otherwise.
+//
+// Example:
+//
+//	ok, err := graft.CacheHas(ctx, graft.DefaultCache(), "config")
+func CacheHas(ctx context.Context, c Cache, id ID) (bool, error) {
+	if hc, ok := c.(HasCache); ok {
+		return hc.Has(ctx, id)
+	}
+	_, ok, err := c.Get(ctx, id)
+	return ok, err
+}
+
+// StatsCache is an optional extension of [Cache] for implementations that
+// count their operations, so that hit rates can be monitored without
+// instrumenting every Get.
+type StatsCache interface {
+	Cache
+
+	// Stats returns the operation counts since the cache was created or
+	// its counters were last reset.
+	Stats() CacheStats
+}
+
+// CacheStats holds the operation counts of a [StatsCache].
+type CacheStats struct {
+	Hits    int64 // lookups that found a value
+	Misses  int64 // lookups that found nothing
+	Sets    int64 // values stored
+	Deletes int64 // entries removed by Delete, Clear or invalidation
+}
+
+// HitRate returns the fraction of lookups that were hits, or 0 if there
+// were no lookups.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// DefaultCacheStats returns the operation counts of the global cache.
+//
+// Example:
+//
+//	stats := graft.DefaultCacheStats()
+//	log.Printf("cache hit rate: %.1f%%", stats.HitRate()*100)
+func DefaultCacheStats() CacheStats {
+	return defaultCache.Stats()
+}
+
 // MemoryCache is a simple thread-safe in-memory cache.
 type MemoryCache struct {
-	mu    sync.RWMutex
-	store map[ID]any
+	mu       sync.RWMutex
+	store    map[ID]any
+	tagIndex map[string]map[ID]struct{} // tag -> tagged entries
+
+	// Operation counters (see Stats)
+	hits    atomic.Int64
+	misses  atomic.Int64
+	sets    atomic.Int64
+	deletes atomic.Int64
 }
 
 // NewMemoryCache creates a new in-memory cache.
 func NewMemoryCache() *MemoryCache {
-	return &MemoryCache{store: make(map[ID]any)}
+	return &MemoryCache{
+		store:    make(map[ID]any),
+		tagIndex: make(map[string]map[ID]struct{}),
+	}
 }
 
 // Get retrieves a value from the cache.
@@ -46,14 +156,102 @@ func (m *MemoryCache) Get(_ context.Context, id ID) (any, bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	val, ok := m.store[id]
+	m.recordLookup(ok)
 	return val, ok, nil
 }
 
+// Has reports whether id has a cached value.
+func (m *MemoryCache) Has(_ context.Context, id ID) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.store[id]
+	return ok, nil
+}
+
 // Set stores a value in the cache.
 func (m *MemoryCache) Set(_ context.Context, id ID, value any) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.store[id] = value
+	m.sets.Add(1)
+	return nil
+}
+
+// GetMulti retrieves every cached value among ids.
+func (m *MemoryCache) GetMulti(_ context.Context, ids []ID) (map[ID]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	hits := make(map[ID]any, len(ids))
+	for _, id := range ids {
+		val, ok := m.store[id]
+		if ok {
+			hits[id] = val
+		}
+		m.recordLookup(ok)
+	}
+	return hits, nil
+}
+
+// SetMulti stores every entry.
+func (m *MemoryCache) SetMulti(_ context.Context, entries map[ID]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, val := range entries {
+		m.store[id] = val
+	}
+	m.sets.Add(int64(len(entries)))
+	return nil
+}
+
+// SingleToMultiCache adapts a cache without batch operations to
+// [MultiCache] by issuing one Get or Set per entry. A cache that already
+// implements MultiCache is returned unchanged.
+//
+// The adapter only exposes the [Cache] and MultiCache methods, so optional
+// extensions such as [TaggedCache] are hidden from the engine.
+//
+// Example:
+//
+//	cache := graft.SingleToMultiCache(myRedisCache)
+//	hits, err := cache.GetMulti(ctx, []graft.ID{"config", "db"})
+func SingleToMultiCache(c Cache) MultiCache {
+	if mc, ok := c.(MultiCache); ok {
+		return mc
+	}
+	return singleToMultiCache{c}
+}
+
+// singleToMultiCache implements MultiCache on top of a plain Cache.
+type singleToMultiCache struct {
+	Cache
+}
+
+func (c singleToMultiCache) GetMulti(ctx context.Context, ids []ID) (map[ID]any, error) {
+	hits := make(map[ID]any, len(ids))
+	for _, id := range ids {
+		val, found, err := c.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", id, err)
+		}
+		if found {
+			hits[id] = val
+		}
+	}
+	return hits, nil
+}
+
+func (c singleToMultiCache) SetMulti(ctx context.Context, entries map[ID]any) error {
+	ids := make([]ID, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if err := c.Set(ctx, id, entries[id]); err != nil {
+			return fmt.Errorf("set %s: %w", id, err)
+		}
+	}
 	return nil
 }
 
@@ -62,7 +260,7 @@ func (m *MemoryCache) Delete(ids ...ID) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	for _, id := range ids {
-		delete(m.store, id)
+		m.deleteLocked(id)
 	}
 }
 
@@ -70,7 +268,169 @@ func (m *MemoryCache) Delete(ids ...ID) {
 func (m *MemoryCache) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.deletes.Add(int64(len(m.store)))
 	m.store = make(map[ID]any)
+	m.tagIndex = make(map[string]map[ID]struct{})
+}
+
+// Tag associates a cache entry with tags so it can later be evicted with
+// [MemoryCache.Invalidate] or [MemoryCache.InvalidateByTags].
+func (m *MemoryCache) Tag(_ context.Context, id ID, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tagIndex == nil {
+		m.tagIndex = make(map[string]map[ID]struct{})
+	}
+	for _, tag := range tags {
+		if m.tagIndex[tag] == nil {
+			m.tagIndex[tag] = make(map[ID]struct{})
+		}
+		m.tagIndex[tag][id] = struct{}{}
+	}
+	return nil
+}
+
+// Invalidate removes every entry associated with tag.
+//
+// Example:
+//
+//	// Evict everything derived from config after a config change event
+//	graft.DefaultCache().Invalidate("config")
+func (m *MemoryCache) Invalidate(tag string) error {
+	return m.InvalidateByTags(tag)
+}
+
+// InvalidateByTags removes every entry associated with any of the tags.
+func (m *MemoryCache) InvalidateByTags(tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tag := range tags {
+		for id := range m.tagIndex[tag] {
+			m.deleteLocked(id)
+		}
+	}
+	return nil
+}
+
+// deleteLocked removes an entry and its tag associations. Callers must hold m.mu.
+func (m *MemoryCache) deleteLocked(id ID) {
+	if _, ok := m.store[id]; ok {
+		m.deletes.Add(1)
+	}
+	delete(m.store, id)
+	for tag, ids := range m.tagIndex {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(m.tagIndex, tag)
+		}
+	}
+}
+
+// Stats returns the cache's operation counts. Get and GetMulti count a hit
+// or miss per ID; Has and Snapshot are not counted.
+//
+// Example:
+//
+//	stats := cache.Stats()
+//	fmt.Printf("%d hits, %d misses (%.0f%%)\n", stats.Hits, stats.Misses, stats.HitRate()*100)
+func (m *MemoryCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:    m.hits.Load(),
+		Misses:  m.misses.Load(),
+		Sets:    m.sets.Load(),
+		Deletes: m.deletes.Load(),
+	}
+}
+
+// ResetStats sets the cache's operation counts to zero without touching its
+// entries, starting a new measurement window.
+//
+// Example:
+//
+//	cache.ResetStats()
+//	results, err := graft.Execute(ctx, graft.WithCache(cache))
+//	log.Printf("request hit rate: %.2f", cache.Stats().HitRate())
+func (m *MemoryCache) ResetStats() {
+	m.hits.Store(0)
+	m.misses.Store(0)
+	m.sets.Store(0)
+	m.deletes.Store(0)
+}
+
+// recordLookup counts a Get of one ID as a hit or miss.
+func (m *MemoryCache) recordLookup(hit bool) {
+	if hit {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+}
+
+// Warmup pre-populates the cache by calling fn for each ID and storing the
+// result. A failing ID does not stop the others; all errors are returned
+// combined with [errors.Join]. Warmup stops early if ctx is done.
+//
+// Example:
+//
+//	err := cache.Warmup(ctx, func(id graft.ID) (any, error) {
+//	    return loadSnapshot(id)
+//	}, "config", "catalog")
+func (m *MemoryCache) Warmup(ctx context.Context, fn func(ID) (any, error), ids ...ID) error {
+	var errs []error
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		val, err := fn(id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("warmup %s: %w", id, err))
+			continue
+		}
+		if err := m.Set(ctx, id, val); err != nil {
+			errs = append(errs, fmt.Errorf("warmup %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WarmCache runs every cacheable node, with its dependencies, so that its
+// output is in the cache before the first real request. By default the
+// global registry and default cache are used; pass [WithRegistry] or
+// [WithCache] to change either.
+//
+// Each cacheable node is executed separately, so a failing node does not
+// prevent the others from being cached. All errors are returned combined
+// with [errors.Join].
+//
+// Example:
+//
+//	func main() {
+//	    if err := graft.WarmCache(ctx); err != nil {
+//	        log.Printf("cache warmup incomplete: %v", err)
+//	    }
+//	    http.ListenAndServe(addr, handler)
+//	}
+func WarmCache(ctx context.Context, opts ...Option) error {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var errs []error
+	for _, id := range SortedIDs(cfg.registry) {
+		if !cfg.registry[id].cacheable {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if _, err := executeForIDs(ctx, []ID{id}, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("warm %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Snapshot returns a copy of all cached values (useful for debugging/inspection).
@@ -84,8 +444,9 @@ func (m *MemoryCache) Snapshot() map[ID]any {
 	return cp
 }
 
-// ResetDefaultCache clears the global default cache.
-// This is primarily useful for test isolation.
+// ResetDefaultCache clears the global default cache and its operation
+// counts. This is primarily useful for test isolation.
 func ResetDefaultCache() {
 	defaultCache.Clear()
+	defaultCache.ResetStats()
 }
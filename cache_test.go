@@ -2,6 +2,9 @@ package graft
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 )
@@ -356,3 +359,574 @@ func TestNoCacheProvided(t *testing.T) {
 		t.Fatalf("expected 2 executions (no cache provided), got %d", execCount.Load())
 	}
 }
+
+func TestCacheNamespace(t *testing.T) {
+	type tc struct {
+		opts      []Option
+		wantKey   ID
+		wantExecs int32
+	}
+
+	tests := map[string]tc{
+		"no namespace uses node ID": {
+			wantKey:   "tenant_config",
+			wantExecs: 1,
+		},
+		"namespace prefixes key": {
+			opts:      []Option{WithCacheNamespace("tenant-a")},
+			wantKey:   "tenant-a/tenant_config",
+			wantExecs: 1,
+		},
+		"custom key function": {
+			opts: []Option{WithCacheKey(func(id ID) ID {
+				return "custom:" + id
+			})},
+			wantKey:   "custom:tenant_config",
+			wantExecs: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var execCount atomic.Int32
+			nodes := map[ID]node{
+				"tenant_config": {
+					id:        "tenant_config",
+					cacheable: true,
+					run: func(ctx context.Context) (any, error) {
+						execCount.Add(1)
+						return "value", nil
+					},
+				},
+			}
+
+			cache := NewMemoryCache()
+			opts := append([]Option{WithRegistry(nodes), WithCache(cache)}, tt.opts...)
+			for i := 0; i < 2; i++ {
+				if _, err := Execute(context.Background(), opts...); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if got := execCount.Load(); got != tt.wantExecs {
+				t.Errorf("executed %d times, want %d", got, tt.wantExecs)
+			}
+
+			snapshot := cache.Snapshot()
+			if len(snapshot) != 1 {
+				t.Fatalf("got %d cache entries, want 1: %v", len(snapshot), snapshot)
+			}
+			if _, ok := snapshot[tt.wantKey]; !ok {
+				t.Errorf("cache entries %v missing key %q", snapshot, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestCacheNamespaceIsolation(t *testing.T) {
+	var execCount atomic.Int32
+	nodes := map[ID]node{
+		"tenant_config": {
+			id:        "tenant_config",
+			cacheable: true,
+			run: func(ctx context.Context) (any, error) {
+				return execCount.Add(1), nil
+			},
+		},
+	}
+
+	cache := NewMemoryCache()
+	ctx := context.Background()
+
+	resultsA, err := Execute(ctx, WithRegistry(nodes), WithCache(cache), WithCacheNamespace("a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resultsB, err := Execute(ctx, WithRegistry(nodes), WithCache(cache), WithCacheNamespace("b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resultsA2, err := Execute(ctx, WithRegistry(nodes), WithCache(cache), WithCacheNamespace("a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resultsA["tenant_config"] == resultsB["tenant_config"] {
+		t.Errorf("namespaces a and b should not share entries, both got %v", resultsA["tenant_config"])
+	}
+	if resultsA["tenant_config"] != resultsA2["tenant_config"] {
+		t.Errorf("namespace a should reuse its entry, got %v then %v", resultsA["tenant_config"], resultsA2["tenant_config"])
+	}
+}
+
+func TestMemoryCacheInvalidate(t *testing.T) {
+	type tc struct {
+		invalidate func(c *MemoryCache) error
+		wantKeys   []ID
+	}
+
+	tests := map[string]tc{
+		"invalidate single tag": {
+			invalidate: func(c *MemoryCache) error { return c.Invalidate("config") },
+			wantKeys:   []ID{"db", "untagged"},
+		},
+		"invalidate by any of several tags": {
+			invalidate: func(c *MemoryCache) error { return c.InvalidateByTags("config", "storage") },
+			wantKeys:   []ID{"untagged"},
+		},
+		"unknown tag is a no-op": {
+			invalidate: func(c *MemoryCache) error { return c.Invalidate("missing") },
+			wantKeys:   []ID{"config", "db", "settings", "untagged"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewMemoryCache()
+			for _, id := range []ID{"config", "settings", "db", "untagged"} {
+				_ = c.Set(ctx, id, string(id))
+			}
+			_ = c.Tag(ctx, "config", "config")
+			_ = c.Tag(ctx, "settings", "config")
+			_ = c.Tag(ctx, "db", "storage")
+
+			if err := tt.invalidate(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			snapshot := c.Snapshot()
+			if len(snapshot) != len(tt.wantKeys) {
+				t.Errorf("got %d entries, want %d: %v", len(snapshot), len(tt.wantKeys), snapshot)
+			}
+			for _, id := range tt.wantKeys {
+				if _, ok := snapshot[id]; !ok {
+					t.Errorf("expected entry %q to remain", id)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryCacheInvalidateRemovesFromIndex(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	_ = c.Set(ctx, "a", 1)
+	_ = c.Tag(ctx, "a", "x", "y")
+
+	if err := c.Invalidate("x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Re-set without tags; the stale "y" association must not evict it
+	_ = c.Set(ctx, "a", 2)
+	if err := c.Invalidate("y"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val, found, _ := c.Get(ctx, "a"); !found || val != 2 {
+		t.Errorf("Get(a) = %v, %v; want 2, true", val, found)
+	}
+}
+
+func TestCacheTagsRecordedByEngine(t *testing.T) {
+	var execCount atomic.Int32
+	nodes := map[ID]node{
+		"config": {
+			id:        "config",
+			cacheable: true,
+			cacheTags: []string{"config"},
+			run: func(ctx context.Context) (any, error) {
+				execCount.Add(1)
+				return "value", nil
+			},
+		},
+	}
+
+	cache := NewMemoryCache()
+	opts := []Option{WithRegistry(nodes), WithCache(cache), WithCacheNamespace("tenant")}
+	for i := 0; i < 2; i++ {
+		if _, err := Execute(context.Background(), opts...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := execCount.Load(); got != 1 {
+		t.Fatalf("executed %d times before invalidation, want 1", got)
+	}
+
+	if err := cache.Invalidate("config"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Execute(context.Background(), opts...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := execCount.Load(); got != 2 {
+		t.Errorf("executed %d times after invalidation, want 2", got)
+	}
+}
+
+func TestMemoryCacheWarmup(t *testing.T) {
+	type tc struct {
+		ids       []ID
+		fail      map[ID]bool
+		want      map[ID]any
+		wantErrs  []string
+		cancelled bool
+	}
+
+	tests := map[string]tc{
+		"stores every value": {
+			ids:  []ID{"a", "b"},
+			want: map[ID]any{"a": "value-a", "b": "value-b"},
+		},
+		"failure does not stop others": {
+			ids:      []ID{"a", "b", "c"},
+			fail:     map[ID]bool{"a": true, "c": true},
+			want:     map[ID]any{"b": "value-b"},
+			wantErrs: []string{"warmup a: boom", "warmup c: boom"},
+		},
+		"cancelled context": {
+			ids:       []ID{"a"},
+			cancelled: true,
+			want:      map[ID]any{},
+			wantErrs:  []string{"context canceled"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			cache := NewMemoryCache()
+			err := cache.Warmup(ctx, func(id ID) (any, error) {
+				if tt.fail[id] {
+					return nil, errors.New("boom")
+				}
+				return "value-" + string(id), nil
+			}, tt.ids...)
+
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.wantErrs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want containing %q", err, want)
+				}
+			}
+			if got := cache.Snapshot(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cache = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	var dbRuns atomic.Int32
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: func(ctx context.Context) (any, error) {
+			return "cfg", nil
+		}},
+		"db": {id: "db", dependsOn: []ID{"config"}, cacheable: true, run: func(ctx context.Context) (any, error) {
+			dbRuns.Add(1)
+			return "db", nil
+		}},
+		"broken": {id: "broken", cacheable: true, run: func(ctx context.Context) (any, error) {
+			return nil, errors.New("unavailable")
+		}},
+		"request": {id: "request", dependsOn: []ID{"db"}, run: func(ctx context.Context) (any, error) {
+			t.Error("non-cacheable node should not run during warmup")
+			return nil, nil
+		}},
+	}
+
+	cache := NewMemoryCache()
+	err := WarmCache(context.Background(), WithRegistry(nodes), WithCache(cache))
+	if err == nil || !strings.Contains(err.Error(), "warm broken: node broken: unavailable") {
+		t.Fatalf("error = %v, want broken node failure", err)
+	}
+
+	want := map[ID]any{"config": "cfg", "db": "db"}
+	if got := cache.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("cache = %v, want %v", got, want)
+	}
+
+	// A later execution is served from the warmed cache
+	if _, err := executeForIDs(context.Background(), []ID{"db"}, WithRegistry(nodes), WithCache(cache)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := dbRuns.Load(); got != 1 {
+		t.Errorf("db ran %d times, want 1", got)
+	}
+}
+
+// singleCache is a Cache without batch operations that counts Get calls.
+type singleCache struct {
+	mem  *MemoryCache
+	gets atomic.Int32
+}
+
+func (c *singleCache) Snapshot() map[ID]any { return c.mem.Snapshot() }
+
+func (c *singleCache) Get(ctx context.Context, id ID) (any, bool, error) {
+	c.gets.Add(1)
+	return c.mem.Get(ctx, id)
+}
+
+func (c *singleCache) Set(ctx context.Context, id ID, value any) error {
+	return c.mem.Set(ctx, id, value)
+}
+
+// countingMultiCache records GetMulti batches and rejects single Gets.
+type countingMultiCache struct {
+	*MemoryCache
+	batches [][]ID
+}
+
+func (c *countingMultiCache) Get(ctx context.Context, id ID) (any, bool, error) {
+	return nil, false, errors.New("unexpected single Get")
+}
+
+func (c *countingMultiCache) GetMulti(ctx context.Context, ids []ID) (map[ID]any, error) {
+	c.batches = append(c.batches, append([]ID{}, ids...))
+	return c.MemoryCache.GetMulti(ctx, ids)
+}
+
+func TestMemoryCacheMulti(t *testing.T) {
+	type tc struct {
+		entries map[ID]any
+		ids     []ID
+		want    map[ID]any
+	}
+
+	tests := map[string]tc{
+		"hits only": {
+			entries: map[ID]any{"a": 1, "b": 2},
+			ids:     []ID{"a", "c"},
+			want:    map[ID]any{"a": 1},
+		},
+		"empty cache": {
+			ids:  []ID{"a"},
+			want: map[ID]any{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, cache := range []MultiCache{NewMemoryCache(), SingleToMultiCache(&singleCache{mem: NewMemoryCache()})} {
+				ctx := context.Background()
+				if err := cache.SetMulti(ctx, tt.entries); err != nil {
+					t.Fatalf("SetMulti error: %v", err)
+				}
+				got, err := cache.GetMulti(ctx, tt.ids)
+				if err != nil {
+					t.Fatalf("GetMulti error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%T: GetMulti = %v, want %v", cache, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSingleToMultiCacheReturnsMultiCache(t *testing.T) {
+	cache := NewMemoryCache()
+	if got := SingleToMultiCache(cache); got != MultiCache(cache) {
+		t.Errorf("SingleToMultiCache should return a MultiCache unchanged, got %T", got)
+	}
+}
+
+func TestSingleCacheReadsPerNode(t *testing.T) {
+	nodes := map[ID]node{
+		"a": {id: "a", cacheable: true, run: func(ctx context.Context) (any, error) { return 1, nil }},
+		"b": {id: "b", cacheable: true, run: func(ctx context.Context) (any, error) { return 2, nil }},
+	}
+
+	cache := &singleCache{mem: NewMemoryCache()}
+	if _, err := Execute(context.Background(), WithRegistry(nodes), WithCache(cache)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cache.gets.Load(); got != 2 {
+		t.Errorf("Get called %d times, want 2", got)
+	}
+}
+
+func TestEngineBatchesCacheReadsPerLevel(t *testing.T) {
+	var execCount atomic.Int32
+	run := func(ctx context.Context) (any, error) {
+		execCount.Add(1)
+		return "value", nil
+	}
+	nodes := map[ID]node{
+		"a":   {id: "a", cacheable: true, run: run},
+		"b":   {id: "b", cacheable: true, run: run},
+		"c":   {id: "c", run: run},
+		"app": {id: "app", dependsOn: []ID{"a", "b"}, cacheable: true, run: run},
+	}
+
+	cache := &countingMultiCache{MemoryCache: NewMemoryCache()}
+	for i := 0; i < 2; i++ {
+		if _, err := Execute(context.Background(), WithRegistry(nodes), WithCache(cache)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := execCount.Load(); got != 5 {
+		t.Errorf("executed %d nodes, want 5 (4 on the first run, only the uncacheable one on the second)", got)
+	}
+	want := [][]ID{{"a", "b"}, {"app"}, {"a", "b"}, {"app"}}
+	if !reflect.DeepEqual(cache.batches, want) {
+		t.Errorf("GetMulti batches = %v, want %v", cache.batches, want)
+	}
+}
+
+func TestCacheHas(t *testing.T) {
+	type tc struct {
+		cache    func() Cache
+		wantGets int32
+	}
+
+	tests := map[string]tc{
+		"memory cache uses Has": {
+			cache: func() Cache { return NewMemoryCache() },
+		},
+		"Has is preferred over Get": {
+			// countingMultiCache fails every Get
+			cache: func() Cache { return &countingMultiCache{MemoryCache: NewMemoryCache()} },
+		},
+		"falls back to Get": {
+			cache:    func() Cache { return &singleCache{mem: NewMemoryCache()} },
+			wantGets: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := tt.cache()
+			if err := cache.Set(ctx, "a", 1); err != nil {
+				t.Fatalf("Set error: %v", err)
+			}
+
+			for id, want := range map[ID]bool{"a": true, "b": false} {
+				got, err := CacheHas(ctx, cache, id)
+				if err != nil {
+					t.Fatalf("CacheHas(%s) error: %v", id, err)
+				}
+				if got != want {
+					t.Errorf("CacheHas(%s) = %v, want %v", id, got, want)
+				}
+			}
+
+			if sc, ok := cache.(*singleCache); ok && sc.gets.Load() != tt.wantGets {
+				t.Errorf("Get called %d times, want %d", sc.gets.Load(), tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheStats(t *testing.T) {
+	type tc struct {
+		ops      func(ctx context.Context, c *MemoryCache)
+		want     CacheStats
+		wantRate float64
+	}
+
+	tests := map[string]tc{
+		"empty": {
+			ops: func(ctx context.Context, c *MemoryCache) {},
+		},
+		"get hits and misses": {
+			ops: func(ctx context.Context, c *MemoryCache) {
+				c.Set(ctx, "a", 1)
+				c.Get(ctx, "a")
+				c.Get(ctx, "a")
+				c.Get(ctx, "a")
+				c.Get(ctx, "b")
+			},
+			want:     CacheStats{Hits: 3, Misses: 1, Sets: 1},
+			wantRate: 0.75,
+		},
+		"multi counts per ID": {
+			ops: func(ctx context.Context, c *MemoryCache) {
+				c.SetMulti(ctx, map[ID]any{"a": 1, "b": 2})
+				c.GetMulti(ctx, []ID{"a", "b", "c", "d"})
+			},
+			want:     CacheStats{Hits: 2, Misses: 2, Sets: 2},
+			wantRate: 0.5,
+		},
+		"has and snapshot are not counted": {
+			ops: func(ctx context.Context, c *MemoryCache) {
+				c.Has(ctx, "a")
+				c.Snapshot()
+			},
+		},
+		"deletes count removed entries": {
+			ops: func(ctx context.Context, c *MemoryCache) {
+				c.SetMulti(ctx, map[ID]any{"a": 1, "b": 2, "c": 3, "d": 4})
+				c.Tag(ctx, "a", "t")
+				c.Invalidate("t")
+				c.Delete("b", "missing")
+				c.Clear()
+			},
+			want: CacheStats{Sets: 4, Deletes: 4},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewMemoryCache()
+			tt.ops(context.Background(), c)
+			got := c.Stats()
+			if got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+			if rate := got.HitRate(); rate != tt.wantRate {
+				t.Errorf("HitRate() = %v, want %v", rate, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheResetStats(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	c.Set(ctx, "a", 1)
+	c.Get(ctx, "a")
+
+	c.ResetStats()
+	if got := c.Stats(); got != (CacheStats{}) {
+		t.Errorf("Stats() after ResetStats = %+v, want zero", got)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Error("ResetStats should not remove entries")
+	}
+}
+
+func TestDefaultCacheStats(t *testing.T) {
+	ResetRegistry()
+	ResetDefaultCache()
+	defer ResetRegistry()
+	defer ResetDefaultCache()
+
+	Register(Node[int]{
+		ID:        "cached",
+		Cacheable: true,
+		Run:       func(ctx context.Context) (int, error) { return 1, nil },
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var _ StatsCache = DefaultCache()
+	want := CacheStats{Hits: 2, Misses: 1, Sets: 1}
+	if got := DefaultCacheStats(); got != want {
+		t.Errorf("DefaultCacheStats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+// Package chi provides graft middleware for github.com/go-chi/chi routers.
+//
+// Chi middleware is plain net/http middleware, so this package builds on
+// graft/httpserver: the results live in the request context, where
+// handlers read them with [Results] or [Result]. Chi's RouteContext only
+// holds routing state and has no place for other values.
+//
+// Example:
+//
+//	r := chi.NewRouter()
+//	r.With(graftchi.Middleware(httpserver.For[user.Output]())).Get("/user/{id}",
+//	    func(w http.ResponseWriter, r *http.Request) {
+//	        u, err := graftchi.Result[user.Output](r)
+//	        if err != nil {
+//	            http.Error(w, err.Error(), http.StatusInternalServerError)
+//	            return
+//	        }
+//	        json.NewEncoder(w).Encode(u)
+//	    })
+package chi
+
+import (
+	"net/http"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/httpserver"
+)
+
+// Middleware runs the graph with run on every request and stores the
+// results for the handlers behind it. It can be passed to chi's Use, With
+// and Group. See [httpserver.Middleware] for error handling.
+func Middleware(run httpserver.Runner) func(http.Handler) http.Handler {
+	return httpserver.Middleware(run)
+}
+
+// Results returns the graph results stored by [Middleware], or nil if the
+// request did not pass through it.
+func Results(r *http.Request) graft.Results {
+	return httpserver.FromContext(r.Context())
+}
+
+// Result retrieves a node's output from the results stored by
+// [Middleware] with type assertion. See [graft.Result].
+func Result[T any](r *http.Request) (T, error) {
+	return httpserver.ResultFromContext[T](r.Context())
+}
@@ -0,0 +1,102 @@
+package chi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/httpserver"
+)
+
+type userOutput struct{ Name string }
+type failingOutput struct{}
+
+// registerNodes registers one node that succeeds and one that fails. How
+// the graph is executed is covered by the httpserver tests; these only check
+// what the adapter adds.
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[userOutput]{
+		ID:  "user",
+		Run: func(ctx context.Context) (userOutput, error) { return userOutput{Name: "alice"}, nil },
+	})
+	graft.Register(graft.Node[failingOutput]{
+		ID:  "failing",
+		Run: func(ctx context.Context) (failingOutput, error) { return failingOutput{}, errors.New("boom") },
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	type tc struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}
+
+	tests := map[string]tc{
+		"results available to routed handler": {
+			path:       "/user/1",
+			wantStatus: http.StatusOK,
+			wantBody:   "1:alice:1",
+		},
+		"execution error returns 500": {
+			path:       "/failing",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "graft: node failing: boom",
+		},
+		"routes without the middleware have no results": {
+			path:       "/plain",
+			wantStatus: http.StatusOK,
+			wantBody:   "results=0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			r := chi.NewRouter()
+			r.With(Middleware(httpserver.For[userOutput]())).Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+				u, err := Result[userOutput](r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				io.WriteString(w, chi.URLParam(r, "id")+":"+u.Name+":")
+				io.WriteString(w, strconv.Itoa(len(Results(r))))
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(Middleware(httpserver.For[failingOutput]()))
+				r.Get("/failing", func(w http.ResponseWriter, r *http.Request) {
+					t.Error("handler called after a failed execution")
+				})
+			})
+			r.Get("/plain", func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "results="+strconv.Itoa(len(Results(r))))
+				if _, err := Result[userOutput](r); err == nil {
+					t.Error("Result without the middleware should fail")
+				}
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
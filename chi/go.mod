@@ -0,0 +1,16 @@
+module github.com/grindlemire/graft/chi
+
+go 1.25.1
+
+replace github.com/grindlemire/graft => ../
+
+require (
+	github.com/go-chi/chi/v5 v5.3.1
+	github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
+)
+
+require (
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
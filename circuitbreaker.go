@@ -0,0 +1,201 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for a node whose circuit breaker is open when
+// there is neither a last known good output nor a Fallback to serve.
+var ErrCircuitOpen = errors.New("graft: circuit breaker open")
+
+// CircuitBreakerPolicy configures a circuit breaker for a node. See
+// [WithCircuitBreaker].
+type CircuitBreakerPolicy struct {
+	// MaxFailures is the number of consecutive Run failures that opens the
+	// circuit. Zero or negative disables the breaker.
+	MaxFailures int
+
+	// ResetAfter is how long the circuit stays open before a single trial
+	// run is allowed (half-open). A successful trial closes the circuit; a
+	// failed one reopens it for another ResetAfter.
+	ResetAfter time.Duration
+
+	// Fallback produces the node's output while the circuit is open and no
+	// successful output has been recorded yet. Optional.
+	Fallback func() (any, error)
+}
+
+// WithCircuitBreaker protects the node with the given ID with a circuit
+// breaker. After policy.MaxFailures consecutive failures the node's Run
+// function is no longer called; instead the node resolves to its last
+// successful output, or to policy.Fallback if it has never succeeded, or
+// fails with [ErrCircuitOpen].
+//
+// Breaker state is kept per node ID for the whole process, so it carries
+// across Execute calls, like the Node.Concurrency limit. It is cleared by
+// [ResetRegistry] and [ResetCircuitBreakers], and for a single node by
+// [Reload]. Outputs served while the circuit is open are not written to the
+// cache. A panic in Run counts as a failure.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.WithCircuitBreaker("pricing", graft.CircuitBreakerPolicy{
+//	        MaxFailures: 5,
+//	        ResetAfter:  30 * time.Second,
+//	        Fallback:    func() (any, error) { return pricing.Output{}, nil },
+//	    }),
+//	)
+func WithCircuitBreaker(id ID, policy CircuitBreakerPolicy) Option {
+	return func(c *config) {
+		if c.circuitBreakers == nil {
+			c.circuitBreakers = make(map[ID]CircuitBreakerPolicy)
+		}
+		c.circuitBreakers[id] = policy
+	}
+}
+
+// circuitState is the state of a circuit breaker.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker tracks failures and the last good output for one node.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+
+	lastGood    any
+	hasLastGood bool
+}
+
+// circuitBreakerStore holds a circuit breaker per node ID, shared by all
+// executions in the process.
+type circuitBreakerStore struct {
+	breakers sync.Map // ID -> *circuitBreaker
+}
+
+// get returns the breaker for id, creating it on first use.
+func (s *circuitBreakerStore) get(id ID) *circuitBreaker {
+	v, _ := s.breakers.LoadOrStore(id, &circuitBreaker{})
+	return v.(*circuitBreaker)
+}
+
+// reset drops the breaker for id, so its next call starts closed.
+func (s *circuitBreakerStore) reset(id ID) {
+	s.breakers.Delete(id)
+}
+
+// clear drops every breaker.
+func (s *circuitBreakerStore) clear() {
+	s.breakers.Range(func(k, _ any) bool {
+		s.breakers.Delete(k)
+		return true
+	})
+}
+
+// circuitBreakers is the process-wide breaker state.
+var circuitBreakers circuitBreakerStore
+
+// ResetCircuitBreakers clears the circuit breaker state of every node,
+// closing all circuits and forgetting their last good outputs. This is
+// primarily useful for test isolation.
+func ResetCircuitBreakers() {
+	circuitBreakers.clear()
+}
+
+// errRunPanicked is recorded as the outcome of a call whose fn panicked.
+var errRunPanicked = errors.New("graft: run panicked")
+
+// call runs fn unless the circuit is open, in which case it serves the
+// last good output or the fallback. served reports whether the output came
+// from the breaker rather than fn.
+func (cb *circuitBreaker) call(ctx context.Context, policy CircuitBreakerPolicy, fn func(ctx context.Context) (any, error)) (output any, served bool, err error) {
+	if policy.MaxFailures <= 0 {
+		output, err = fn(ctx)
+		return output, false, err
+	}
+
+	if !cb.allow(policy) {
+		output, err = cb.fallback(policy)
+		return output, true, err
+	}
+
+	// Record a panic as a failure before it propagates; otherwise a
+	// panicking trial would leave the circuit half-open for good
+	completed := false
+	defer func() {
+		if !completed {
+			cb.record(policy, nil, errRunPanicked)
+		}
+	}()
+	output, err = fn(ctx)
+	completed = true
+	cb.record(policy, output, err)
+	return output, false, err
+}
+
+// allow reports whether fn may be called, moving an expired open circuit to
+// half-open so that exactly one trial run goes through.
+func (cb *circuitBreaker) allow(policy CircuitBreakerPolicy) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < policy.ResetAfter {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// A trial run is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a call.
+func (cb *circuitBreaker) record(policy CircuitBreakerPolicy, output any, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err == nil {
+		cb.state = circuitClosed
+		cb.failures = 0
+		cb.lastGood, cb.hasLastGood = output, true
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= policy.MaxFailures {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// fallback returns the output to serve while the circuit is open.
+func (cb *circuitBreaker) fallback(policy CircuitBreakerPolicy) (any, error) {
+	cb.mu.Lock()
+	lastGood, ok := cb.lastGood, cb.hasLastGood
+	cb.mu.Unlock()
+
+	switch {
+	case ok:
+		return lastGood, nil
+	case policy.Fallback != nil:
+		return policy.Fallback()
+	default:
+		return nil, ErrCircuitOpen
+	}
+}
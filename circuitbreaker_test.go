@@ -0,0 +1,233 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	type outcome struct {
+		output any
+		err    error
+	}
+
+	type tc struct {
+		policy    CircuitBreakerPolicy
+		runs      []error // result of each call to Run, in order; nil means success
+		sleep     time.Duration
+		want      []outcome
+		wantCalls int32
+	}
+
+	boom := errors.New("boom")
+
+	tests := map[string]tc{
+		"opens after consecutive failures": {
+			policy:    CircuitBreakerPolicy{MaxFailures: 2, ResetAfter: time.Hour},
+			runs:      []error{boom, boom, nil},
+			want:      []outcome{{err: boom}, {err: boom}, {err: ErrCircuitOpen}},
+			wantCalls: 2,
+		},
+		"serves last good output while open": {
+			policy:    CircuitBreakerPolicy{MaxFailures: 1, ResetAfter: time.Hour},
+			runs:      []error{nil, boom, nil},
+			want:      []outcome{{output: 1}, {err: boom}, {output: 1}},
+			wantCalls: 2,
+		},
+		"uses fallback without a good output": {
+			policy: CircuitBreakerPolicy{
+				MaxFailures: 1,
+				ResetAfter:  time.Hour,
+				Fallback:    func() (any, error) { return "fallback", nil },
+			},
+			runs:      []error{boom, nil},
+			want:      []outcome{{err: boom}, {output: "fallback"}},
+			wantCalls: 1,
+		},
+		"success resets the failure count": {
+			policy:    CircuitBreakerPolicy{MaxFailures: 2, ResetAfter: time.Hour},
+			runs:      []error{boom, nil, boom, nil},
+			want:      []outcome{{err: boom}, {output: 2}, {err: boom}, {output: 4}},
+			wantCalls: 4,
+		},
+		"half-open trial closes the circuit": {
+			policy:    CircuitBreakerPolicy{MaxFailures: 1, ResetAfter: 10 * time.Millisecond},
+			runs:      []error{boom, nil, nil},
+			sleep:     20 * time.Millisecond,
+			want:      []outcome{{err: boom}, {output: 2}, {output: 3}},
+			wantCalls: 3,
+		},
+		"disabled with zero max failures": {
+			policy:    CircuitBreakerPolicy{},
+			runs:      []error{boom, boom, boom},
+			want:      []outcome{{err: boom}, {err: boom}, {err: boom}},
+			wantCalls: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Breaker state is process-wide, so each case needs its own ID
+			id := ID("breaker-" + name)
+			t.Cleanup(ResetCircuitBreakers)
+			var calls atomic.Int32
+			nodes := map[ID]node{
+				id: makeNode(id, nil, func(ctx context.Context) (any, error) {
+					n := calls.Add(1)
+					if err := tt.runs[n-1]; err != nil {
+						return nil, err
+					}
+					return int(n), nil
+				}),
+			}
+
+			var got []outcome
+			for range tt.want {
+				results, err := Execute(context.Background(),
+					WithRegistry(nodes),
+					DisableCache(),
+					WithCircuitBreaker(id, tt.policy),
+				)
+				if err != nil {
+					got = append(got, outcome{err: errors.Unwrap(err)})
+				} else {
+					got = append(got, outcome{output: results[id]})
+				}
+				if len(got) == 1 {
+					time.Sleep(tt.sleep)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outcomes = %v, want %v", fmt.Sprint(got), fmt.Sprint(tt.want))
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("Run called %d times, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerOutputNotCached(t *testing.T) {
+	id := ID("breaker-not-cached")
+	t.Cleanup(ResetCircuitBreakers)
+	nodes := map[ID]node{
+		id: {id: id, cacheable: true, run: func(ctx context.Context) (any, error) {
+			return nil, errors.New("down")
+		}},
+	}
+	cache := NewMemoryCache()
+	opts := []Option{
+		WithRegistry(nodes),
+		WithCache(cache),
+		WithCircuitBreaker(id, CircuitBreakerPolicy{
+			MaxFailures: 1,
+			ResetAfter:  time.Hour,
+			Fallback:    func() (any, error) { return "fallback", nil },
+		}),
+	}
+
+	if _, err := Execute(context.Background(), opts...); err == nil {
+		t.Fatal("expected first execution to fail")
+	}
+	results, err := Execute(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[id] != "fallback" {
+		t.Errorf("results[%s] = %v, want fallback", id, results[id])
+	}
+	if _, found, _ := cache.Get(context.Background(), id); found {
+		t.Error("fallback output should not be cached")
+	}
+}
+
+func TestCircuitBreakerPanickingTrial(t *testing.T) {
+	id := ID("breaker-panic")
+	t.Cleanup(ResetCircuitBreakers)
+	var calls atomic.Int32
+	nodes := map[ID]node{
+		id: makeNode(id, nil, func(ctx context.Context) (any, error) {
+			switch calls.Add(1) {
+			case 1:
+				return nil, errors.New("down")
+			case 2:
+				panic("trial panicked")
+			default:
+				return "ok", nil
+			}
+		}),
+	}
+	opts := []Option{
+		WithRegistry(nodes),
+		DisableCache(),
+		WithPanicRecovery(),
+		WithCircuitBreaker(id, CircuitBreakerPolicy{MaxFailures: 1, ResetAfter: 10 * time.Millisecond}),
+	}
+
+	// Open the circuit, then let the half-open trial panic
+	for i, want := range []string{"down", "trial panicked"} {
+		if _, err := Execute(context.Background(), opts...); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("execution %d: error = %v, want one containing %q", i, err, want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// The panic reopened the circuit, so the next trial runs again
+	results, err := Execute(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[id] != "ok" {
+		t.Errorf("results[%s] = %v, want ok", id, results[id])
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Run called %d times, want 3", calls.Load())
+	}
+}
+
+func TestCircuitBreakerClearedByReload(t *testing.T) {
+	resetGlobalState()
+	t.Cleanup(resetGlobalState)
+
+	Register(Node[reloadTestConfig]{
+		ID:  "config",
+		Run: func(ctx context.Context) (reloadTestConfig, error) { return reloadTestConfig{Env: "original"}, nil },
+	})
+	breaker := WithCircuitBreaker("config", CircuitBreakerPolicy{MaxFailures: 1, ResetAfter: time.Hour})
+
+	// Record a last good output, then open the circuit
+	if _, _, err := ExecuteFor[reloadTestConfig](context.Background(), DisableCache(), breaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Reload(Node[reloadTestConfig]{
+		ID:  "config",
+		Run: func(ctx context.Context) (reloadTestConfig, error) { return reloadTestConfig{}, errors.New("down") },
+	}); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if _, _, err := ExecuteFor[reloadTestConfig](context.Background(), DisableCache(), breaker); err == nil {
+		t.Fatal("expected the failing node to fail")
+	}
+
+	// A reload with a new output type must not be served the old one
+	if err := Reload(Node[reloadTestConfigV2]{
+		ID:  "config",
+		Run: func(ctx context.Context) (reloadTestConfigV2, error) { return reloadTestConfigV2{Env: "v2"}, nil },
+	}); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	cfg, _, err := ExecuteFor[reloadTestConfigV2](context.Background(), DisableCache(), breaker)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Env != "v2" {
+		t.Errorf("Env = %q, want v2", cfg.Env)
+	}
+}
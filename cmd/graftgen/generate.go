@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	graftPath   = "github.com/grindlemire/graft"
+	genFileName = "graft_gen.go"
+	directive   = "//graft:node"
+)
+
+// nodeFunc is a function marked with the //graft:node directive.
+type nodeFunc struct {
+	name    string
+	output  types.Type
+	withCtx bool
+	deps    []types.Type // parameter types after the optional context
+}
+
+// generate returns the source of graft_gen.go for pkg, or nil if pkg has
+// no functions marked with the //graft:node directive.
+func generate(pkg *packages.Package) ([]byte, error) {
+	var funcs []nodeFunc
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !hasDirective(fd.Doc) {
+				continue
+			}
+			obj, _ := pkg.TypesInfo.Defs[fd.Name].(*types.Func)
+			if obj == nil {
+				continue
+			}
+			fn, err := parseNodeFunc(pkg.Types, obj)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pkg.Fset.Position(fd.Pos()), err)
+			}
+			funcs = append(funcs, fn)
+		}
+	}
+	if len(funcs) == 0 {
+		return nil, nil
+	}
+	if len(funcs) > 1 {
+		return nil, fmt.Errorf("package %s: %d functions marked %s, want at most one per package", pkg.PkgPath, len(funcs), directive)
+	}
+	if !hasIDConst(pkg.Types) {
+		return nil, fmt.Errorf("package %s: %s requires a graft.ID constant named ID", pkg.PkgPath, directive)
+	}
+
+	return render(pkg.Types, funcs[0])
+}
+
+// hasDirective reports whether doc contains the //graft:node directive.
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNodeFunc checks that fn has the shape
+// func([context.Context,] deps...) (Output, error) and returns its parts.
+func parseNodeFunc(pkg *types.Package, fn *types.Func) (nodeFunc, error) {
+	sig := fn.Type().(*types.Signature)
+	if sig.TypeParams() != nil {
+		return nodeFunc{}, fmt.Errorf("%s: generic functions are not supported", fn.Name())
+	}
+	if sig.Variadic() {
+		return nodeFunc{}, fmt.Errorf("%s: variadic functions are not supported", fn.Name())
+	}
+
+	res := sig.Results()
+	if res.Len() != 2 || !isError(res.At(1).Type()) {
+		return nodeFunc{}, fmt.Errorf("%s: must return (output, error)", fn.Name())
+	}
+	nf := nodeFunc{name: fn.Name(), output: res.At(0).Type()}
+
+	params := sig.Params()
+	seen := make(map[*types.Package]bool)
+	for i := 0; i < params.Len(); i++ {
+		t := params.At(i).Type()
+		if i == 0 && isContext(t) {
+			nf.withCtx = true
+			continue
+		}
+		depPkg := typePackage(t)
+		if depPkg == nil || depPkg == pkg || !hasIDConst(depPkg) {
+			return nodeFunc{}, fmt.Errorf("%s: parameter %s has type %s, which is not declared by a node package with a graft.ID constant named ID",
+				fn.Name(), params.At(i).Name(), t)
+		}
+		if seen[depPkg] {
+			return nodeFunc{}, fmt.Errorf("%s: more than one parameter from node package %s", fn.Name(), depPkg.Path())
+		}
+		seen[depPkg] = true
+		nf.deps = append(nf.deps, t)
+	}
+	return nf, nil
+}
+
+// render produces the formatted source registering fn as a node.
+func render(pkg *types.Package, fn nodeFunc) ([]byte, error) {
+	imports := newImportSet(pkg)
+	imports.add(graftPath, "graft")
+	imports.add("context", "context")
+	for _, dep := range fn.deps {
+		dp := typePackage(dep)
+		imports.add(dp.Path(), dp.Name())
+	}
+	typeStr := func(t types.Type) string { return types.TypeString(t, imports.qualifier) }
+
+	// Resolve every name before writing the import block, since qualifying
+	// a type may add imports
+	output := typeStr(fn.output)
+	depTypes := make([]string, len(fn.deps))
+	depIDs := make([]string, len(fn.deps))
+	for i, dep := range fn.deps {
+		depTypes[i] = typeStr(dep)
+		depIDs[i] = imports.names[typePackage(dep).Path()] + ".ID"
+	}
+	graft := imports.names[graftPath]
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by graftgen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name())
+	b.WriteString("import (\n")
+	specs := imports.sorted()
+	for i, imp := range specs {
+		// Separate standard library imports from the rest, as goimports does
+		if i > 0 && isStdlib(specs[i-1].path) && !isStdlib(imp.path) {
+			b.WriteString("\n")
+		}
+		if imp.name != "" {
+			fmt.Fprintf(&b, "%s ", imp.name)
+		}
+		fmt.Fprintf(&b, "%q\n", imp.path)
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "func init() {\n%s.Register(%s.Node[%s]{\n", graft, graft, output)
+	fmt.Fprintf(&b, "ID: ID,\nDependsOn: []%s.ID{%s},\n", graft, strings.Join(depIDs, ", "))
+	fmt.Fprintf(&b, "Run: func(ctx %s.Context) (%s, error) {\n", imports.names["context"], output)
+
+	args := make([]string, 0, len(fn.deps)+1)
+	if fn.withCtx {
+		args = append(args, "ctx")
+	}
+	for i, dep := range depTypes {
+		v := fmt.Sprintf("dep%d", i)
+		fmt.Fprintf(&b, "%s, err := %s.Dep[%s](ctx)\nif err != nil {\nvar zero %s\nreturn zero, err\n}\n", v, graft, dep, output)
+		args = append(args, v)
+	}
+	fmt.Fprintf(&b, "return %s(%s)\n", fn.name, strings.Join(args, ", "))
+	b.WriteString("},\n})\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// importSet assigns each imported package a unique name in the generated file.
+type importSet struct {
+	pkg     *types.Package
+	names   map[string]string // path -> name
+	renamed map[string]bool   // paths whose name differs from the package name
+	used    map[string]bool
+}
+
+type importSpec struct {
+	name string
+	path string
+}
+
+func newImportSet(pkg *types.Package) *importSet {
+	used := make(map[string]bool)
+	// Avoid shadowing identifiers declared by the package itself
+	for _, name := range pkg.Scope().Names() {
+		used[name] = true
+	}
+	return &importSet{pkg: pkg, names: make(map[string]string), renamed: make(map[string]bool), used: used}
+}
+
+func (s *importSet) add(path, name string) {
+	if _, ok := s.names[path]; ok {
+		return
+	}
+	unique := name
+	for i := 2; s.used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	s.used[unique] = true
+	s.names[path] = unique
+	s.renamed[path] = unique != name
+}
+
+func (s *importSet) qualifier(p *types.Package) string {
+	if p == s.pkg {
+		return ""
+	}
+	s.add(p.Path(), p.Name())
+	return s.names[p.Path()]
+}
+
+// sorted returns the imports with the standard library first.
+func (s *importSet) sorted() []importSpec {
+	specs := make([]importSpec, 0, len(s.names))
+	for path, name := range s.names {
+		if !s.renamed[path] {
+			name = ""
+		}
+		specs = append(specs, importSpec{name: name, path: path})
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if si, sj := isStdlib(specs[i].path), isStdlib(specs[j].path); si != sj {
+			return si
+		}
+		return specs[i].path < specs[j].path
+	})
+	return specs
+}
+
+// typePackage returns the package declaring the named type t or *t.
+func typePackage(t types.Type) *types.Package {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := types.Unalias(t).(*types.Named); ok {
+		return n.Obj().Pkg()
+	}
+	return nil
+}
+
+// hasIDConst reports whether pkg declares a constant ID of type graft.ID.
+func hasIDConst(pkg *types.Package) bool {
+	c, ok := pkg.Scope().Lookup("ID").(*types.Const)
+	if !ok {
+		return false
+	}
+	n, ok := types.Unalias(c.Type()).(*types.Named)
+	return ok && n.Obj().Name() == "ID" && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == graftPath
+}
+
+// isStdlib reports whether path looks like a standard library import path.
+func isStdlib(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func isContext(t types.Type) bool {
+	n, ok := types.Unalias(t).(*types.Named)
+	return ok && n.Obj().Name() == "Context" && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "context"
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
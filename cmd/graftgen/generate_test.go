@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	type tc struct {
+		pkg       string
+		want      string
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"registers marked function": {
+			pkg: "./testdata/nodes/app",
+			want: `// Code generated by graftgen. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/config"
+	"github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/db"
+)
+
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID:        ID,
+		DependsOn: []graft.ID{config.ID, db.ID},
+		Run: func(ctx context.Context) (Output, error) {
+			dep0, err := graft.Dep[config.Output](ctx)
+			if err != nil {
+				var zero Output
+				return zero, err
+			}
+			dep1, err := graft.Dep[db.Output](ctx)
+			if err != nil {
+				var zero Output
+				return zero, err
+			}
+			return build(ctx, dep0, dep1)
+		},
+	})
+}
+`,
+		},
+		"no marked functions": {
+			pkg: "./testdata/nodes/db",
+		},
+		"missing ID constant": {
+			pkg:       "./testdata/nodes/noid",
+			errSubstr: "requires a graft.ID constant named ID",
+		},
+		"parameter is not a node output": {
+			pkg:       "./testdata/nodes/badsig",
+			errSubstr: "parameter name has type string",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pkgs, err := loadPackages("", tt.pkg)
+			if err != nil {
+				t.Fatalf("loading %s: %v", tt.pkg, err)
+			}
+
+			src, err := generate(pkgs[0])
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(src) != tt.want {
+				t.Errorf("generated source mismatch\ngot:\n%s\nwant:\n%s", src, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Command graftgen generates graft.Register boilerplate for plain functions.
+//
+// Mark a function with a //graft:node directive. Its parameters are the
+// outputs of the nodes it depends on, optionally preceded by a
+// context.Context, and it returns the package's node output and an error:
+//
+//	// build assembles the application.
+//	//
+//	//graft:node
+//	func build(cfg config.Output, db db.Output) (Output, error) {
+//	    ...
+//	}
+//
+// For each package with marked functions, graftgen writes graft_gen.go
+// containing the graft.Register call. The node ID is the package's ID
+// constant, and each dependency ID is the ID constant of the package that
+// declares the parameter's type, following the one-node-per-package layout:
+//
+//	func init() {
+//	    graft.Register(graft.Node[Output]{
+//	        ID:        ID,
+//	        DependsOn: []graft.ID{config.ID, db.ID},
+//	        Run: func(ctx context.Context) (Output, error) {
+//	            ...
+//	            return build(dep0, dep1)
+//	        },
+//	    })
+//	}
+//
+// Usage:
+//
+//	graftgen [packages]
+//
+// Packages default to ./... and are resolved from the current directory, so
+// graftgen works well as a //go:generate directive.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: graftgen [packages]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	if err := run(patterns); err != nil {
+		fmt.Fprintln(os.Stderr, "graftgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates graft_gen.go for every package matching patterns.
+func run(patterns []string) error {
+	pkgs, err := loadPackages("", patterns...)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		src, err := generate(pkg)
+		if err != nil {
+			return err
+		}
+		if src == nil || len(pkg.GoFiles) == 0 {
+			continue
+		}
+		out := filepath.Join(filepath.Dir(pkg.GoFiles[0]), genFileName)
+		if err := os.WriteFile(out, src, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", out)
+	}
+	return nil
+}
+
+// loadPackages loads the syntax and types of the packages matching patterns.
+func loadPackages(dir string, patterns ...string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedImports |
+			packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("package errors: %v", pkg.Errors[0])
+		}
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/config"
+	"github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/db"
+)
+
+const ID graft.ID = "app"
+
+type Output struct {
+	Name string
+}
+
+// build assembles the application.
+//
+//graft:node
+func build(ctx context.Context, cfg config.Output, db db.Output) (Output, error) {
+	return Output{Name: cfg.DSN + db.DSN}, nil
+}
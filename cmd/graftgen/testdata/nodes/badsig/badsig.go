@@ -0,0 +1,12 @@
+package badsig
+
+import "github.com/grindlemire/graft"
+
+const ID graft.ID = "badsig"
+
+type Output struct{}
+
+//graft:node
+func build(name string) (Output, error) {
+	return Output{}, nil
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+const ID graft.ID = "config"
+
+type Output struct {
+	DSN string
+}
+
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID:  ID,
+		Run: func(ctx context.Context) (Output, error) { return Output{DSN: "postgres://"}, nil },
+	})
+}
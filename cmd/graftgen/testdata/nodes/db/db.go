@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/config"
+)
+
+const ID graft.ID = "db"
+
+type Output struct {
+	DSN string
+}
+
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID:        ID,
+		DependsOn: []graft.ID{config.ID},
+		Run: func(ctx context.Context) (Output, error) {
+			cfg, err := graft.Dep[config.Output](ctx)
+			if err != nil {
+				return Output{}, err
+			}
+			return Output{DSN: cfg.DSN}, nil
+		},
+	})
+}
@@ -0,0 +1,10 @@
+package noid
+
+import "github.com/grindlemire/graft/cmd/graftgen/testdata/nodes/config"
+
+type Output struct{}
+
+//graft:node
+func build(cfg config.Output) (Output, error) {
+	return Output{}, nil
+}
@@ -0,0 +1,139 @@
+// Package cobra provides github.com/spf13/cobra commands for CLI tools
+// built on graft: commands that execute part of the graph, and commands
+// that print the graph.
+//
+// Example:
+//
+//	root := &cobra.Command{Use: "app"}
+//	root.AddCommand(
+//	    graftcobra.NodeCommand("report", "Build the daily report", "report"),
+//	    graftcobra.GraphCommand(),
+//	    graftcobra.MermaidCommand(),
+//	)
+//	root.Execute()
+package cobra
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grindlemire/graft"
+	"github.com/spf13/cobra"
+)
+
+// NodeCommand returns a command that executes targets and their transitive
+// dependencies in the global registry, then prints the targets' outputs to
+// the command's output as an indented JSON object keyed by node ID.
+// Outputs are encoded with encoding/json, so json struct tags apply.
+//
+// Example:
+//
+//	root.AddCommand(graftcobra.NodeCommand("users", "List active users", "users"))
+//
+//	$ app users
+//	{
+//	  "users": [
+//	    "alice"
+//	  ]
+//	}
+func NodeCommand(use, short string, targets ...graft.ID) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			needed, err := withDeps(targets)
+			if err != nil {
+				return err
+			}
+			registry := graft.Registry()
+			for id := range registry {
+				if !needed[id] {
+					delete(registry, id)
+				}
+			}
+			results, err := graft.Execute(cmd.Context(), graft.WithRegistry(registry))
+			if err != nil {
+				return err
+			}
+
+			out := make(map[graft.ID]any, len(targets))
+			for _, id := range targets {
+				out[id] = results[id]
+			}
+			data, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encode results: %w", err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return err
+		},
+	}
+}
+
+// GraphCommand returns a "graph" command that prints the node graph with
+// [graft.PrintGraph].
+//
+// Example:
+//
+//	root.AddCommand(graftcobra.GraphCommand())
+func GraphCommand(opts ...graft.Option) *cobra.Command {
+	return &cobra.Command{
+		Use:   "graph",
+		Short: "Print the node dependency graph",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return graft.PrintGraph(cmd.OutOrStdout(), opts...)
+		},
+	}
+}
+
+// MermaidCommand returns a "mermaid" command that prints the node graph as
+// a Mermaid diagram with [graft.PrintMermaid].
+//
+// Example:
+//
+//	root.AddCommand(graftcobra.MermaidCommand())
+func MermaidCommand(opts ...graft.Option) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mermaid",
+		Short: "Print the node dependency graph as a Mermaid diagram",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return graft.PrintMermaid(cmd.OutOrStdout(), opts...)
+		},
+	}
+}
+
+// withDeps returns targets and their transitive dependencies in the global
+// registry.
+func withDeps(targets []graft.ID) (map[graft.ID]bool, error) {
+	deps := map[graft.ID][]graft.ID{}
+	for _, n := range graft.ListNodes() {
+		deps[n.ID] = n.DependsOn
+	}
+
+	needed := map[graft.ID]bool{}
+	var visit func(id graft.ID) error
+	visit = func(id graft.ID) error {
+		if needed[id] {
+			return nil
+		}
+		if _, ok := deps[id]; !ok {
+			return fmt.Errorf("unknown node: %s", id)
+		}
+		needed[id] = true
+		for _, dep := range deps[id] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range targets {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return needed, nil
+}
@@ -0,0 +1,137 @@
+package cobra
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/spf13/cobra"
+)
+
+type configOutput struct {
+	Env string `json:"env"`
+}
+
+type usersOutput []string
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+	t.Cleanup(graft.ResetDefaultCache)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{Env: "prod"}, nil },
+	})
+	graft.Register(graft.Node[usersOutput]{
+		ID:        "users",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (usersOutput, error) {
+			return usersOutput{"alice", "bob"}, nil
+		},
+	})
+	graft.Register(graft.Node[int]{
+		ID:  "unrelated",
+		Run: func(ctx context.Context) (int, error) { return 0, errors.New("unrelated node ran") },
+	})
+}
+
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(
+		NodeCommand("users", "List users", "users"),
+		NodeCommand("all", "Config and users", "users", "config"),
+		NodeCommand("broken", "Unknown target", "missing"),
+		GraphCommand(),
+		MermaidCommand(),
+	)
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestNodeCommand(t *testing.T) {
+	type tc struct {
+		args    []string
+		want    string
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"single target": {
+			args: []string{"users"},
+			want: "{\n  \"users\": [\n    \"alice\",\n    \"bob\"\n  ]\n}\n",
+		},
+		"several targets": {
+			args: []string{"all"},
+			want: "{\n  \"config\": {\n    \"env\": \"prod\"\n  },\n  \"users\": [\n    \"alice\",\n    \"bob\"\n  ]\n}\n",
+		},
+		"unknown target": {
+			args:    []string{"broken"},
+			wantErr: "unknown node: missing",
+		},
+		"arguments rejected": {
+			args:    []string{"users", "extra"},
+			wantErr: "unknown command",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			got, err := run(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphCommands(t *testing.T) {
+	type tc struct {
+		args   []string
+		sprint func(opts ...graft.Option) (string, error)
+	}
+
+	tests := map[string]tc{
+		"graph":   {args: []string{"graph"}, sprint: graft.SprintGraph},
+		"mermaid": {args: []string{"mermaid"}, sprint: graft.SprintMermaid},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			want, err := tt.sprint()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := run(t, tt.args...)
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if got != want {
+				t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/grindlemire/graft/cobra
+
+go 1.25.1
+
+replace github.com/grindlemire/graft => ../
+
+require (
+	github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
+	github.com/spf13/cobra v1.10.2
+)
+
+require (
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
@@ -0,0 +1,163 @@
+// Package config provides a graft node that loads configuration from a file
+// with github.com/spf13/viper, and watches the file for changes.
+//
+// Viper picks the format from the file extension: JSON, YAML, TOML and the
+// other formats it supports all work.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port        int    `mapstructure:"port"`
+//	    DatabaseURL string `mapstructure:"database_url"`
+//	}
+//
+//	graft.Register(config.Node[Config]("config", "config.yaml", nil))
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/grindlemire/graft"
+	"github.com/spf13/viper"
+)
+
+// Loader loads a T from a configuration file. Create one with [NewLoader].
+type Loader[T any] struct {
+	path   string
+	decode func(v *viper.Viper) (T, error)
+}
+
+// NewLoader returns a loader that reads configPath with Viper and converts
+// it to a T with decoder. A nil decoder unmarshals the whole file into T
+// with [viper.Viper.Unmarshal], which matches keys to fields through
+// mapstructure tags.
+//
+// Example:
+//
+//	loader := config.NewLoader("config.toml", func(v *viper.Viper) (Config, error) {
+//	    return Config{Port: v.GetInt("server.port")}, nil
+//	})
+func NewLoader[T any](configPath string, decoder func(v *viper.Viper) (T, error)) *Loader[T] {
+	if decoder == nil {
+		decoder = func(v *viper.Viper) (T, error) {
+			var out T
+			err := v.Unmarshal(&out)
+			return out, err
+		}
+	}
+	return &Loader[T]{path: configPath, decode: decoder}
+}
+
+// Node returns a node for [NewLoader]'s loader; see [Loader.Node].
+//
+// Example:
+//
+//	graft.Register(config.Node[Config]("config", "config.yaml", nil))
+func Node[T any](id graft.ID, configPath string, decoder func(v *viper.Viper) (T, error)) graft.Node[T] {
+	return NewLoader(configPath, decoder).Node(id)
+}
+
+// Load reads the file and decodes it. Each call reads the file again.
+func (l *Loader[T]) Load() (T, error) {
+	v := viper.New()
+	v.SetConfigFile(l.path)
+	if err := v.ReadInConfig(); err != nil {
+		var zero T
+		return zero, fmt.Errorf("read config %s: %w", l.path, err)
+	}
+	out, err := l.decode(v)
+	if err != nil {
+		return out, fmt.Errorf("decode config %s: %w", l.path, err)
+	}
+	return out, nil
+}
+
+// Node returns a cacheable node with the given ID whose Run calls
+// [Loader.Load]. Because the output is cached, a changed file is only
+// picked up after the cached entry is removed, for example from the
+// onChange callback of [Loader.Watch].
+//
+// Example:
+//
+//	loader := config.NewLoader[Config]("config.yaml", nil)
+//	graft.Register(loader.Node("config"))
+func (l *Loader[T]) Node(id graft.ID) graft.Node[T] {
+	return graft.Node[T]{
+		ID:          id,
+		Cacheable:   true,
+		Description: "Configuration loaded from " + l.path,
+		Run: func(ctx context.Context) (T, error) {
+			return l.Load()
+		},
+	}
+}
+
+// watchSettle is how long Watch waits after the last write to the file
+// before loading it, so that a file being written is not read half way.
+const watchSettle = 100 * time.Millisecond
+
+// Watch calls onChange with the newly loaded configuration each time the
+// file is written or replaced and its decoded value differs from the
+// previous one. It blocks until ctx is done, then returns nil.
+//
+// The directory holding the file is watched, so editors that save by
+// renaming a temporary file over it are seen too. The file is loaded once
+// writes to it have stopped for 100ms. A change that fails to load is
+// skipped; the next successful load is compared with the last value passed
+// to onChange, or with the value loaded when Watch started.
+//
+// Example:
+//
+//	go loader.Watch(ctx, func(cfg Config) {
+//	    graft.DefaultCache().Delete("config")
+//	    log.Printf("config reloaded: port %d", cfg.Port)
+//	})
+func (l *Loader[T]) Watch(ctx context.Context, onChange func(T)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch config %s: %w", l.path, err)
+	}
+	defer watcher.Close()
+
+	path := filepath.Clean(l.path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watch config %s: %w", l.path, err)
+	}
+
+	last, lastErr := l.Load()
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch config %s: %w", l.path, err)
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(ev.Name) == path && (ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create)) {
+				settle = time.After(watchSettle)
+			}
+		case <-settle:
+			settle = nil
+			cfg, err := l.Load()
+			if err != nil {
+				continue
+			}
+			if lastErr == nil && reflect.DeepEqual(cfg, last) {
+				continue
+			}
+			last, lastErr = cfg, nil
+			onChange(cfg)
+		}
+	}
+}
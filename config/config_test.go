@@ -0,0 +1,168 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/graft"
+	"github.com/spf13/viper"
+)
+
+type appConfig struct {
+	Port int    `mapstructure:"port"`
+	Host string `mapstructure:"host"`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNode(t *testing.T) {
+	type tc struct {
+		file    string
+		content string
+		decoder func(v *viper.Viper) (appConfig, error)
+		want    appConfig
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"yaml with default decoder": {
+			file:    "config.yaml",
+			content: "port: 8080\nhost: localhost\n",
+			want:    appConfig{Port: 8080, Host: "localhost"},
+		},
+		"json with default decoder": {
+			file:    "config.json",
+			content: `{"port": 9090, "host": "example.com"}`,
+			want:    appConfig{Port: 9090, Host: "example.com"},
+		},
+		"toml with custom decoder": {
+			file:    "config.toml",
+			content: "[server]\nport = 7070\n",
+			decoder: func(v *viper.Viper) (appConfig, error) {
+				return appConfig{Port: v.GetInt("server.port")}, nil
+			},
+			want: appConfig{Port: 7070},
+		},
+		"decoder error": {
+			file:    "config.yaml",
+			content: "port: 1\n",
+			decoder: func(v *viper.Viper) (appConfig, error) {
+				return appConfig{}, errors.New("host is required")
+			},
+			wantErr: "host is required",
+		},
+		"missing file": {
+			file:    "config.yaml",
+			wantErr: "read config",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			graft.ResetRegistry()
+			graft.ResetDefaultCache()
+			t.Cleanup(graft.ResetRegistry)
+			t.Cleanup(graft.ResetDefaultCache)
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			if tt.content != "" {
+				writeFile(t, path, tt.content)
+			}
+
+			n := Node("config", path, tt.decoder)
+			if !n.Cacheable {
+				t.Error("config node should be cacheable")
+			}
+			graft.Register(n)
+
+			got, _, err := graft.ExecuteFor[appConfig](context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteFor: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("config = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "port: 1\n")
+	loader := NewLoader[appConfig](path, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan appConfig, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- loader.Watch(ctx, func(cfg appConfig) { changes <- cfg })
+	}()
+
+	// The watcher starts asynchronously: write new ports until one is seen
+	port := 1
+	deadline := time.After(5 * time.Second)
+	for seen := false; !seen; {
+		port++
+		writeFile(t, path, fmt.Sprintf("port: %d\n", port))
+		select {
+		case cfg := <-changes:
+			if cfg.Port < 2 || cfg.Port > port {
+				t.Fatalf("change = %+v, want a port written by the test", cfg)
+			}
+			port, seen = cfg.Port, true
+		case <-time.After(3 * watchSettle):
+		case <-deadline:
+			t.Fatal("no change seen")
+		}
+	}
+
+	// Rewriting the same content is not a change
+	writeFile(t, path, fmt.Sprintf("port: %d\n", port))
+	select {
+	case cfg := <-changes:
+		t.Fatalf("unexpected change %+v for unchanged content", cfg)
+	case <-time.After(3 * watchSettle):
+	}
+
+	// Replacing the file by renaming over it is
+	tmp := path + ".tmp"
+	writeFile(t, tmp, "port: 100\n")
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cfg := <-changes:
+		if cfg.Port != 100 {
+			t.Errorf("change = %+v, want port 100", cfg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("replaced file not seen")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
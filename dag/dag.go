@@ -0,0 +1,267 @@
+// Package dag implements the dependency graph operations graft uses to
+// order node execution: topological levels, cycle detection and
+// reachability.
+//
+// A DAG maps each node ID to the IDs it depends on. It is usable on its own
+// for any graph of string IDs:
+//
+//	g := dag.New()
+//	g.Add("config")
+//	g.Add("db", "config")
+//	g.Add("api", "db", "config")
+//	levels, err := g.Levels() // [[config] [db] [api]]
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrCycle is returned by [DAG.Levels] when the graph has a dependency
+// cycle.
+var ErrCycle = errors.New("cycle detected in dependency graph")
+
+// DAG is a directed graph of nodes and the nodes they depend on. Despite
+// the name it may contain cycles; [DAG.Levels] reports them and
+// [DAG.Cycles] lists them. The zero value is not usable; create one with
+// [New].
+type DAG struct {
+	deps map[string][]string
+}
+
+// New returns an empty graph.
+func New() *DAG {
+	return &DAG{deps: make(map[string][]string)}
+}
+
+// Add adds the node id, if it is not already present, and records that it
+// depends on deps. Dependencies do not have to be added as nodes first, but
+// [DAG.Levels] fails if any is never added. Duplicate dependencies are
+// ignored.
+func (g *DAG) Add(id string, deps ...string) {
+	existing := g.deps[id]
+	if existing == nil {
+		existing = []string{}
+	}
+	for _, dep := range deps {
+		if !contains(existing, dep) {
+			existing = append(existing, dep)
+		}
+	}
+	g.deps[id] = existing
+}
+
+// Has reports whether id was added as a node.
+func (g *DAG) Has(id string) bool {
+	_, ok := g.deps[id]
+	return ok
+}
+
+// Nodes returns every node ID, sorted.
+func (g *DAG) Nodes() []string {
+	ids := make([]string, 0, len(g.deps))
+	for id := range g.deps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Deps returns the direct dependencies of id in the order they were added.
+func (g *DAG) Deps(id string) []string {
+	return append([]string{}, g.deps[id]...)
+}
+
+// Levels groups the nodes with Kahn's algorithm into levels where each
+// node depends only on nodes in earlier levels, so the nodes of a level can
+// run concurrently. IDs within a level are sorted.
+//
+// It returns an error naming the first node, in sorted order, that depends
+// on a node never added. If the graph has a cycle it returns the levels of
+// the nodes that could be ordered along with [ErrCycle].
+func (g *DAG) Levels() ([][]string, error) {
+	for _, id := range g.Nodes() {
+		for _, dep := range g.deps[id] {
+			if !g.Has(dep) {
+				return nil, fmt.Errorf("node %s depends on unknown node %s", id, dep)
+			}
+		}
+	}
+
+	inDegree := make(map[string]int, len(g.deps))
+	dependents := g.dependents()
+	var current []string
+	for id, deps := range g.deps {
+		inDegree[id] = len(deps)
+		if len(deps) == 0 {
+			current = append(current, id)
+		}
+	}
+
+	var levels [][]string
+	processed := 0
+	for len(current) > 0 {
+		sort.Strings(current)
+		levels = append(levels, current)
+		processed += len(current)
+
+		var next []string
+		for _, id := range current {
+			for _, dependent := range dependents[id] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		current = next
+	}
+
+	if processed != len(g.deps) {
+		return levels, ErrCycle
+	}
+	return levels, nil
+}
+
+// Cycles returns dependency cycles as paths that start and end with the
+// same ID (e.g., [a b a]), or nil if the graph is acyclic. Every cyclic
+// part of the graph is represented by at least one cycle, but not every
+// distinct cycle is listed. Nodes and dependencies are visited in sorted
+// order, so the result is deterministic. Dependencies never added as nodes
+// are ignored.
+func (g *DAG) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(g.deps))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		deps := g.Deps(id)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if !g.Has(dep) {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				for i, sid := range stack {
+					if sid == dep {
+						cycles = append(cycles, append(append([]string{}, stack[i:]...), dep))
+						break
+					}
+				}
+			case unvisited:
+				visit(dep)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range g.Nodes() {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// Ancestors returns every node that id depends on, directly or
+// transitively, sorted. id itself is only included if it is part of a
+// cycle.
+func (g *DAG) Ancestors(id string) []string {
+	return reach(id, func(n string) []string { return g.deps[n] })
+}
+
+// Descendants returns every node that depends on id, directly or
+// transitively, sorted. id itself is only included if it is part of a
+// cycle.
+func (g *DAG) Descendants(id string) []string {
+	dependents := g.dependents()
+	return reach(id, func(n string) []string { return dependents[n] })
+}
+
+// Subgraph returns a new graph holding roots and every node they depend on,
+// directly or transitively. Roots and dependencies that were never added
+// as nodes are left out, though nodes keep their dependencies on them.
+func (g *DAG) Subgraph(roots ...string) *DAG {
+	sub := New()
+	var visit func(id string)
+	visit = func(id string) {
+		if sub.Has(id) || !g.Has(id) {
+			return
+		}
+		sub.Add(id, g.deps[id]...)
+		for _, dep := range g.deps[id] {
+			visit(dep)
+		}
+	}
+	for _, id := range roots {
+		visit(id)
+	}
+	return sub
+}
+
+// Merge returns a new graph holding the nodes of both g and other. A node
+// present in both depends on the union of its dependencies.
+func (g *DAG) Merge(other *DAG) *DAG {
+	merged := New()
+	for _, src := range []*DAG{g, other} {
+		for _, id := range src.Nodes() {
+			merged.Add(id, src.deps[id]...)
+		}
+	}
+	return merged
+}
+
+// dependents maps each ID to the nodes that depend on it.
+func (g *DAG) dependents() map[string][]string {
+	dependents := make(map[string][]string)
+	for _, id := range g.Nodes() {
+		for _, dep := range g.deps[id] {
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+	return dependents
+}
+
+// reach returns the IDs reachable from id via next, sorted.
+func reach(id string, next func(string) []string) []string {
+	seen := make(map[string]bool)
+	stack := append([]string{}, next(id)...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		stack = append(stack, next(n)...)
+	}
+
+	out := make([]string, 0, len(seen))
+	for n := range seen {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// build creates a graph from id → deps.
+func build(edges map[string][]string) *DAG {
+	g := New()
+	for id, deps := range edges {
+		g.Add(id, deps...)
+	}
+	return g
+}
+
+func TestLevels(t *testing.T) {
+	type tc struct {
+		edges   map[string][]string
+		want    [][]string
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"empty": {
+			edges: map[string][]string{},
+		},
+		"diamond": {
+			edges: map[string][]string{
+				"config": nil,
+				"db":     {"config"},
+				"cache":  {"config"},
+				"api":    {"db", "cache"},
+			},
+			want: [][]string{{"config"}, {"cache", "db"}, {"api"}},
+		},
+		"duplicate deps": {
+			edges: map[string][]string{"a": nil, "b": {"a", "a"}},
+			want:  [][]string{{"a"}, {"b"}},
+		},
+		"unknown dependency": {
+			edges:   map[string][]string{"db": {"config"}},
+			wantErr: "node db depends on unknown node config",
+		},
+		"cycle keeps ordered levels": {
+			edges: map[string][]string{
+				"root": nil,
+				"a":    {"root", "b"},
+				"b":    {"a"},
+			},
+			want:    [][]string{{"root"}},
+			wantErr: ErrCycle.Error(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := build(tt.edges).Levels()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Levels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelsCycleIsErrCycle(t *testing.T) {
+	_, err := build(map[string][]string{"a": {"a"}}).Levels()
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("error = %v, want ErrCycle", err)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	type tc struct {
+		edges map[string][]string
+		want  [][]string
+	}
+
+	tests := map[string]tc{
+		"acyclic": {
+			edges: map[string][]string{"a": nil, "b": {"a"}},
+		},
+		"self loop": {
+			edges: map[string][]string{"a": {"a"}},
+			want:  [][]string{{"a", "a"}},
+		},
+		"two separate cycles": {
+			edges: map[string][]string{
+				"a": {"b"},
+				"b": {"a"},
+				"x": {"y"},
+				"y": {"z"},
+				"z": {"x"},
+			},
+			want: [][]string{{"a", "b", "a"}, {"x", "y", "z", "x"}},
+		},
+		"unknown deps ignored": {
+			edges: map[string][]string{"a": {"missing"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := build(tt.edges).Cycles()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReachability(t *testing.T) {
+	g := build(map[string][]string{
+		"config": nil,
+		"db":     {"config"},
+		"cache":  {"config"},
+		"api":    {"db", "cache"},
+		"worker": {"db"},
+	})
+
+	tests := map[string]struct {
+		got  []string
+		want []string
+	}{
+		"ancestors of api":      {got: g.Ancestors("api"), want: []string{"cache", "config", "db"}},
+		"ancestors of root":     {got: g.Ancestors("config"), want: []string{}},
+		"descendants of config": {got: g.Descendants("config"), want: []string{"api", "cache", "db", "worker"}},
+		"descendants of db":     {got: g.Descendants("db"), want: []string{"api", "worker"}},
+		"unknown node":          {got: g.Descendants("missing"), want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := build(map[string][]string{
+		"config": nil,
+		"db":     {"config"},
+		"cache":  {"config"},
+		"api":    {"db"},
+		"broken": {"missing"},
+	})
+
+	tests := map[string]struct {
+		roots []string
+		want  []string
+	}{
+		"transitive deps":     {roots: []string{"api"}, want: []string{"api", "config", "db"}},
+		"several roots":       {roots: []string{"db", "cache"}, want: []string{"cache", "config", "db"}},
+		"unknown root":        {roots: []string{"missing"}, want: []string{}},
+		"unknown dep skipped": {roots: []string{"broken"}, want: []string{"broken"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sub := g.Subgraph(tt.roots...)
+			if got := sub.Nodes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subgraph(%v).Nodes() = %v, want %v", tt.roots, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := build(map[string][]string{"config": nil, "db": {"config"}})
+	b := build(map[string][]string{"cache": nil, "db": {"cache"}})
+
+	merged := a.Merge(b)
+	if got, want := merged.Nodes(), []string{"cache", "config", "db"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Nodes() = %v, want %v", got, want)
+	}
+	if got, want := strings.Join(merged.Deps("db"), ","), "config,cache"; got != want {
+		t.Errorf("Deps(db) = %v, want %v", got, want)
+	}
+
+	// The inputs are unchanged
+	if got := a.Deps("db"); len(got) != 1 {
+		t.Errorf("Merge modified its receiver: Deps(db) = %v", got)
+	}
+}
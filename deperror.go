@@ -0,0 +1,63 @@
+package graft
+
+import "fmt"
+
+// DepErrorCode classifies why [Dep] or [Result] could not return a value.
+type DepErrorCode int
+
+const (
+	// ErrNotRegistered means the requested output type has no registered
+	// node. This usually indicates a missing blank import at startup.
+	ErrNotRegistered DepErrorCode = iota + 1
+	// ErrNotFound means the node's output is not available, typically
+	// because the dependency is missing from DependsOn.
+	ErrNotFound
+	// ErrWrongType means the stored output does not have the requested type.
+	ErrWrongType
+	// ErrNoContext means Dep was called with a context that carries no
+	// results, i.e. outside of a node's Run function.
+	ErrNoContext
+)
+
+// String returns the code's name.
+func (c DepErrorCode) String() string {
+	switch c {
+	case ErrNotRegistered:
+		return "not registered"
+	case ErrNotFound:
+		return "not found"
+	case ErrWrongType:
+		return "wrong type"
+	case ErrNoContext:
+		return "no context"
+	default:
+		return fmt.Sprintf("DepErrorCode(%d)", int(c))
+	}
+}
+
+// DepError is returned by [Dep], [Result] and the ExecuteFor functions when
+// a node output cannot be retrieved. Use [errors.As] to tell these
+// programming and startup errors apart from errors returned by nodes.
+//
+// Example:
+//
+//	var depErr *graft.DepError
+//	if errors.As(err, &depErr) && depErr.Code == graft.ErrNotRegistered {
+//	    http.Error(w, "service misconfigured", http.StatusServiceUnavailable)
+//	    return
+//	}
+type DepError struct {
+	// Code classifies the error.
+	Code DepErrorCode
+
+	// NodeID is the ID of the requested node, if known.
+	NodeID ID
+
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the message prefixed with "graft: ".
+func (e *DepError) Error() string {
+	return "graft: " + e.Message
+}
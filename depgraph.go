@@ -0,0 +1,115 @@
+package graft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grindlemire/graft/dag"
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+// DependencyGraph maps each node ID to the IDs it declares in DependsOn.
+// Build one from analysis results with [BuildDependencyGraph].
+type DependencyGraph map[string][]string
+
+// BuildDependencyGraph aggregates the declared dependencies from
+// [AnalyzeDir] results into a [DependencyGraph]. IDs that appear only as
+// dependencies (e.g., nodes outside the analyzed directory) are included
+// with no dependencies of their own.
+//
+// Example:
+//
+//	results, err := graft.AnalyzeDir("./nodes")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	g := graft.BuildDependencyGraph(results)
+//	fmt.Println(g.Descendants("config"))
+func BuildDependencyGraph(results []typeaware.Result) DependencyGraph {
+	g := make(DependencyGraph, len(results))
+	for _, r := range results {
+		seen := make(map[string]bool, len(r.DeclaredDeps))
+		deps := make([]string, 0, len(r.DeclaredDeps))
+		for _, dep := range r.DeclaredDeps {
+			if !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+		sort.Strings(deps)
+		g[r.NodeID] = append(g[r.NodeID], deps...)
+	}
+	for _, deps := range g {
+		for _, dep := range deps {
+			if _, ok := g[dep]; !ok {
+				g[dep] = nil
+			}
+		}
+	}
+	return g
+}
+
+// Ancestors returns every node that id depends on, directly or
+// transitively, sorted by ID.
+func (g DependencyGraph) Ancestors(id string) []string {
+	return g.toDAG().Ancestors(id)
+}
+
+// Descendants returns every node that depends on id, directly or
+// transitively, sorted by ID.
+func (g DependencyGraph) Descendants(id string) []string {
+	return g.toDAG().Descendants(id)
+}
+
+// Topological groups the nodes into levels where each node depends only on
+// nodes in earlier levels, like the levels Execute runs concurrently. Nodes
+// within a level are sorted. Nodes on or behind a dependency cycle cannot be
+// ordered and are omitted.
+func (g DependencyGraph) Topological() [][]string {
+	// Every dependency is a node, so only a cycle can fail; its levels
+	// hold the nodes that could be ordered
+	levels, _ := g.toDAG().Levels()
+	return levels
+}
+
+// toDAG converts g to a [dag.DAG], adding IDs that appear only as
+// dependencies as nodes without dependencies.
+func (g DependencyGraph) toDAG() *dag.DAG {
+	d := dag.New()
+	for id, deps := range g {
+		d.Add(id, deps...)
+		for _, dep := range deps {
+			d.Add(dep)
+		}
+	}
+	return d
+}
+
+// DOT renders the graph in Graphviz DOT format, with edges pointing from a
+// dependency to the node that depends on it.
+//
+// Example:
+//
+//	os.WriteFile("graph.dot", []byte(g.DOT()), 0o644)
+//	// dot -Tsvg graph.dot -o graph.svg
+func (g DependencyGraph) DOT() string {
+	ids := make([]string, 0, len(g))
+	for id := range g {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	b.WriteString("digraph graft {\n")
+	for _, id := range ids {
+		fmt.Fprintf(&b, "    %q;\n", id)
+	}
+	for _, id := range ids {
+		for _, dep := range g[id] {
+			fmt.Fprintf(&b, "    %q -> %q;\n", dep, id)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
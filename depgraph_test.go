@@ -0,0 +1,115 @@
+package graft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+func depGraphTestResults() []typeaware.Result {
+	return []typeaware.Result{
+		{NodeID: "config"},
+		{NodeID: "db", DeclaredDeps: []string{"config"}},
+		{NodeID: "cache", DeclaredDeps: []string{"config", "config"}},
+		{NodeID: "api", DeclaredDeps: []string{"db", "cache"}},
+		{NodeID: "worker", DeclaredDeps: []string{"db", "external"}},
+	}
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	got := BuildDependencyGraph(depGraphTestResults())
+	want := DependencyGraph{
+		"config":   nil,
+		"db":       {"config"},
+		"cache":    {"config"},
+		"api":      {"cache", "db"},
+		"worker":   {"db", "external"},
+		"external": nil,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d nodes, want %d: %v", len(got), len(want), got)
+	}
+	for id, deps := range want {
+		if len(got[id]) != len(deps) || (len(deps) > 0 && !reflect.DeepEqual(got[id], deps)) {
+			t.Errorf("%s: deps = %v, want %v", id, got[id], deps)
+		}
+	}
+}
+
+func TestDependencyGraphTraversal(t *testing.T) {
+	g := BuildDependencyGraph(depGraphTestResults())
+
+	type tc struct {
+		got  []string
+		want []string
+	}
+
+	tests := map[string]tc{
+		"ancestors of api":      {got: g.Ancestors("api"), want: []string{"cache", "config", "db"}},
+		"ancestors of root":     {got: g.Ancestors("config"), want: []string{}},
+		"descendants of config": {got: g.Descendants("config"), want: []string{"api", "cache", "db", "worker"}},
+		"descendants of leaf":   {got: g.Descendants("api"), want: []string{}},
+		"unknown node":          {got: g.Ancestors("missing"), want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraphTopological(t *testing.T) {
+	type tc struct {
+		graph DependencyGraph
+		want  [][]string
+	}
+
+	tests := map[string]tc{
+		"levels": {
+			graph: BuildDependencyGraph(depGraphTestResults()),
+			want: [][]string{
+				{"config", "external"},
+				{"cache", "db"},
+				{"api", "worker"},
+			},
+		},
+		"cycle omitted": {
+			graph: DependencyGraph{
+				"root": nil,
+				"a":    {"root", "b"},
+				"b":    {"a"},
+				"c":    {"b"},
+			},
+			want: [][]string{{"root"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.graph.Topological(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraphDOT(t *testing.T) {
+	g := DependencyGraph{
+		"config": nil,
+		"db":     {"config"},
+	}
+
+	want := `digraph graft {
+    "config";
+    "db";
+    "config" -> "db";
+}
+`
+	if got := g.DOT(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
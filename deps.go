@@ -0,0 +1,78 @@
+package graft
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Deps returns ids with duplicates removed, sorted for deterministic
+// output. Use it to build a node's DependsOn list.
+//
+// Example:
+//
+//	graft.Register(graft.Node[Output]{
+//	    ID:        "api",
+//	    DependsOn: graft.Deps("db", "config", "db"), // [config db]
+//	    Run:       run,
+//	})
+func Deps(ids ...ID) []ID {
+	seen := make(map[ID]bool, len(ids))
+	out := make([]ID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// DepsOf returns the ID of the node whose output type is A, as a DependsOn
+// list. Unlike string IDs, the type argument is checked by the compiler.
+//
+// The dependency must already be registered, which is the case when it
+// lives in a package imported by the caller: Go runs the imported
+// package's init functions first. Panics if A is not registered.
+//
+// Use [DepsOf2], [DepsOf3] and [DepsOf4] for more dependencies.
+//
+// Example:
+//
+//	graft.Register(graft.Node[Output]{
+//	    ID:        "db",
+//	    DependsOn: graft.DepsOf[config.Output](),
+//	    Run:       run,
+//	})
+func DepsOf[A any]() []ID {
+	return depsOf((*A)(nil))
+}
+
+// DepsOf2 is like [DepsOf] for two dependencies.
+//
+// Example:
+//
+//	DependsOn: graft.DepsOf2[config.Output, db.Output](),
+func DepsOf2[A, B any]() []ID {
+	return depsOf((*A)(nil), (*B)(nil))
+}
+
+// DepsOf3 is like [DepsOf] for three dependencies.
+func DepsOf3[A, B, C any]() []ID {
+	return depsOf((*A)(nil), (*B)(nil), (*C)(nil))
+}
+
+// DepsOf4 is like [DepsOf] for four dependencies.
+func DepsOf4[A, B, C, D any]() []ID {
+	return depsOf((*A)(nil), (*B)(nil), (*C)(nil), (*D)(nil))
+}
+
+// depsOf resolves typed nil pointer sentinels to a sorted, deduplicated
+// DependsOn list, panicking on unregistered types.
+func depsOf(sentinels ...any) []ID {
+	ids, err := outputIDs(sentinels...)
+	if err != nil {
+		panic(fmt.Sprintf("graft: DepsOf: %s", err.(*DepError).Message))
+	}
+	return Deps(ids...)
+}
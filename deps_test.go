@@ -0,0 +1,82 @@
+package graft
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeps(t *testing.T) {
+	type tc struct {
+		ids  []ID
+		want []ID
+	}
+
+	tests := map[string]tc{
+		"empty":              {ids: nil, want: []ID{}},
+		"sorted":             {ids: []ID{"db", "config"}, want: []ID{"config", "db"}},
+		"duplicates removed": {ids: []ID{"db", "config", "db", "db"}, want: []ID{"config", "db"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Deps(tt.ids...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Deps(%v) = %v, want %v", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
+
+type depsOfConfig struct{}
+type depsOfDB struct{}
+type depsOfCache struct{}
+type depsOfQueue struct{}
+
+func TestDepsOf(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	Register(Node[depsOfConfig]{ID: "config", Run: func(ctx context.Context) (depsOfConfig, error) { return depsOfConfig{}, nil }})
+	Register(Node[depsOfDB]{ID: "db", Run: func(ctx context.Context) (depsOfDB, error) { return depsOfDB{}, nil }})
+	Register(Node[depsOfCache]{ID: "cache", Run: func(ctx context.Context) (depsOfCache, error) { return depsOfCache{}, nil }})
+	Register(Node[depsOfQueue]{ID: "queue", Run: func(ctx context.Context) (depsOfQueue, error) { return depsOfQueue{}, nil }})
+
+	type tc struct {
+		got  func() []ID
+		want []ID
+	}
+
+	tests := map[string]tc{
+		"one":        {got: DepsOf[depsOfDB], want: []ID{"db"}},
+		"two sorted": {got: DepsOf2[depsOfDB, depsOfConfig], want: []ID{"config", "db"}},
+		"three":      {got: DepsOf3[depsOfQueue, depsOfDB, depsOfCache], want: []ID{"cache", "db", "queue"}},
+		"four":       {got: DepsOf4[depsOfQueue, depsOfDB, depsOfCache, depsOfConfig], want: []ID{"cache", "config", "db", "queue"}},
+		"duplicate":  {got: DepsOf2[depsOfDB, depsOfDB], want: []ID{"db"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.got(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepsOfUnregisteredPanics(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic for unregistered type")
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, "depsOfQueue not registered") {
+			t.Errorf("unexpected panic message: %v", r)
+		}
+	}()
+
+	DepsOf[depsOfQueue]()
+}
@@ -0,0 +1,81 @@
+// Package dig bridges graft and go.uber.org/dig containers in both
+// directions.
+//
+// [ContainerFromRegistry] returns a container that executes the graft graph
+// the first time it is needed and provides the resulting [graft.Results];
+// [Provide] then exposes individual node outputs to dig constructors by
+// type. [NodeFromProvider] goes the other way and turns a dig constructor
+// into a graft node.
+package dig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grindlemire/graft"
+	"go.uber.org/dig"
+)
+
+// ContainerFromRegistry returns a new container providing the
+// [graft.Results] of executing the graft graph with opts. The graph is
+// executed at most once, the first time a constructor or Invoke needs it.
+//
+// Example:
+//
+//	c := graftdig.ContainerFromRegistry()
+//	if err := graftdig.Provide[config.Output](c); err != nil {
+//	    log.Fatal(err)
+//	}
+//	err := c.Invoke(func(cfg config.Output) { ... })
+func ContainerFromRegistry(opts ...graft.Option) *dig.Container {
+	c := dig.New()
+	// A func() (graft.Results, error) constructor is always valid
+	_ = c.Provide(func() (graft.Results, error) {
+		return graft.Execute(context.Background(), opts...)
+	})
+	return c
+}
+
+// Provide adds a constructor for T to c that returns the output of the
+// graft node producing T. c must provide [graft.Results], as containers
+// from [ContainerFromRegistry] do.
+//
+// Example:
+//
+//	err := graftdig.Provide[db.Output](c)
+func Provide[T any](c *dig.Container) error {
+	return c.Provide(func(results graft.Results) (T, error) {
+		return graft.Result[T](results)
+	})
+}
+
+// NodeFromProvider adds the dig constructor fn to c and returns a graft node
+// with the given id whose Run resolves T from c. fn's parameters are
+// resolved by c, not by graft, so the node has no DependsOn. The returned
+// error is the one c.Provide reports for an invalid fn.
+//
+// c must not get T from the graft graph the node is registered in (for
+// example with [Provide]), or resolving it would execute that graph again.
+//
+// Example:
+//
+//	c := dig.New()
+//	n, err := graftdig.NodeFromProvider[*sql.DB]("db", c, openDB)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	graft.Register(n)
+func NodeFromProvider[T any](id graft.ID, c *dig.Container, fn any) (graft.Node[T], error) {
+	if err := c.Provide(fn); err != nil {
+		return graft.Node[T]{}, fmt.Errorf("node %s: %w", id, err)
+	}
+
+	return graft.Node[T]{
+		ID: id,
+		Run: func(ctx context.Context) (T, error) {
+			var out T
+			err := c.Invoke(func(v T) { out = v })
+			return out, err
+		},
+	}, nil
+}
@@ -0,0 +1,107 @@
+package dig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"go.uber.org/dig"
+)
+
+type configOutput struct{ Port int }
+type serverOutput struct{ Addr string }
+
+func TestContainerFromRegistry(t *testing.T) {
+	type tc struct {
+		runErr    error
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"provides outputs": {},
+		"execution error": {
+			runErr:    errors.New("boom"),
+			errSubstr: "boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			graft.ResetRegistry()
+			t.Cleanup(graft.ResetRegistry)
+
+			runs := 0
+			graft.Register(graft.Node[configOutput]{
+				ID: "config",
+				Run: func(ctx context.Context) (configOutput, error) {
+					runs++
+					return configOutput{Port: 8080}, tt.runErr
+				},
+			})
+
+			c := ContainerFromRegistry(graft.DisableCache())
+			if err := Provide[configOutput](c); err != nil {
+				t.Fatalf("Provide: %v", err)
+			}
+			if err := c.Provide(func(cfg configOutput) serverOutput {
+				return serverOutput{Addr: "localhost"}
+			}); err != nil {
+				t.Fatalf("Provide server: %v", err)
+			}
+
+			var got configOutput
+			err := c.Invoke(func(cfg configOutput, _ serverOutput) { got = cfg })
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Invoke: %v", err)
+			}
+			if got.Port != 8080 {
+				t.Errorf("config = %+v, want port 8080", got)
+			}
+
+			if err := c.Invoke(func(graft.Results) {}); err != nil {
+				t.Fatalf("Invoke: %v", err)
+			}
+			if runs != 1 {
+				t.Errorf("graph executed %d times, want 1", runs)
+			}
+		})
+	}
+}
+
+func TestNodeFromProvider(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	c := dig.New()
+	if err := c.Provide(func() configOutput { return configOutput{Port: 9090} }); err != nil {
+		t.Fatalf("Provide: %v", err)
+	}
+	n, err := NodeFromProvider[serverOutput]("server", c, func(cfg configOutput) serverOutput {
+		return serverOutput{Addr: fmt.Sprintf("localhost:%d", cfg.Port)}
+	})
+	if err != nil {
+		t.Fatalf("NodeFromProvider: %v", err)
+	}
+	graft.Register(n)
+
+	out, _, err := graft.ExecuteFor[serverOutput](context.Background(), graft.DisableCache())
+	if err != nil {
+		t.Fatalf("ExecuteFor: %v", err)
+	}
+	if out.Addr != "localhost:9090" {
+		t.Errorf("server = %+v, want localhost:9090", out)
+	}
+
+	if _, err := NodeFromProvider[serverOutput]("bad", c, 42); err == nil {
+		t.Errorf("expected an error for a non-function provider")
+	}
+}
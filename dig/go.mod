@@ -0,0 +1,17 @@
+module github.com/grindlemire/graft/dig
+
+go 1.25.1
+
+replace github.com/grindlemire/graft => ../
+
+require (
+	github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
+	go.uber.org/dig v1.19.0
+)
+
+require (
+	github.com/stretchr/testify v1.11.1 // indirect
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
@@ -0,0 +1,36 @@
+package graft
+
+import "reflect"
+
+// WithDryRun validates the graph without running any node. Execute still
+// resolves the nodes to run and sorts them into levels, returning any cycle
+// or unknown-dependency error, but then returns results holding the zero
+// value of every node's output type instead of calling Run.
+//
+// Caches, hooks and stats are not touched. Use it at startup to fail fast
+// on a broken graph before doing expensive work.
+//
+// Example:
+//
+//	if _, err := graft.Execute(ctx, graft.WithDryRun()); err != nil {
+//	    log.Fatal(err)
+//	}
+func WithDryRun() Option {
+	return func(c *config) {
+		c.dryRun = true
+	}
+}
+
+// storeZeroResults records the zero value of each node's output type as its
+// result. Nodes of unknown output type get a nil result.
+func (e *engine) storeZeroResults() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, n := range e.nodes {
+		if n.outputType == nil {
+			e.results[id] = nil
+			continue
+		}
+		e.results[id] = reflect.Zero(n.outputType).Interface()
+	}
+}
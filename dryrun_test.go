@@ -0,0 +1,78 @@
+package graft
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type dryRunConfig struct{ Port int }
+
+func TestWithDryRun(t *testing.T) {
+	type tc struct {
+		nodes     map[ID]node
+		want      Results
+		errSubstr string
+	}
+
+	called := func(t *testing.T) func(ctx context.Context) (any, error) {
+		return func(ctx context.Context) (any, error) {
+			t.Errorf("Run called during a dry run")
+			return nil, nil
+		}
+	}
+
+	tests := map[string]tc{
+		"zero values by output type": {
+			nodes: map[ID]node{
+				"config": eraseNode("config", Node[dryRunConfig]{}),
+				"name":   eraseNode("name", Node[string]{DependsOn: []ID{"config"}}),
+				"db":     eraseNode("db", Node[*dryRunConfig]{DependsOn: []ID{"config"}}),
+				"legacy": makeNode("legacy", nil, nil),
+			},
+			want: Results{
+				"config": dryRunConfig{},
+				"name":   "",
+				"db":     (*dryRunConfig)(nil),
+				"legacy": nil,
+			},
+		},
+		"cycle": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"b"}, nil),
+				"b": makeNode("b", []ID{"a"}, nil),
+			},
+			errSubstr: "cycle",
+		},
+		"unknown dependency": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"missing"}, nil),
+			},
+			errSubstr: "unknown node missing",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for id, n := range tt.nodes {
+				n.run = called(t)
+				tt.nodes[id] = n
+			}
+
+			results, err := Execute(context.Background(), WithRegistry(tt.nodes), WithDryRun())
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("results = %#v, want %#v", results, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package echo provides graft middleware for github.com/labstack/echo
+// servers, using the [httpserver.Runner] functions of graft/httpserver to
+// choose what each request executes.
+//
+// Example:
+//
+//	e := echo.New()
+//	e.GET("/user/:id", func(c echo.Context) error {
+//	    u, err := graftecho.Result[user.Output](c)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return c.JSON(http.StatusOK, u)
+//	}, graftecho.Middleware(httpserver.For[user.Output]()))
+package echo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/httpserver"
+	"github.com/labstack/echo/v4"
+)
+
+// resultsKey is the echo.Context key holding the graph results.
+const resultsKey = "graft.results"
+
+// Middleware runs the graph with run on every request, using the request's
+// context, and stores the results in the echo.Context. Retrieve them with
+// [Results] or [Result].
+//
+// If execution fails, the middleware does not call the next handler and
+// returns an HTTP 500 *echo.HTTPError wrapping the execution error, which
+// Echo's HTTPErrorHandler turns into the response.
+func Middleware(run httpserver.Runner) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			results, err := run(c.Request().Context())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("graft: %v", err)).SetInternal(err)
+			}
+			c.Set(resultsKey, results)
+			return next(c)
+		}
+	}
+}
+
+// Results returns the graph results stored by [Middleware], or nil if the
+// request did not pass through it.
+func Results(c echo.Context) graft.Results {
+	results, _ := c.Get(resultsKey).(graft.Results)
+	return results
+}
+
+// Result retrieves a node's output from the results stored by
+// [Middleware] with type assertion. See [graft.Result].
+func Result[T any](c echo.Context) (T, error) {
+	results, ok := c.Get(resultsKey).(graft.Results)
+	if !ok {
+		var zero T
+		return zero, &graft.DepError{Code: graft.ErrNoContext, Message: "no results in echo context"}
+	}
+	return graft.Result[T](results)
+}
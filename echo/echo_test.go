@@ -0,0 +1,117 @@
+package echo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/httpserver"
+	"github.com/labstack/echo/v4"
+)
+
+type userOutput struct{ Name string }
+type failingOutput struct{}
+
+var errBoom = errors.New("boom")
+
+// registerNodes registers the smallest graph the middleware needs: a node
+// that succeeds and one that fails.
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[userOutput]{
+		ID:  "user",
+		Run: func(ctx context.Context) (userOutput, error) { return userOutput{Name: "alice"}, nil },
+	})
+	graft.Register(graft.Node[failingOutput]{
+		ID:  "failing",
+		Run: func(ctx context.Context) (failingOutput, error) { return failingOutput{}, errBoom },
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	type tc struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}
+
+	tests := map[string]tc{
+		"results available to routed handler": {
+			path:       "/user/1",
+			wantStatus: http.StatusOK,
+			wantBody:   "1:alice:1",
+		},
+		"execution error returns 500": {
+			path:       "/failing",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "graft: node failing: boom",
+		},
+		"routes without the middleware have no results": {
+			path:       "/plain",
+			wantStatus: http.StatusOK,
+			wantBody:   "results=0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			e := echo.New()
+			e.GET("/user/:id", func(c echo.Context) error {
+				u, err := Result[userOutput](c)
+				if err != nil {
+					return err
+				}
+				return c.String(http.StatusOK, c.Param("id")+":"+u.Name+":"+strconv.Itoa(len(Results(c))))
+			}, Middleware(httpserver.For[userOutput]()))
+
+			g := e.Group("", Middleware(httpserver.For[failingOutput]()))
+			g.GET("/failing", func(c echo.Context) error {
+				t.Error("handler called after a failed execution")
+				return nil
+			})
+
+			e.GET("/plain", func(c echo.Context) error {
+				if _, err := Result[userOutput](c); err == nil {
+					t.Error("Result without the middleware should fail")
+				}
+				return c.String(http.StatusOK, "results="+strconv.Itoa(len(Results(c))))
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMiddlewareWrapsError(t *testing.T) {
+	registerNodes(t)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	err := Middleware(httpserver.For[failingOutput]())(func(c echo.Context) error { return nil })(c)
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusInternalServerError {
+		t.Fatalf("error = %v, want a 500 *echo.HTTPError", err)
+	}
+	if !errors.Is(err, errBoom) {
+		t.Errorf("error = %v, want it to wrap the execution error", err)
+	}
+}
@@ -3,7 +3,10 @@ package graft
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Option configures execution behavior.
@@ -11,8 +14,52 @@ type Option func(*config)
 
 type config struct {
 	registry       map[ID]node
-	cache          Cache       // optional cache for node outputs
-	ignoreCacheFor map[ID]bool // nodes to skip cache lookup
+	cache          Cache          // optional cache for node outputs
+	ignoreCacheFor map[ID]bool    // nodes to skip cache lookup
+	cacheKey       func(id ID) ID // optional cache key derivation
+	contextValues  []contextValue
+	metrics        MetricsSink // optional metrics sink
+	deadline       time.Time   // optional absolute deadline for execution
+	stats          *ExecutionStats
+	startHooks     []func(id ID, level int)
+	completeHooks  []func(id ID, level int, d time.Duration, err error)
+	cacheHitHooks  []func(id ID)
+	levelHooks     []func(level int, results Results)
+
+	circuitBreakers map[ID]CircuitBreakerPolicy
+	parallelGroups  [][]ID
+	replay          *replay
+	fallbacks       map[ID]any
+	stagger         func() time.Duration // delay between node launches within a level
+	recoverPanics   bool                 // convert panics in Run into errors
+	dryRun          bool                 // validate the graph without running nodes
+	resultHooks     []func(id ID, output any)
+	nodeOrder       []ID // run each level sequentially in this order
+	preHooks        []func(ctx context.Context, nodes []NodeSummary) error
+	postHooks       []func(ctx context.Context, results Results, err error)
+	ansiColors      bool // color ASCII graph output even when not a terminal
+}
+
+// contextValue is a key-value pair applied to the execution context.
+type contextValue struct {
+	key any
+	val any
+}
+
+// applyContextValues wraps ctx with each configured context value in order.
+func (c *config) applyContextValues(ctx context.Context) context.Context {
+	for _, kv := range c.contextValues {
+		ctx = context.WithValue(ctx, kv.key, kv.val)
+	}
+	return ctx
+}
+
+// applyDeadline bounds ctx by the configured deadline, if any.
+func (c *config) applyDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.deadline.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, c.deadline)
 }
 
 // WithRegistry uses a custom node registry instead of the global registry.
@@ -89,6 +136,44 @@ func IgnoreCache(ids ...ID) Option {
 	}
 }
 
+// WithCacheNamespace prefixes every cache key used during execution with
+// ns, isolating cache entries between tenants that share a cache.
+// A node "config" executed with namespace "tenant-a" is stored under the
+// key "tenant-a/config".
+//
+// This is shorthand for [WithCacheKey] and replaces any previously
+// configured key function.
+//
+// Example:
+//
+//	out, _, err := graft.ExecuteFor[app.Output](ctx,
+//	    graft.WithCacheNamespace(tenantID),
+//	)
+func WithCacheNamespace(ns string) Option {
+	return WithCacheKey(func(id ID) ID {
+		return ID(ns) + "/" + id
+	})
+}
+
+// WithCacheKey customizes how node IDs are mapped to cache keys. The engine
+// calls fn before every cache Get/Set, so fn can derive keys arbitrarily
+// (e.g., from values captured from the request).
+//
+// IgnoreCache still matches on node IDs, not derived keys.
+//
+// Example:
+//
+//	out, _, err := graft.ExecuteFor[app.Output](ctx,
+//	    graft.WithCacheKey(func(id graft.ID) graft.ID {
+//	        return graft.ID(region) + ":" + id
+//	    }),
+//	)
+func WithCacheKey(fn func(id ID) ID) Option {
+	return func(c *config) {
+		c.cacheKey = fn
+	}
+}
+
 // DisableCache disables the use of the default global cache.
 //
 // Example:
@@ -101,6 +186,59 @@ func DisableCache() Option {
 	}
 }
 
+// WithContextValues adds each key-value pair to the context before execution
+// begins, making request-scoped values (user ID, tenant ID, feature flags)
+// available to every node's Run function via ctx.Value.
+//
+// Keys follow the same rules as [context.WithValue]: they must be comparable
+// and non-nil, and should use unexported types to avoid collisions.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.WithContextValues(map[any]any{
+//	    tenantKey{}: "acme",
+//	    userKey{}:   userID,
+//	}))
+func WithContextValues(values map[any]any) Option {
+	return func(c *config) {
+		for k, v := range values {
+			c.contextValues = append(c.contextValues, contextValue{key: k, val: v})
+		}
+	}
+}
+
+// WithContextValue adds a single key-value pair to the context before
+// execution begins. It is the typed single-value variant of [WithContextValues].
+//
+// Example:
+//
+//	out, _, err := graft.ExecuteFor[app.Output](ctx,
+//	    graft.WithContextValue(tenantKey{}, "acme"),
+//	)
+func WithContextValue[K any](key K, val any) Option {
+	return func(c *config) {
+		c.contextValues = append(c.contextValues, contextValue{key: key, val: val})
+	}
+}
+
+// WithDeadline sets an absolute deadline for the entire execution. The
+// context passed to every node is cancelled once the deadline passes, so
+// long-running nodes that honor ctx are interrupted, and nodes that have
+// not started yet are skipped. Execution then fails with
+// [context.DeadlineExceeded].
+//
+// An earlier deadline already present on ctx still applies.
+//
+// Example:
+//
+//	deadline, _ := grpcCtx.Deadline()
+//	results, err := graft.Execute(ctx, graft.WithDeadline(deadline))
+func WithDeadline(deadline time.Time) Option {
+	return func(c *config) {
+		c.deadline = deadline
+	}
+}
+
 // PatchValue replaces a node's output with a fixed value for testing.
 //
 // The node is identified by the type T, which must match a registered node's
@@ -117,7 +255,7 @@ func DisableCache() Option {
 //	)
 func PatchValue[T any](value T) Option {
 	return func(c *config) {
-		id, ok := typeToID[(*T)(nil)]
+		id, ok := idForType((*T)(nil))
 		if !ok {
 			return
 		}
@@ -125,9 +263,10 @@ func PatchValue[T any](value T) Option {
 			c.registry = Registry()
 		}
 		c.registry[id] = node{
-			id:        id,
-			dependsOn: []ID{},
-			run:       func(ctx context.Context) (any, error) { return value, nil },
+			id:         id,
+			dependsOn:  []ID{},
+			run:        func(ctx context.Context) (any, error) { return value, nil },
+			outputType: reflect.TypeOf((*T)(nil)).Elem(),
 		}
 	}
 }
@@ -135,8 +274,8 @@ func PatchValue[T any](value T) Option {
 // Patch replaces a node with a custom node for testing.
 //
 // The node is identified by the type T, which must match a registered node's
-// output type. The patched node inherits DependsOn, Run, and Cacheable from
-// the provided Node[T].
+// output type. The patched node inherits DependsOn, Run (or RunWithContext),
+// Cacheable, CacheTags, OnError, and Concurrency from the provided Node[T].
 //
 // This is a no-op if type T is not registered.
 //
@@ -154,19 +293,14 @@ func PatchValue[T any](value T) Option {
 //	)
 func Patch[T any](n Node[T]) Option {
 	return func(c *config) {
-		id, ok := typeToID[(*T)(nil)]
+		id, ok := idForType((*T)(nil))
 		if !ok {
 			return
 		}
 		if c.registry == nil {
 			c.registry = Registry()
 		}
-		c.registry[id] = node{
-			id:        id,
-			dependsOn: n.DependsOn,
-			run:       func(ctx context.Context) (any, error) { return n.Run(ctx) },
-			cacheable: n.Cacheable,
-		}
+		c.registry[id] = eraseNode(id, n)
 	}
 }
 
@@ -193,11 +327,14 @@ func Patch[T any](n Node[T]) Option {
 //	    }
 //	    db := results["db"].(*sql.DB)
 //	}
-func Execute(ctx context.Context, opts ...Option) (map[ID]any, error) {
+func Execute(ctx context.Context, opts ...Option) (Results, error) {
 	cfg := &config{registry: Registry(), cache: defaultCache}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	ctx = cfg.applyContextValues(ctx)
+	ctx, cancel := cfg.applyDeadline(ctx)
+	defer cancel()
 
 	engine := newEngine(cfg.registry, cfg)
 	if err := engine.run(ctx); err != nil {
@@ -222,12 +359,12 @@ func Execute(ctx context.Context, opts ...Option) (map[ID]any, error) {
 //	// appOut is typed as app.Output
 //	// results map available for accessing dependencies:
 //	config, _ := graft.Result[config.Output](results)
-func ExecuteFor[T any](ctx context.Context, opts ...Option) (T, results, error) {
+func ExecuteFor[T any](ctx context.Context, opts ...Option) (T, Results, error) {
 	var zero T
 
-	id, ok := typeToID[(*T)(nil)]
+	id, ok := idForType((*T)(nil))
 	if !ok {
-		return zero, nil, fmt.Errorf("graft: type %T not registered as node output", zero)
+		return zero, nil, &DepError{Code: ErrNotRegistered, Message: fmt.Sprintf("type %s not registered as node output", typeName[T]())}
 	}
 
 	results, err := executeForIDs(ctx, []ID{id}, opts...)
@@ -243,13 +380,110 @@ func ExecuteFor[T any](ctx context.Context, opts ...Option) (T, results, error)
 	return result, results, nil
 }
 
+// ExecuteForMultiple runs the nodes that produce types A and B, together
+// with the union of their transitive dependencies, in a single execution.
+// Shared dependencies run once.
+//
+// Returns an error if either type is not registered or execution fails.
+//
+// Example:
+//
+//	u, cfg, results, err := graft.ExecuteForMultiple[user.Output, config.Output](ctx)
+func ExecuteForMultiple[A, B any](ctx context.Context, opts ...Option) (A, B, Results, error) {
+	var zeroA A
+	var zeroB B
+
+	ids, err := outputIDs((*A)(nil), (*B)(nil))
+	if err != nil {
+		return zeroA, zeroB, nil, err
+	}
+
+	results, err := executeForIDs(ctx, ids, opts...)
+	if err != nil {
+		return zeroA, zeroB, nil, err
+	}
+
+	a, err := Result[A](results)
+	if err != nil {
+		return zeroA, zeroB, nil, err
+	}
+	b, err := Result[B](results)
+	if err != nil {
+		return zeroA, zeroB, nil, err
+	}
+
+	return a, b, results, nil
+}
+
+// ExecuteForMultiple3 is like [ExecuteForMultiple] for three output types.
+//
+// Example:
+//
+//	u, cfg, db, results, err := graft.ExecuteForMultiple3[user.Output, config.Output, db.Output](ctx)
+func ExecuteForMultiple3[A, B, C any](ctx context.Context, opts ...Option) (A, B, C, Results, error) {
+	var zeroA A
+	var zeroB B
+	var zeroC C
+
+	ids, err := outputIDs((*A)(nil), (*B)(nil), (*C)(nil))
+	if err != nil {
+		return zeroA, zeroB, zeroC, nil, err
+	}
+
+	results, err := executeForIDs(ctx, ids, opts...)
+	if err != nil {
+		return zeroA, zeroB, zeroC, nil, err
+	}
+
+	a, err := Result[A](results)
+	if err != nil {
+		return zeroA, zeroB, zeroC, nil, err
+	}
+	b, err := Result[B](results)
+	if err != nil {
+		return zeroA, zeroB, zeroC, nil, err
+	}
+	c, err := Result[C](results)
+	if err != nil {
+		return zeroA, zeroB, zeroC, nil, err
+	}
+
+	return a, b, c, results, nil
+}
+
+// outputIDs maps typed nil pointer sentinels (e.g., (*T)(nil)) to the IDs of
+// the nodes that produce T.
+func outputIDs(sentinels ...any) ([]ID, error) {
+	ids := make([]ID, 0, len(sentinels))
+	for _, sentinel := range sentinels {
+		id, ok := idForType(sentinel)
+		if !ok {
+			return nil, &DepError{
+				Code:    ErrNotRegistered,
+				Message: fmt.Sprintf("type %s not registered as node output", strings.TrimPrefix(fmt.Sprintf("%T", sentinel), "*")),
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // executeForIDs runs the specified target nodes and their transitive dependencies.
 // This is an internal helper used by ExecuteFor.
-func executeForIDs(ctx context.Context, targets []ID, opts ...Option) (map[ID]any, error) {
+func executeForIDs(ctx context.Context, targets []ID, opts ...Option) (Results, error) {
 	cfg := &config{registry: Registry(), cache: defaultCache}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	return executeSubgraph(ctx, cfg, targets)
+}
+
+// executeSubgraph runs targets and their transitive dependencies with an
+// already-configured cfg.
+func executeSubgraph(ctx context.Context, cfg *config, targets []ID) (Results, error) {
+	ctx = cfg.applyContextValues(ctx)
+	ctx, cancel := cfg.applyDeadline(ctx)
+	defer cancel()
 
 	nodes, err := resolveSubgraph(cfg.registry, targets)
 	if err != nil {
@@ -265,33 +499,24 @@ func executeForIDs(ctx context.Context, targets []ID, opts ...Option) (map[ID]an
 
 // resolveSubgraph extracts target nodes and their transitive dependencies from a registry.
 func resolveSubgraph(registry map[ID]node, targets []ID) (map[ID]node, error) {
-	needed := make(map[ID]node)
-
-	var resolve func(id ID) error
-	resolve = func(id ID) error {
-		if _, already := needed[id]; already {
-			return nil
-		}
-
-		n, ok := registry[id]
-		if !ok {
-			return fmt.Errorf("unknown node: %s", id)
+	g := nodeDAG(registry)
+	roots := make([]string, len(targets))
+	for i, id := range targets {
+		if !g.Has(string(id)) {
+			return nil, fmt.Errorf("unknown node: %s", id)
 		}
-
-		needed[id] = n
-
-		for _, dep := range n.dependsOn {
-			if err := resolve(dep); err != nil {
-				return err
-			}
-		}
-		return nil
+		roots[i] = string(id)
 	}
 
-	for _, id := range targets {
-		if err := resolve(id); err != nil {
-			return nil, err
+	sub := g.Subgraph(roots...)
+	needed := make(map[ID]node)
+	for _, id := range sub.Nodes() {
+		for _, dep := range sub.Deps(id) {
+			if !sub.Has(dep) {
+				return nil, fmt.Errorf("unknown node: %s", dep)
+			}
 		}
+		needed[ID(id)] = registry[ID(id)]
 	}
 
 	return needed, nil
@@ -300,89 +525,158 @@ func resolveSubgraph(registry map[ID]node, targets []ID) (map[ID]node, error) {
 // engine manages the dependency graph and orchestrates execution.
 type engine struct {
 	nodes          map[ID]node
-	results        results
+	results        Results
 	mu             sync.RWMutex
 	cache          Cache
 	ignoreCacheFor map[ID]bool
+	cacheKey       func(id ID) ID
+	metrics        MetricsSink
+	stats          *ExecutionStats
+	startHooks     []func(id ID, level int)
+	completeHooks  []func(id ID, level int, d time.Duration, err error)
+	cacheHitHooks  []func(id ID)
+	levelHooks     []func(level int, results Results)
+
+	circuitBreakers map[ID]CircuitBreakerPolicy
+	parallelGroups  [][]ID
+	replay          *replay
+	fallbacks       map[ID]any
+	stagger         func() time.Duration
+	recoverPanics   bool
+	dryRun          bool
+	resultHooks     []func(id ID, output any)
+	nodeOrder       *nodeOrder // nil unless levels run sequentially
+	preHooks        []func(ctx context.Context, nodes []NodeSummary) error
+	postHooks       []func(ctx context.Context, results Results, err error)
 }
 
 func newEngine(nodes map[ID]node, cfg *config) *engine {
+	var order *nodeOrder
+	if cfg.nodeOrder != nil {
+		order = newNodeOrder(cfg.registry, cfg.nodeOrder)
+	}
+
 	return &engine{
 		nodes:          nodes,
-		results:        make(results),
+		results:        make(Results),
 		cache:          cfg.cache,
 		ignoreCacheFor: cfg.ignoreCacheFor,
+		cacheKey:       cfg.cacheKey,
+		metrics:        cfg.metrics,
+		stats:          cfg.stats,
+		startHooks:     cfg.startHooks,
+		completeHooks:  cfg.completeHooks,
+		cacheHitHooks:  cfg.cacheHitHooks,
+		levelHooks:     cfg.levelHooks,
+
+		circuitBreakers: cfg.circuitBreakers,
+		parallelGroups:  cfg.parallelGroups,
+		replay:          cfg.replay,
+		fallbacks:       cfg.fallbacks,
+		stagger:         cfg.stagger,
+		recoverPanics:   cfg.recoverPanics,
+		dryRun:          cfg.dryRun,
+		resultHooks:     cfg.resultHooks,
+		nodeOrder:       order,
+		preHooks:        cfg.preHooks,
+		postHooks:       cfg.postHooks,
 	}
 }
 
-func (e *engine) run(ctx context.Context) error {
+func (e *engine) run(ctx context.Context) (err error) {
 	levels, err := topoSortLevels(e.nodes)
 	if err != nil {
 		return err
 	}
+	levels, err = applyParallelGroups(e.nodes, levels, e.parallelGroups)
+	if err != nil {
+		return err
+	}
+	if e.nodeOrder != nil && e.nodeOrder.err != nil {
+		return e.nodeOrder.err
+	}
+	if e.dryRun {
+		e.storeZeroResults()
+		return nil
+	}
 
-	for _, level := range levels {
+	if err := e.beforeExecute(ctx); err != nil {
+		return err
+	}
+	// Hooks get the caller's context, not the one cancelled on return
+	defer func(ctx context.Context) { e.afterExecute(ctx, err) }(ctx)
+	if e.replay != nil {
+		levels = e.seedReplay(levels)
+	}
+
+	// Let RunWithContext nodes cancel the rest of the execution
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = context.WithValue(ctx, cancelKey{}, context.CancelFunc(cancel))
+
+	if e.stats != nil {
+		*e.stats = ExecutionStats{Nodes: make(map[ID]NodeStats, len(e.nodes))}
+		start := time.Now()
+		defer func() { e.stats.Total = time.Since(start) }()
+	}
+
+	for levelIdx, level := range levels {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		if err := e.runLevel(ctx, level); err != nil {
+		if err := e.runLevel(ctx, levelIdx, level); err != nil {
 			return err
 		}
+		e.levelCompleted(levelIdx)
+	}
+
+	// Persist buffered cache writes for caches that support it
+	if fc, ok := e.cache.(FlushableCache); ok {
+		if err := fc.Flush(); err != nil {
+			return fmt.Errorf("cache flush: %w", err)
+		}
 	}
 
 	return nil
 }
 
-func (e *engine) runLevel(ctx context.Context, level []ID) error {
+func (e *engine) runLevel(ctx context.Context, levelIdx int, level []ID) error {
+	if e.nodeOrder != nil {
+		return e.runLevelInOrder(ctx, levelIdx, level)
+	}
+
+	prefetched, err := e.prefetchLevel(ctx, level)
+	if err != nil {
+		return err
+	}
+
 	var wg sync.WaitGroup
 	errCh := make(chan error, len(level))
 
-	for _, id := range level {
+	for i, id := range level {
+		// Don't start more nodes once the context is done
+		if err := ctx.Err(); err != nil {
+			errCh <- err
+			break
+		}
+		if e.stagger != nil && i > 0 {
+			if err := e.staggerLaunch(ctx); err != nil {
+				errCh <- err
+				break
+			}
+		}
+
 		wg.Add(1)
 		go func(nodeID ID) {
 			defer wg.Done()
-
-			n := e.nodes[nodeID]
-
-			// Check cache for cacheable nodes (unless explicitly ignored)
-			useCache := e.cache != nil && n.cacheable && !e.ignoreCacheFor[nodeID]
-			if useCache {
-				if val, found, err := e.cache.Get(ctx, nodeID); err != nil {
-					errCh <- fmt.Errorf("node %s: cache get: %w", nodeID, err)
-					return
-				} else if found {
-					e.mu.Lock()
-					e.results[nodeID] = val
-					e.mu.Unlock()
-					return // Cache hit - skip execution
-				}
-			}
-
-			// Build context with current results snapshot
-			e.mu.RLock()
-			nodeCtx := withResults(ctx, e.copyResults())
-			e.mu.RUnlock()
-
-			// Execute node
-			output, err := n.run(nodeCtx)
+			e.nodeStarted(nodeID, levelIdx)
+			start := time.Now()
+			err := e.runNodeRecovered(ctx, nodeID, prefetched)
+			e.nodeCompleted(nodeID, levelIdx, time.Since(start), err)
 			if err != nil {
-				errCh <- fmt.Errorf("node %s: %w", nodeID, err)
-				return
+				errCh <- err
 			}
-
-			// Write to cache for cacheable nodes
-			if useCache {
-				if err := e.cache.Set(ctx, nodeID, output); err != nil {
-					errCh <- fmt.Errorf("node %s: cache set: %w", nodeID, err)
-					return
-				}
-			}
-
-			// Store result
-			e.mu.Lock()
-			e.results[nodeID] = output
-			e.mu.Unlock()
 		}(id)
 	}
 
@@ -397,8 +691,189 @@ func (e *engine) runLevel(ctx context.Context, level []ID) error {
 	return nil
 }
 
-func (e *engine) copyResults() results {
-	cp := make(results, len(e.results))
+// usesCache reports whether a node's output is read from and written to the
+// cache.
+func (e *engine) usesCache(id ID) bool {
+	return e.cache != nil && e.nodes[id].cacheable && !e.ignoreCacheFor[id]
+}
+
+// prefetchLevel fetches the cached outputs of a level's cacheable nodes with
+// a single GetMulti call when the cache is a [MultiCache]. It returns nil if
+// the cache has no batch support, in which case each node reads its own
+// entry.
+func (e *engine) prefetchLevel(ctx context.Context, level []ID) (map[ID]any, error) {
+	mc, ok := e.cache.(MultiCache)
+	if !ok {
+		return nil, nil
+	}
+
+	var keys []ID
+	for _, id := range level {
+		if e.usesCache(id) {
+			keys = append(keys, e.cacheKeyFor(id))
+		}
+	}
+	if len(keys) == 0 {
+		return map[ID]any{}, nil
+	}
+
+	hits, err := mc.GetMulti(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("cache get: %w", err)
+	}
+	return hits, nil
+}
+
+// runNode resolves a single node's output from the cache or by executing it,
+// and stores the output in the engine's results. prefetched holds the
+// level's cache hits when they were fetched in one batch, keyed by cache key.
+func (e *engine) runNode(ctx context.Context, nodeID ID, prefetched map[ID]any) error {
+	n := e.nodes[nodeID]
+
+	// Check cache for cacheable nodes (unless explicitly ignored)
+	useCache := e.usesCache(nodeID)
+	key := e.cacheKeyFor(nodeID)
+	if useCache {
+		var val any
+		var found bool
+		if prefetched != nil {
+			val, found = prefetched[key]
+		} else {
+			var err error
+			val, found, err = e.cache.Get(ctx, key)
+			if err != nil {
+				e.recordExecution(nodeID, false, err)
+				return fmt.Errorf("node %s: cache get: %w", nodeID, err)
+			}
+		}
+		if found {
+			e.nodeCacheHit(nodeID)
+			e.storeResult(nodeID, val, 0, true)
+			e.recordExecution(nodeID, true, nil)
+			return nil // Cache hit - skip execution
+		}
+	}
+
+	// Build context with current results snapshot
+	e.mu.RLock()
+	nodeCtx := withResults(ctx, e.copyResults())
+	e.mu.RUnlock()
+
+	// Respect the node's cross-engine concurrency limit
+	if n.concurrency > 0 {
+		release, err := acquireNodeSlot(ctx, nodeID, n.concurrency)
+		if err != nil {
+			e.recordExecution(nodeID, false, err)
+			return fmt.Errorf("node %s: %w", nodeID, err)
+		}
+		defer release()
+	}
+
+	// Execute node, unless its circuit breaker is open
+	var output any
+	var err error
+	served := false
+	start := time.Now()
+	if policy, ok := e.circuitBreakers[nodeID]; ok {
+		output, served, err = circuitBreakers.get(nodeID).call(nodeCtx, policy, n.run)
+	} else {
+		output, err = n.run(nodeCtx)
+	}
+	duration := time.Since(start)
+	if !served {
+		e.recordDuration(nodeID, duration)
+	}
+
+	// Outputs served by an open circuit breaker are treated like recovered
+	// values and not cached
+	recovered := served
+	if err != nil && n.onError != nil {
+		output, err = n.onError(nodeCtx, err)
+		recovered = err == nil
+	}
+	if err != nil {
+		if fallback, ok := e.fallbackFor(nodeID, err); ok {
+			output, err, recovered = fallback, nil, true
+		}
+	}
+	if err != nil {
+		e.recordExecution(nodeID, false, err)
+		return fmt.Errorf("node %s: %w", nodeID, err)
+	}
+
+	// Write to cache for cacheable nodes (recovered zero values are not cached)
+	if useCache && !recovered {
+		if err := e.cache.Set(ctx, key, output); err != nil {
+			e.recordExecution(nodeID, false, err)
+			return fmt.Errorf("node %s: cache set: %w", nodeID, err)
+		}
+		if tc, ok := e.cache.(TaggedCache); ok && len(n.cacheTags) > 0 {
+			if err := tc.Tag(ctx, key, n.cacheTags...); err != nil {
+				e.recordExecution(nodeID, false, err)
+				return fmt.Errorf("node %s: cache tag: %w", nodeID, err)
+			}
+		}
+	}
+
+	e.storeResult(nodeID, output, duration, false)
+	e.recordExecution(nodeID, false, nil)
+	return nil
+}
+
+// nodeSlots holds a semaphore per node ID and limit, shared by all
+// executions in the process, for nodes that set Concurrency.
+var nodeSlots sync.Map // nodeSlotKey -> chan struct{}
+
+// nodeSlotKey identifies a semaphore. Keying on the limit as well as the ID
+// means a node replaced by Reload or Patch with a different Concurrency
+// gets a semaphore of the new size instead of reusing the old one.
+type nodeSlotKey struct {
+	id    ID
+	limit int
+}
+
+// acquireNodeSlot blocks until fewer than limit instances of the node are
+// running under the same limit, or ctx is done. The semaphore is created
+// lazily on first use.
+func acquireNodeSlot(ctx context.Context, id ID, limit int) (release func(), err error) {
+	v, ok := nodeSlots.Load(nodeSlotKey{id, limit})
+	if !ok {
+		v, _ = nodeSlots.LoadOrStore(nodeSlotKey{id, limit}, make(chan struct{}, limit))
+	}
+	sem := v.(chan struct{})
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// storeResult records a node's output and, if enabled, its statistics,
+// then passes the output to the result hooks.
+func (e *engine) storeResult(id ID, output any, d time.Duration, cacheHit bool) {
+	e.mu.Lock()
+	e.results[id] = output
+	e.recordStats(id, d, cacheHit)
+	e.mu.Unlock()
+
+	for _, hook := range e.resultHooks {
+		hook(id, output)
+	}
+}
+
+// cacheKeyFor returns the cache key for a node, applying any configured
+// key derivation.
+func (e *engine) cacheKeyFor(id ID) ID {
+	if e.cacheKey == nil {
+		return id
+	}
+	return e.cacheKey(id)
+}
+
+func (e *engine) copyResults() Results {
+	cp := make(Results, len(e.results))
 	for k, v := range e.results {
 		cp[k] = v
 	}
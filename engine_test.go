@@ -3,6 +3,7 @@ package graft
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -216,6 +217,61 @@ func TestExecuteContextCancelledBetweenLevels(t *testing.T) {
 	}
 }
 
+func TestWithDeadlineOption(t *testing.T) {
+	type tc struct {
+		deadline time.Time
+		run      func(ctx context.Context) (any, error)
+		wantErr  error
+	}
+
+	tests := map[string]tc{
+		"deadline in the future": {
+			deadline: time.Now().Add(time.Minute),
+			run:      func(ctx context.Context) (any, error) { return "done", nil },
+		},
+		"deadline already passed": {
+			deadline: time.Now().Add(-time.Second),
+			run: func(ctx context.Context) (any, error) {
+				t.Error("node should not have run - deadline already passed")
+				return "done", nil
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		"long-running node is interrupted": {
+			deadline: time.Now().Add(20 * time.Millisecond),
+			run: func(ctx context.Context) (any, error) {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(5 * time.Second):
+					return "done", nil
+				}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := map[ID]node{
+				"a": makeNode("a", nil, tt.run),
+			}
+
+			_, err := Execute(context.Background(), WithRegistry(nodes), WithDeadline(tt.deadline))
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestExecuteFor(t *testing.T) {
 	type tc struct {
 		registry    map[ID]node
@@ -926,3 +982,477 @@ func TestPatchOption(t *testing.T) {
 		t.Error("db.Connected = false, want true (patched logic should have set it based on config)")
 	}
 }
+
+type ctxTestKey struct{ name string }
+
+func TestWithContextValuesOption(t *testing.T) {
+	type tc struct {
+		opts []Option
+		want map[ctxTestKey]any
+	}
+
+	tests := map[string]tc{
+		"map of values": {
+			opts: []Option{WithContextValues(map[any]any{
+				ctxTestKey{"tenant"}: "acme",
+				ctxTestKey{"user"}:   42,
+			})},
+			want: map[ctxTestKey]any{{"tenant"}: "acme", {"user"}: 42},
+		},
+		"single value": {
+			opts: []Option{WithContextValue(ctxTestKey{"tenant"}, "acme")},
+			want: map[ctxTestKey]any{{"tenant"}: "acme"},
+		},
+		"later value overrides earlier": {
+			opts: []Option{
+				WithContextValue(ctxTestKey{"tenant"}, "first"),
+				WithContextValue(ctxTestKey{"tenant"}, "second"),
+			},
+			want: map[ctxTestKey]any{{"tenant"}: "second"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := make(map[ctxTestKey]any)
+			nodes := map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) {
+					for k := range tt.want {
+						got[k] = ctx.Value(k)
+					}
+					return nil, nil
+				}),
+			}
+
+			opts := append([]Option{WithRegistry(nodes)}, tt.opts...)
+			if _, err := Execute(context.Background(), opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("ctx.Value(%v) = %v, want %v", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithContextValueExecuteFor(t *testing.T) {
+	ResetRegistry()
+	defer ResetRegistry()
+
+	Register(Node[string]{
+		ID: "tenant",
+		Run: func(ctx context.Context) (string, error) {
+			tenant, _ := ctx.Value(ctxTestKey{"tenant"}).(string)
+			return tenant, nil
+		},
+	})
+
+	out, _, err := ExecuteFor[string](context.Background(),
+		DisableCache(),
+		WithContextValue(ctxTestKey{"tenant"}, "acme"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "acme" {
+		t.Errorf("got %q, want %q", out, "acme")
+	}
+}
+
+var errOnErrorSentinel = errors.New("sentinel")
+
+func TestNodeOnError(t *testing.T) {
+	type tc struct {
+		onError     func(ctx context.Context, err error) error
+		wantErr     error
+		wantErrStr  string
+		wantDownRun bool
+		wantValue   int
+	}
+
+	tests := map[string]tc{
+		"no handler propagates error": {
+			wantErrStr: "node failing: boom",
+		},
+		"handler replaces error": {
+			onError: func(ctx context.Context, err error) error {
+				return errOnErrorSentinel
+			},
+			wantErr:    errOnErrorSentinel,
+			wantErrStr: "node failing: sentinel",
+		},
+		"handler wraps error": {
+			onError: func(ctx context.Context, err error) error {
+				return fmt.Errorf("wrapped: %w", err)
+			},
+			wantErrStr: "node failing: wrapped: boom",
+		},
+		"handler recovers with zero value": {
+			onError: func(ctx context.Context, err error) error {
+				return nil
+			},
+			wantDownRun: true,
+			wantValue:   0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ResetRegistry()
+			defer ResetRegistry()
+
+			var downstreamRan atomic.Bool
+			Register(Node[int]{
+				ID: "failing",
+				Run: func(ctx context.Context) (int, error) {
+					return 99, errors.New("boom")
+				},
+				OnError: tt.onError,
+			})
+			Register(Node[string]{
+				ID:        "downstream",
+				DependsOn: []ID{"failing"},
+				Run: func(ctx context.Context) (string, error) {
+					downstreamRan.Store(true)
+					v, err := Dep[int](ctx)
+					return fmt.Sprint(v), err
+				},
+			})
+
+			results, err := Execute(context.Background(), DisableCache())
+			if tt.wantErrStr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErrStr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrStr) {
+					t.Errorf("error %q should contain %q", err.Error(), tt.wantErrStr)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error %v should wrap %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if downstreamRan.Load() != tt.wantDownRun {
+				t.Errorf("downstream ran = %v, want %v", downstreamRan.Load(), tt.wantDownRun)
+			}
+			if results["failing"] != tt.wantValue {
+				t.Errorf("failing result = %v, want %v", results["failing"], tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestNodeOnErrorRecoveredNotCached(t *testing.T) {
+	ResetRegistry()
+	defer ResetRegistry()
+
+	var runCount atomic.Int32
+	Register(Node[int]{
+		ID:        "flaky",
+		Cacheable: true,
+		Run: func(ctx context.Context) (int, error) {
+			if runCount.Add(1) == 1 {
+				return 0, errors.New("transient")
+			}
+			return 42, nil
+		},
+		OnError: func(ctx context.Context, err error) error { return nil },
+	})
+
+	cache := NewMemoryCache()
+	for i := 0; i < 2; i++ {
+		if _, err := Execute(context.Background(), WithCache(cache)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	val, found, _ := cache.Get(context.Background(), "flaky")
+	if !found || val != 42 {
+		t.Errorf("cache = (%v, %v), want (42, true): recovered zero value should not be cached", val, found)
+	}
+}
+
+func TestNodeConcurrencyAcrossExecutions(t *testing.T) {
+	type tc struct {
+		concurrency int
+		executions  int
+		wantMax     int32
+	}
+
+	tests := map[string]tc{
+		"limit of one serializes executions": {
+			concurrency: 1,
+			executions:  5,
+			wantMax:     1,
+		},
+		"limit of two": {
+			concurrency: 2,
+			executions:  6,
+			wantMax:     2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetGlobalState()
+			defer resetGlobalState()
+
+			var running, maxRunning atomic.Int32
+			id := ID("limited-" + strings.ReplaceAll(name, " ", "-"))
+			Register(Node[int]{
+				ID:          id,
+				Concurrency: tt.concurrency,
+				Run: func(ctx context.Context) (int, error) {
+					n := running.Add(1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					running.Add(-1)
+					return 1, nil
+				},
+			})
+
+			errCh := make(chan error, tt.executions)
+			for i := 0; i < tt.executions; i++ {
+				go func() {
+					_, _, err := ExecuteFor[int](context.Background(), DisableCache())
+					errCh <- err
+				}()
+			}
+			for i := 0; i < tt.executions; i++ {
+				if err := <-errCh; err != nil {
+					t.Fatalf("ExecuteFor error: %v", err)
+				}
+			}
+
+			if got := maxRunning.Load(); got != tt.wantMax {
+				t.Errorf("max concurrent runs = %d, want %d", got, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestNodeConcurrencyChangedLimit(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	var running, maxRunning atomic.Int32
+	newNode := func(limit int) Node[int] {
+		return Node[int]{
+			ID:          "changed-limit",
+			Concurrency: limit,
+			Run: func(ctx context.Context) (int, error) {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return 1, nil
+			},
+		}
+	}
+	runConcurrently := func(n int) {
+		errCh := make(chan error, n)
+		for i := 0; i < n; i++ {
+			go func() {
+				_, _, err := ExecuteFor[int](context.Background(), DisableCache())
+				errCh <- err
+			}()
+		}
+		for i := 0; i < n; i++ {
+			if err := <-errCh; err != nil {
+				t.Fatalf("ExecuteFor error: %v", err)
+			}
+		}
+	}
+
+	Register(newNode(1))
+	runConcurrently(3)
+	if got := maxRunning.Load(); got != 1 {
+		t.Fatalf("max concurrent runs with limit 1 = %d, want 1", got)
+	}
+
+	if err := Reload(newNode(3)); err != nil {
+		t.Fatalf("Reload error: %v", err)
+	}
+	maxRunning.Store(0)
+	runConcurrently(6)
+	if got := maxRunning.Load(); got != 3 {
+		t.Errorf("max concurrent runs after raising the limit to 3 = %d, want 3", got)
+	}
+}
+
+func TestNodeConcurrencyContextCancelled(t *testing.T) {
+	release, err := acquireNodeSlot(context.Background(), "cancel-test", 1)
+	if err != nil {
+		t.Fatalf("acquireNodeSlot error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	nodes := map[ID]node{
+		"cancel-test": {
+			id:          "cancel-test",
+			concurrency: 1,
+			run: func(ctx context.Context) (any, error) {
+				t.Error("node should not run while its only slot is held")
+				return nil, nil
+			},
+		},
+	}
+
+	_, err = Execute(ctx, WithRegistry(nodes))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestExecuteForMultiple(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	var configRuns atomic.Int32
+	Register(Node[testConfigOutput]{
+		ID: "test_config",
+		Run: func(ctx context.Context) (testConfigOutput, error) {
+			configRuns.Add(1)
+			return testConfigOutput{Host: "localhost", Port: 5432}, nil
+		},
+	})
+	Register(Node[testDBOutput]{
+		ID:        "test_db",
+		DependsOn: []ID{"test_config"},
+		Run: func(ctx context.Context) (testDBOutput, error) {
+			cfg, err := Dep[testConfigOutput](ctx)
+			if err != nil {
+				return testDBOutput{}, err
+			}
+			return testDBOutput{Connected: cfg.Host != "", PoolSize: 10}, nil
+		},
+	})
+	Register(Node[string]{
+		ID:        "test_name",
+		DependsOn: []ID{"test_config"},
+		Run:       func(ctx context.Context) (string, error) { return "graft", nil },
+	})
+	Register(Node[int]{
+		ID:  "test_unrelated",
+		Run: func(ctx context.Context) (int, error) { return 0, errors.New("should not run") },
+	})
+
+	t.Run("two outputs share dependencies", func(t *testing.T) {
+		configRuns.Store(0)
+		db, cfg, results, err := ExecuteForMultiple[testDBOutput, testConfigOutput](context.Background(), DisableCache())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !db.Connected || cfg.Host != "localhost" {
+			t.Errorf("got db=%+v cfg=%+v", db, cfg)
+		}
+		if len(results) != 2 {
+			t.Errorf("got %d results, want 2", len(results))
+		}
+		if got := configRuns.Load(); got != 1 {
+			t.Errorf("config ran %d times, want 1", got)
+		}
+	})
+
+	t.Run("three outputs", func(t *testing.T) {
+		db, name, cfg, results, err := ExecuteForMultiple3[testDBOutput, string, testConfigOutput](context.Background(), DisableCache())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !db.Connected || name != "graft" || cfg.Port != 5432 {
+			t.Errorf("got db=%+v name=%q cfg=%+v", db, name, cfg)
+		}
+		if len(results) != 3 {
+			t.Errorf("got %d results, want 3", len(results))
+		}
+	})
+
+	t.Run("unregistered type", func(t *testing.T) {
+		_, _, _, err := ExecuteForMultiple[testDBOutput, float64](context.Background())
+		if err == nil || !strings.Contains(err.Error(), "float64 not registered") {
+			t.Errorf("expected unregistered type error, got: %v", err)
+		}
+	})
+}
+
+func TestNodeRunWithContext(t *testing.T) {
+	type tc struct {
+		callCancel bool
+		wantErr    error
+		wantConn   any
+	}
+
+	tests := map[string]tc{
+		"returns output like Run": {
+			wantConn: "open",
+		},
+		"cancel stops dependents": {
+			callCancel: true,
+			wantErr:    context.Canceled,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var consumerRan atomic.Bool
+			nodes := map[ID]node{
+				"conn": {
+					id: "conn",
+					run: eraseRun(Node[string]{
+						ID: "conn",
+						RunWithContext: func(ctx context.Context, cancel context.CancelFunc) (string, error) {
+							if tt.callCancel {
+								cancel()
+							}
+							return "open", nil
+						},
+					}),
+				},
+				"consumer": makeNode("consumer", []ID{"conn"}, func(ctx context.Context) (any, error) {
+					consumerRan.Store(true)
+					return nil, nil
+				}),
+			}
+
+			results, err := Execute(context.Background(), WithRegistry(nodes), DisableCache())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got: %v", tt.wantErr, err)
+				}
+				if consumerRan.Load() {
+					t.Error("dependent node should not run after cancel")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if results["conn"] != tt.wantConn {
+				t.Errorf("results[conn] = %v, want %v", results["conn"], tt.wantConn)
+			}
+			if !consumerRan.Load() {
+				t.Error("dependent node should run when cancel is not called")
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package envconfig provides a graft node that loads a configuration struct
+// from environment variables, using only the standard library.
+//
+// Each exported field is read from PREFIX_FIELD_NAME, where FIELD_NAME is
+// the field name in upper snake case (DatabaseURL becomes DATABASE_URL). An
+// `env:"NAME"` tag replaces the derived name, and `env:"-"` skips the field.
+// Nested structs extend the prefix with their own field name.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port        int           // APP_PORT
+//	    DatabaseURL string        // APP_DATABASE_URL
+//	    Timeout     time.Duration // APP_TIMEOUT, e.g. "5s"
+//	    Hosts       []string      // APP_HOSTS, comma-separated
+//	}
+//
+//	graft.Register(envconfig.Node[Config]("config", "APP",
+//	    envconfig.WithDefaults(Config{Port: 8080}),
+//	))
+package envconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/grindlemire/graft"
+)
+
+// Option configures a node built by [Node].
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	defaults  T
+	validator func(T) error
+}
+
+// WithDefaults sets the values used for fields whose environment variable
+// is unset.
+//
+// Example:
+//
+//	envconfig.Node[Config]("config", "APP", envconfig.WithDefaults(Config{Port: 8080}))
+func WithDefaults[T any](defaults T) Option[T] {
+	return func(o *options[T]) {
+		o.defaults = defaults
+	}
+}
+
+// WithValidator checks the loaded configuration. An error from fn fails the
+// node.
+//
+// Example:
+//
+//	envconfig.Node[Config]("config", "APP", envconfig.WithValidator(func(c Config) error {
+//	    if c.DatabaseURL == "" {
+//	        return errors.New("APP_DATABASE_URL is required")
+//	    }
+//	    return nil
+//	}))
+func WithValidator[T any](fn func(T) error) Option[T] {
+	return func(o *options[T]) {
+		o.validator = fn
+	}
+}
+
+// Node returns a cacheable node with the given ID whose Run loads T, which
+// must be a struct, from environment variables starting with prefix
+// followed by an underscore. An empty prefix reads FIELD_NAME directly.
+//
+// Run fails if a variable cannot be parsed into its field, if a field has
+// an unsupported type, or if the validator rejects the result.
+//
+// Example:
+//
+//	graft.Register(envconfig.Node[Config]("config", "APP"))
+func Node[T any](id graft.ID, prefix string, opts ...Option[T]) graft.Node[T] {
+	var o options[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	description := "configuration loaded from environment variables"
+	if prefix != "" {
+		description = fmt.Sprintf("configuration loaded from %s_* environment variables", prefix)
+	}
+
+	return graft.Node[T]{
+		ID:          id,
+		Cacheable:   true,
+		Description: description,
+		Run: func(ctx context.Context) (T, error) {
+			cfg := o.defaults
+			v := reflect.ValueOf(&cfg).Elem()
+			if v.Kind() != reflect.Struct {
+				var zero T
+				return zero, fmt.Errorf("envconfig: %s is not a struct", v.Type())
+			}
+			if err := load(v, prefix); err != nil {
+				var zero T
+				return zero, err
+			}
+			if o.validator != nil {
+				if err := o.validator(cfg); err != nil {
+					var zero T
+					return zero, err
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// durationType is handled before the int64 kind it is based on.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// load sets the exported fields of the struct v from the environment.
+func load(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := envName(field.Name)
+		if tag, ok := field.Tag.Lookup("env"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			if err := load(fv, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(fv, raw); err != nil {
+			return fmt.Errorf("envconfig: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setValue parses raw into v according to its type.
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		if raw == "" {
+			parts = nil
+		}
+		s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(s.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// envName converts a Go field name to upper snake case, keeping acronyms
+// together: DatabaseURL becomes DATABASE_URL and HTTPPort HTTP_PORT.
+func envName(field string) string {
+	runes := []rune(field)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package envconfig
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/graft"
+)
+
+type dbConfig struct {
+	URL      string
+	MaxConns uint8
+}
+
+type testConfig struct {
+	Port     int
+	Debug    bool
+	Ratio    float64
+	Timeout  time.Duration
+	Hosts    []string
+	HTTPAddr string
+	Secret   string `env:"SECRET_KEY"`
+	Ignored  string `env:"-"`
+	DB       dbConfig
+	internal string
+}
+
+func TestNode(t *testing.T) {
+	type tc struct {
+		env       map[string]string
+		opts      []Option[testConfig]
+		want      testConfig
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"all fields": {
+			env: map[string]string{
+				"APP_PORT":         "9090",
+				"APP_DEBUG":        "true",
+				"APP_RATIO":        "0.5",
+				"APP_TIMEOUT":      "5s",
+				"APP_HOSTS":        "a, b,c",
+				"APP_HTTP_ADDR":    ":80",
+				"APP_SECRET_KEY":   "s3cret",
+				"APP_IGNORED":      "nope",
+				"APP_DB_URL":       "postgres://db",
+				"APP_DB_MAX_CONNS": "20",
+			},
+			want: testConfig{
+				Port:     9090,
+				Debug:    true,
+				Ratio:    0.5,
+				Timeout:  5 * time.Second,
+				Hosts:    []string{"a", "b", "c"},
+				HTTPAddr: ":80",
+				Secret:   "s3cret",
+				DB:       dbConfig{URL: "postgres://db", MaxConns: 20},
+			},
+		},
+		"defaults fill unset variables": {
+			env:  map[string]string{"APP_PORT": "9090"},
+			opts: []Option[testConfig]{WithDefaults(testConfig{Port: 8080, Debug: true})},
+			want: testConfig{Port: 9090, Debug: true},
+		},
+		"parse error": {
+			env:       map[string]string{"APP_DB_MAX_CONNS": "300"},
+			errSubstr: "envconfig: APP_DB_MAX_CONNS",
+		},
+		"validator": {
+			opts: []Option[testConfig]{WithValidator(func(c testConfig) error {
+				if c.Port == 0 {
+					return errors.New("APP_PORT is required")
+				}
+				return nil
+			})},
+			errSubstr: "APP_PORT is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			n := Node[testConfig]("config", "APP", tt.opts...)
+			if !n.Cacheable {
+				t.Errorf("node is not cacheable")
+			}
+
+			got, err := n.Run(context.Background())
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeExecute(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+	t.Setenv("PORT", "7070")
+
+	graft.Register(Node[struct{ Port int }]("config", ""))
+	out, _, err := graft.ExecuteFor[struct{ Port int }](context.Background(), graft.DisableCache())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Port != 7070 {
+		t.Errorf("Port = %d, want 7070", out.Port)
+	}
+}
+
+func TestNodeRejectsNonStruct(t *testing.T) {
+	_, err := Node[int]("port", "APP").Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "is not a struct") {
+		t.Errorf("error = %v, want a not-a-struct error", err)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"Port":        "PORT",
+		"DatabaseURL": "DATABASE_URL",
+		"HTTPPort":    "HTTP_PORT",
+		"MaxConns2":   "MAX_CONNS2",
+		"ID":          "ID",
+	}
+	for in, want := range tests {
+		if got := envName(in); got != want {
+			t.Errorf("envName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
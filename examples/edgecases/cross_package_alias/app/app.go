@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/examples/edgecases/cross_package_alias/config"
+)
+
+type Output struct {
+	Endpoint string
+}
+
+// Consumer accesses the dependency through the alias
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (Output, error) {
+			cfg, _ := graft.Dep[config.Output](ctx)
+			return Output{Endpoint: cfg.Endpoint}, nil
+		},
+	})
+}
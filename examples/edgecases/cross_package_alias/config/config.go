@@ -0,0 +1,21 @@
+package config
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/examples/edgecases/cross_package_alias/thirdparty"
+)
+
+// Output is an alias for a type defined in another package
+type Output = thirdparty.Settings
+
+// Producer registers its output through the alias
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID: "config",
+		Run: func(ctx context.Context) (Output, error) {
+			return Output{Endpoint: "localhost:8080"}, nil
+		},
+	})
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	_ "github.com/grindlemire/graft/examples/edgecases/cross_package_alias/app"
+	_ "github.com/grindlemire/graft/examples/edgecases/cross_package_alias/config"
+	_ "github.com/grindlemire/graft/examples/edgecases/cross_package_alias/report"
+)
+
+func main() {
+	// This main package exists to ensure all nodes are loaded during analysis
+}
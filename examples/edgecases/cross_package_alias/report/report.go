@@ -0,0 +1,25 @@
+package report
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+	"github.com/grindlemire/graft/examples/edgecases/cross_package_alias/thirdparty"
+)
+
+type Output struct {
+	Summary string
+}
+
+// Consumer accesses the dependency through the aliased type directly,
+// so the analyzer must see through config.Output to match it
+func init() {
+	graft.Register(graft.Node[Output]{
+		ID:        "report",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (Output, error) {
+			s, _ := graft.Dep[thirdparty.Settings](ctx)
+			return Output{Summary: s.Endpoint}, nil
+		},
+	})
+}
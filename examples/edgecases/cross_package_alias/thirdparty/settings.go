@@ -0,0 +1,7 @@
+package thirdparty
+
+// Settings is defined in a third package that neither the producer nor the
+// consumers own
+type Settings struct {
+	Endpoint string
+}
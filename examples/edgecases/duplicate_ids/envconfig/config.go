@@ -0,0 +1,22 @@
+package envconfig
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+// Config is loaded from the environment
+type Config struct {
+	Prefix string
+}
+
+// Registers "config" - the same ID as fileconfig
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID: "config",
+		Run: func(ctx context.Context) (Config, error) {
+			return Config{Prefix: "APP_"}, nil
+		},
+	})
+}
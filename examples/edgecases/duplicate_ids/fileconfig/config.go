@@ -0,0 +1,23 @@
+package fileconfig
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+// Config is loaded from a file
+type Config struct {
+	Path string
+}
+
+// Registers "config" - so does envconfig, which panics at runtime when
+// both packages are linked into the same binary
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID: "config",
+		Run: func(ctx context.Context) (Config, error) {
+			return Config{Path: "config.yaml"}, nil
+		},
+	})
+}
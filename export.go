@@ -0,0 +1,84 @@
+package graft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// nodeSpec is the serialized form of a node used by [RegistryToJSON] and
+// [ValidateRegistryJSON].
+type nodeSpec struct {
+	ID          ID       `json:"id"`
+	DependsOn   []ID     `json:"depends_on"`
+	Cacheable   bool     `json:"cacheable"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// nodeSpecs returns the registry selected by opts as specs sorted by ID.
+func nodeSpecs(opts ...Option) []nodeSpec {
+	summaries := ListNodes(opts...)
+	specs := make([]nodeSpec, len(summaries))
+	for i, s := range summaries {
+		specs[i] = nodeSpec{
+			ID:          s.ID,
+			DependsOn:   s.DependsOn,
+			Cacheable:   s.Cacheable,
+			Description: s.Description,
+			Tags:        s.Tags,
+		}
+	}
+	return specs
+}
+
+// RegistryToJSON serializes node metadata (id, depends_on, cacheable,
+// description, tags) as a JSON array sorted by ID. Run functions are not
+// included.
+//
+// By default, serializes the global registry. Use [WithRegistry] for a custom registry.
+//
+// Example:
+//
+//	data, err := graft.RegistryToJSON()
+//	os.WriteFile("graph.json", data, 0o644)
+func RegistryToJSON(opts ...Option) ([]byte, error) {
+	return json.MarshalIndent(nodeSpecs(opts...), "", "  ")
+}
+
+// ValidateRegistryJSON checks that data describes a valid dependency graph
+// in the format produced by [RegistryToJSON]: every node has a non-empty,
+// unique id, every depends_on entry names a node in the document, and the
+// graph is acyclic. Unknown fields are rejected so that misspelled keys are
+// not silently ignored.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("graph.json")
+//	if err := graft.ValidateRegistryJSON(data); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateRegistryJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var specs []nodeSpec
+	if err := dec.Decode(&specs); err != nil {
+		return fmt.Errorf("decode registry: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("decode registry: unexpected data after node list")
+	}
+
+	nodes := make(map[ID]node, len(specs))
+	for i, s := range specs {
+		if s.ID == "" {
+			return fmt.Errorf("node %d has no id", i+1)
+		}
+		if _, ok := nodes[s.ID]; ok {
+			return fmt.Errorf("duplicate node id %s", s.ID)
+		}
+		nodes[s.ID] = node{id: s.ID, dependsOn: s.DependsOn}
+	}
+
+	return CheckGraphAcyclic(WithRegistry(nodes))
+}
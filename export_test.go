@@ -0,0 +1,118 @@
+package graft
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exportTestRegistry() map[ID]node {
+	return map[ID]node{
+		"config": {id: "config", cacheable: true, description: "App config", tags: []string{"core"}},
+		"db":     {id: "db", dependsOn: []ID{"config"}, description: `Postgres "primary"`, tags: []string{"storage", "core"}},
+		"app":    {id: "app", dependsOn: []ID{"config", "db"}},
+	}
+}
+
+func TestRegistryToJSON(t *testing.T) {
+	data, err := RegistryToJSON(WithRegistry(exportTestRegistry()))
+	if err != nil {
+		t.Fatalf("RegistryToJSON error: %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, data)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(got))
+	}
+
+	want := map[string]any{
+		"id":          "db",
+		"depends_on":  []any{"config"},
+		"cacheable":   false,
+		"description": `Postgres "primary"`,
+		"tags":        []any{"storage", "core"},
+	}
+	if !reflect.DeepEqual(got[2], want) {
+		t.Errorf("db = %v, want %v", got[2], want)
+	}
+	if got[0]["id"] != "app" || got[1]["id"] != "config" {
+		t.Errorf("nodes not sorted by id: %v", got)
+	}
+}
+
+func TestValidateRegistryJSON(t *testing.T) {
+	type tc struct {
+		json    string
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"valid graph": {
+			json: `[{"id": "config", "cacheable": true}, {"id": "db", "depends_on": ["config"], "tags": ["storage"]}]`,
+		},
+		"empty graph": {
+			json: `[]`,
+		},
+		"unknown dependency": {
+			json:    `[{"id": "db", "depends_on": ["config"]}]`,
+			wantErr: "node db depends on unknown node config",
+		},
+		"cycle": {
+			json:    `[{"id": "a", "depends_on": ["b"]}, {"id": "b", "depends_on": ["a"]}]`,
+			wantErr: "cycle detected: a -> b -> a",
+		},
+		"duplicate id": {
+			json:    `[{"id": "a"}, {"id": "a"}]`,
+			wantErr: "duplicate node id a",
+		},
+		"missing id": {
+			json:    `[{"id": "a"}, {"cacheable": true}]`,
+			wantErr: "node 2 has no id",
+		},
+		"unknown field": {
+			json:    `[{"id": "a", "run": "main.go"}]`,
+			wantErr: `unknown field "run"`,
+		},
+		"not a list": {
+			json:    `{"nodes": []}`,
+			wantErr: "decode registry",
+		},
+		"invalid cacheable": {
+			json:    `[{"id": "a", "cacheable": "maybe"}]`,
+			wantErr: "decode registry",
+		},
+		"trailing data": {
+			json:    `[] []`,
+			wantErr: "unexpected data after node list",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRegistryJSON([]byte(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryToJSONValidates(t *testing.T) {
+	data, err := RegistryToJSON(WithRegistry(exportTestRegistry()))
+	if err != nil {
+		t.Fatalf("RegistryToJSON error: %v", err)
+	}
+	if err := ValidateRegistryJSON(data); err != nil {
+		t.Errorf("output does not validate: %v", err)
+	}
+}
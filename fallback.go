@@ -0,0 +1,53 @@
+package graft
+
+// WithFallback makes the node producing T resolve to fallback when it fails,
+// so that dependents calling Dep[T] receive fallback instead of the
+// execution stopping. The node's OnError handler, if any, runs first and the
+// fallback only applies to the error it returns.
+//
+// The original error is recorded in [ExecutionStats.FallbackErrors] when
+// [WithStats] is used. Fallback values are never cached.
+//
+// This is a no-op if type T is not registered.
+//
+// Example:
+//
+//	var stats graft.ExecutionStats
+//	results, err := graft.Execute(ctx,
+//	    graft.WithFallback(recommendations.Output{Items: nil}),
+//	    graft.WithStats(&stats),
+//	)
+//	if err := stats.FallbackErrors[recommendations.ID]; err != nil {
+//	    log.Printf("recommendations unavailable: %v", err)
+//	}
+func WithFallback[T any](fallback T) Option {
+	return func(c *config) {
+		id, ok := idForType((*T)(nil))
+		if !ok {
+			return
+		}
+		if c.fallbacks == nil {
+			c.fallbacks = make(map[ID]any)
+		}
+		c.fallbacks[id] = fallback
+	}
+}
+
+// fallbackFor returns the configured fallback output for a failed node and
+// records the error it replaces.
+func (e *engine) fallbackFor(id ID, err error) (any, bool) {
+	fallback, ok := e.fallbacks[id]
+	if !ok {
+		return nil, false
+	}
+
+	if e.stats != nil {
+		e.mu.Lock()
+		if e.stats.FallbackErrors == nil {
+			e.stats.FallbackErrors = make(map[ID]error)
+		}
+		e.stats.FallbackErrors[id] = err
+		e.mu.Unlock()
+	}
+	return fallback, true
+}
@@ -0,0 +1,132 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fallbackTestRecs struct{ Items []string }
+type fallbackTestPage struct{ Items []string }
+
+func TestWithFallback(t *testing.T) {
+	type tc struct {
+		recsErr      error
+		onError      func(ctx context.Context, err error) error
+		opts         []Option
+		wantErr      string
+		wantItems    []string
+		wantFallback error
+	}
+
+	boom := errors.New("boom")
+	fallback := WithFallback(fallbackTestRecs{Items: []string{"default"}})
+
+	tests := map[string]tc{
+		"success ignores fallback": {
+			opts:      []Option{fallback},
+			wantItems: []string{"live"},
+		},
+		"failure uses fallback": {
+			recsErr:      boom,
+			opts:         []Option{fallback},
+			wantItems:    []string{"default"},
+			wantFallback: boom,
+		},
+		"failure without fallback": {
+			recsErr: boom,
+			wantErr: "node recs: boom",
+		},
+		"fallback applies to OnError result": {
+			recsErr: boom,
+			onError: func(ctx context.Context, err error) error {
+				return errors.New("wrapped")
+			},
+			opts:         []Option{fallback},
+			wantItems:    []string{"default"},
+			wantFallback: errors.New("wrapped"),
+		},
+		"unregistered type is a no-op": {
+			recsErr: boom,
+			opts:    []Option{WithFallback(struct{ unregistered bool }{})},
+			wantErr: "node recs: boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ResetRegistry()
+			t.Cleanup(ResetRegistry)
+
+			Register(Node[fallbackTestRecs]{
+				ID: "recs",
+				Run: func(ctx context.Context) (fallbackTestRecs, error) {
+					if tt.recsErr != nil {
+						return fallbackTestRecs{}, tt.recsErr
+					}
+					return fallbackTestRecs{Items: []string{"live"}}, nil
+				},
+				OnError: tt.onError,
+			})
+			Register(Node[fallbackTestPage]{
+				ID:        "page",
+				DependsOn: []ID{"recs"},
+				Run: func(ctx context.Context) (fallbackTestPage, error) {
+					recs, err := Dep[fallbackTestRecs](ctx)
+					if err != nil {
+						return fallbackTestPage{}, err
+					}
+					return fallbackTestPage{Items: recs.Items}, nil
+				},
+			})
+
+			var stats ExecutionStats
+			opts := append([]Option{DisableCache(), WithStats(&stats)}, tt.opts...)
+			page, _, err := ExecuteFor[fallbackTestPage](context.Background(), opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(page.Items, ",") != strings.Join(tt.wantItems, ",") {
+				t.Errorf("page.Items = %v, want %v", page.Items, tt.wantItems)
+			}
+			gotFallback := stats.FallbackErrors["recs"]
+			if (gotFallback == nil) != (tt.wantFallback == nil) ||
+				(gotFallback != nil && gotFallback.Error() != tt.wantFallback.Error()) {
+				t.Errorf("FallbackErrors[recs] = %v, want %v", gotFallback, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestWithFallbackNotCached(t *testing.T) {
+	ResetRegistry()
+	t.Cleanup(ResetRegistry)
+
+	Register(Node[fallbackTestRecs]{
+		ID:        "recs",
+		Cacheable: true,
+		Run: func(ctx context.Context) (fallbackTestRecs, error) {
+			return fallbackTestRecs{}, errors.New("down")
+		},
+	})
+
+	cache := NewMemoryCache()
+	_, _, err := ExecuteFor[fallbackTestRecs](context.Background(),
+		WithCache(cache),
+		WithFallback(fallbackTestRecs{Items: []string{"default"}}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found, _ := cache.Get(context.Background(), "recs"); found {
+		t.Error("fallback output should not be cached")
+	}
+}
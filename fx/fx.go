@@ -0,0 +1,90 @@
+// Package fx exposes graft node outputs to go.uber.org/fx applications.
+//
+// [Module] executes the graft graph once while the fx application is built
+// and provides the resulting [graft.Results]. [Provide] and [Invoke] then
+// hand individual node outputs to fx constructors and invocations.
+package fx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/grindlemire/graft"
+	"go.uber.org/fx"
+)
+
+// Module returns an fx option that executes the graft graph with opts and
+// provides its [graft.Results]. Like every fx constructor it runs at most
+// once per application, so each node output is effectively a singleton.
+//
+// Execution happens while fx builds the application, before any OnStart
+// hooks run. Use [graft.WithDeadline] to bound it.
+//
+// Example:
+//
+//	app := fx.New(
+//	    graftfx.Module(),
+//	    graftfx.Provide[config.Output](),
+//	    fx.Invoke(func(cfg config.Output) { ... }),
+//	)
+func Module(opts ...graft.Option) fx.Option {
+	return fx.Module("graft",
+		fx.Provide(func() (graft.Results, error) {
+			return graft.Execute(context.Background(), opts...)
+		}),
+	)
+}
+
+// Provide makes the output of the node producing type T available to fx
+// constructors as a T. It requires [Module].
+//
+// Example:
+//
+//	fx.New(graftfx.Module(), graftfx.Provide[db.Output](), fx.Invoke(startServer))
+func Provide[T any]() fx.Option {
+	return fx.Provide(func(results graft.Results) (T, error) {
+		return graft.Result[T](results)
+	})
+}
+
+// Invoke calls fn with the output of node id when the fx application
+// starts. fn must be a function taking exactly one argument that the node
+// output is assignable to, and may return a single error. It requires
+// [Module].
+//
+// Example:
+//
+//	graftfx.Invoke("config", func(cfg config.Output) error {
+//	    return cfg.Validate()
+//	})
+func Invoke(id graft.ID, fn any) fx.Option {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.NumOut() > 1 ||
+		(ft.NumOut() == 1 && ft.Out(0) != errType) {
+		return fx.Error(fmt.Errorf("graft fx: Invoke %s: fn must be func(T) or func(T) error, got %s", id, ft))
+	}
+
+	return fx.Invoke(func(results graft.Results) error {
+		out, ok := results[id]
+		if !ok {
+			return fmt.Errorf("graft fx: node %s has no result", id)
+		}
+
+		arg := reflect.ValueOf(out)
+		if !arg.IsValid() {
+			arg = reflect.Zero(ft.In(0))
+		}
+		if !arg.Type().AssignableTo(ft.In(0)) {
+			return fmt.Errorf("graft fx: node %s output %s is not assignable to %s", id, arg.Type(), ft.In(0))
+		}
+
+		ret := fv.Call([]reflect.Value{arg})
+		if len(ret) == 1 && !ret[0].IsNil() {
+			return ret[0].Interface().(error)
+		}
+		return nil
+	})
+}
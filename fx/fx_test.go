@@ -0,0 +1,150 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"go.uber.org/fx"
+)
+
+type configOutput struct{ Port int }
+type appOutput struct{ Addr string }
+
+func registerNodes(t *testing.T, runs *int) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID: "config",
+		Run: func(ctx context.Context) (configOutput, error) {
+			*runs++
+			return configOutput{Port: 8080}, nil
+		},
+	})
+	graft.Register(graft.Node[appOutput]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (appOutput, error) {
+			cfg, err := graft.Dep[configOutput](ctx)
+			if err != nil {
+				return appOutput{}, err
+			}
+			return appOutput{Addr: fmt.Sprintf(":%d", cfg.Port)}, nil
+		},
+	})
+}
+
+func TestProvide(t *testing.T) {
+	var runs int
+	registerNodes(t, &runs)
+
+	var cfg configOutput
+	var app appOutput
+	fxApp := fx.New(
+		fx.NopLogger,
+		Module(graft.DisableCache()),
+		Provide[configOutput](),
+		Provide[appOutput](),
+		fx.Populate(&cfg, &app),
+	)
+	if err := fxApp.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Port != 8080 {
+		t.Errorf("config port = %d, want 8080", cfg.Port)
+	}
+	if app.Addr != ":8080" {
+		t.Errorf("app addr = %q, want %q", app.Addr, ":8080")
+	}
+	if runs != 1 {
+		t.Errorf("config ran %d times, want 1", runs)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	type tc struct {
+		id        graft.ID
+		fn        any
+		errSubstr string
+	}
+
+	var got configOutput
+	tests := map[string]tc{
+		"typed argument": {
+			id: "config",
+			fn: func(cfg configOutput) { got = cfg },
+		},
+		"interface argument": {
+			id: "config",
+			fn: func(v any) error { got = v.(configOutput); return nil },
+		},
+		"fn error": {
+			id:        "config",
+			fn:        func(configOutput) error { return errors.New("invalid config") },
+			errSubstr: "invalid config",
+		},
+		"unknown node": {
+			id:        "missing",
+			fn:        func(configOutput) {},
+			errSubstr: "node missing has no result",
+		},
+		"wrong argument type": {
+			id:        "config",
+			fn:        func(appOutput) {},
+			errSubstr: "not assignable",
+		},
+		"not a function": {
+			id:        "config",
+			fn:        42,
+			errSubstr: "fn must be",
+		},
+		"too many arguments": {
+			id:        "config",
+			fn:        func(configOutput, appOutput) {},
+			errSubstr: "fn must be",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var runs int
+			registerNodes(t, &runs)
+			got = configOutput{}
+
+			err := fx.New(fx.NopLogger, Module(graft.DisableCache()), Invoke(tt.id, tt.fn)).Err()
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Port != 8080 {
+				t.Errorf("invoked with %+v, want port 8080", got)
+			}
+		})
+	}
+}
+
+func TestModuleExecutionError(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{}, errors.New("boom") },
+	})
+
+	err := fx.New(fx.NopLogger, Module(graft.DisableCache()), Provide[configOutput](), fx.Invoke(func(configOutput) {})).Err()
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("error = %v, want execution error", err)
+	}
+}
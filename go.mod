@@ -2,9 +2,9 @@ module github.com/grindlemire/graft
 
 go 1.25.1
 
-require golang.org/x/tools v0.40.0
-
 require (
-	golang.org/x/mod v0.31.0 // indirect
-	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/mod v0.31.0
+	golang.org/x/tools v0.40.0
 )
+
+require golang.org/x/sync v0.19.0 // indirect
@@ -66,6 +66,7 @@ package graft
 import (
 	"context"
 	"fmt"
+	"reflect"
 )
 
 // contextKey is the type for context keys used by graft.
@@ -108,11 +109,60 @@ type Node[T any] struct {
 	// Dependencies are accessed via Dep[T](ctx).
 	Run func(ctx context.Context) (T, error)
 
+	// RunWithContext is an alternative to Run for nodes that need to stop the
+	// rest of the execution, e.g. to tear down long-lived connections before
+	// dependents use them. Calling cancel cancels the execution context:
+	// nodes that have not started are skipped, running nodes observe
+	// ctx.Done(), and the execution fails with context.Canceled.
+	//
+	// Run and RunWithContext are mutually exclusive.
+	RunWithContext func(ctx context.Context, cancel context.CancelFunc) (T, error)
+
 	// Cacheable indicates whether this node's output should be cached.
 	// When true and a cache is provided via WithCache, the node's output
 	// is stored after first execution and reused on subsequent runs.
 	// Default is false (not cached).
 	Cacheable bool
+
+	// CacheTags optionally labels this node's cached output so that it can be
+	// evicted as a group (e.g., MemoryCache.Invalidate("config") on a config
+	// change event). Tags are recorded only when the cache implements
+	// TaggedCache.
+	CacheTags []string
+
+	// OnError optionally handles errors returned by Run before they are
+	// propagated. It can wrap, log, or convert the error (e.g., turning a
+	// network timeout into a sentinel error). Returning nil recovers the node:
+	// it is treated as successful and produces the zero value of T.
+	// Recovered outputs are never written to the cache.
+	OnError func(ctx context.Context, err error) error
+
+	// Description is an optional human-readable summary of what the node
+	// produces. It is surfaced by introspection helpers such as ListNodes.
+	Description string
+
+	// Tags are optional free-form labels used to group nodes for
+	// introspection and documentation.
+	Tags []string
+
+	// Concurrency limits how many instances of this node's Run function may
+	// execute simultaneously across all executions in the process (e.g., to
+	// respect a rate-limited API). Zero means unlimited.
+	//
+	// The limit is tracked per node ID. When the node is replaced with a
+	// different Concurrency (see [Reload] and [Patch]), later executions
+	// are limited by the new value; runs still in flight under the old
+	// limit are not counted against it.
+	Concurrency int
+
+	// Deprecated marks the node as deprecated when non-empty, with a message
+	// telling users what to depend on instead. [AnalyzeDir] warns about
+	// every node that declares a deprecated node in DependsOn.
+	//
+	// Example:
+	//
+	//	Deprecated: "use the pgx node (ID \"pgxdb\") instead",
+	Deprecated string
 }
 
 // node is the internal type-erased representation used for storage.
@@ -122,19 +172,99 @@ type node struct {
 	dependsOn []ID
 	run       func(ctx context.Context) (any, error)
 	cacheable bool
+	cacheTags []string
+
+	// onError handles a Run error, returning the zero output on recovery.
+	onError func(ctx context.Context, err error) (any, error)
+
+	description string
+	tags        []string
+	concurrency int
+	deprecated  string
+
+	// outputType is T for a Node[T], or nil if unknown.
+	outputType reflect.Type
 }
 
-// results is the internal type for storing node outputs in context.
-type results map[ID]any
+// cancelKey is the context key for the execution's cancel function.
+type cancelKey struct{}
+
+// eraseRun converts a node's Run or RunWithContext function into the
+// type-erased form. RunWithContext receives the execution's cancel function,
+// which the engine stores in the context.
+func eraseRun[T any](n Node[T]) func(ctx context.Context) (any, error) {
+	if n.Run != nil && n.RunWithContext != nil {
+		panic("graft: node " + string(n.ID) + " sets both Run and RunWithContext")
+	}
+	if n.RunWithContext != nil {
+		return func(ctx context.Context) (any, error) {
+			cancel, ok := ctx.Value(cancelKey{}).(context.CancelFunc)
+			if !ok {
+				cancel = func() {}
+			}
+			return n.RunWithContext(ctx, cancel)
+		}
+	}
+	return func(ctx context.Context) (any, error) {
+		return n.Run(ctx)
+	}
+}
+
+// eraseNode converts a typed Node[T] into its type-erased form with the
+// given ID.
+func eraseNode[T any](id ID, n Node[T]) node {
+	return node{
+		id:          id,
+		dependsOn:   n.DependsOn,
+		run:         eraseRun(n),
+		cacheable:   n.Cacheable,
+		cacheTags:   n.CacheTags,
+		onError:     eraseOnError[T](n.OnError),
+		description: n.Description,
+		tags:        n.Tags,
+		concurrency: n.Concurrency,
+		deprecated:  n.Deprecated,
+		outputType:  reflect.TypeOf((*T)(nil)).Elem(),
+	}
+}
+
+// eraseOnError converts a typed OnError handler into its type-erased form.
+// On recovery the erased handler returns the zero value of T so that
+// dependents can still assert the output to T.
+func eraseOnError[T any](fn func(ctx context.Context, err error) error) func(ctx context.Context, err error) (any, error) {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, err error) (any, error) {
+		var zero T
+		if err := fn(ctx, err); err != nil {
+			return nil, err
+		}
+		return zero, nil
+	}
+}
+
+// Results holds node outputs keyed by node ID. It is returned by [Execute]
+// and the ExecuteFor functions, and is also how dependency outputs are
+// passed to nodes through the context.
+//
+// Go does not allow type parameters on methods, so typed access goes
+// through the package-level [Result] function rather than a method.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx)
+//	cfg, err := graft.Result[config.Output](results)
+type Results map[ID]any
 
 // withResults adds results to a context for downstream node access.
-func withResults(ctx context.Context, r results) context.Context {
+func withResults(ctx context.Context, r Results) context.Context {
 	return context.WithValue(ctx, resultsKey, r)
 }
 
 // getResults retrieves results from context.
-func getResults(ctx context.Context) (results, bool) {
-	r, ok := ctx.Value(resultsKey).(results)
+func getResults(ctx context.Context) (Results, bool) {
+	r, ok := ctx.Value(resultsKey).(Results)
 	return r, ok
 }
 
@@ -150,6 +280,8 @@ func getResults(ctx context.Context) (results, bool) {
 //   - The dependency is not found (not declared in DependsOn)
 //   - The dependency's output cannot be asserted to type T
 //
+// The error is a [*DepError] whose Code identifies which case occurred.
+//
 // Example:
 //
 //	func(ctx context.Context) (MyOutput, error) {
@@ -162,24 +294,24 @@ func getResults(ctx context.Context) (results, bool) {
 func Dep[T any](ctx context.Context) (T, error) {
 	var zero T
 
-	id, ok := typeToID[(*T)(nil)]
+	id, ok := idForType((*T)(nil))
 	if !ok {
-		return zero, fmt.Errorf("graft: type %T not registered as node output", zero)
+		return zero, &DepError{Code: ErrNotRegistered, Message: fmt.Sprintf("type %s not registered as node output", typeName[T]())}
 	}
 
 	r, ok := getResults(ctx)
 	if !ok {
-		return zero, fmt.Errorf("graft: no results in context")
+		return zero, &DepError{Code: ErrNoContext, Message: "no results in context"}
 	}
 
 	val, ok := r[id]
 	if !ok {
-		return zero, fmt.Errorf("graft: dependency %q not found", id)
+		return zero, &DepError{Code: ErrNotFound, NodeID: id, Message: fmt.Sprintf("dependency %q not found", id)}
 	}
 
-	typed, ok := val.(T)
+	typed, ok := assertOutput[T](val)
 	if !ok {
-		return zero, fmt.Errorf("graft: dependency %q has wrong type (got %T, want %T)", id, val, zero)
+		return zero, &DepError{Code: ErrWrongType, NodeID: id, Message: fmt.Sprintf("dependency %q has wrong type (got %T, want %s)", id, val, typeName[T]())}
 	}
 
 	return typed, nil
@@ -196,31 +328,53 @@ func Dep[T any](ctx context.Context) (T, error) {
 //   - The node is not found in the results
 //   - The output cannot be asserted to type T
 //
+// The error is a [*DepError] whose Code identifies which case occurred.
+//
 // Example:
 //
 //	results, _ := graft.Execute(ctx)
 //	cfg, err := graft.Result[config.Output](results)
-func Result[T any](r results) (T, error) {
+func Result[T any](r Results) (T, error) {
 	var zero T
 
-	id, ok := typeToID[(*T)(nil)]
+	id, ok := idForType((*T)(nil))
 	if !ok {
-		return zero, fmt.Errorf("graft: type %T not registered as node output", zero)
+		return zero, &DepError{Code: ErrNotRegistered, Message: fmt.Sprintf("type %s not registered as node output", typeName[T]())}
 	}
 
 	val, ok := r[id]
 	if !ok {
-		return zero, fmt.Errorf("graft: result %q not found", id)
+		return zero, &DepError{Code: ErrNotFound, NodeID: id, Message: fmt.Sprintf("result %q not found", id)}
 	}
 
-	typed, ok := val.(T)
+	typed, ok := assertOutput[T](val)
 	if !ok {
-		return zero, fmt.Errorf("graft: result %q has wrong type (got %T, want %T)", id, val, zero)
+		return zero, &DepError{Code: ErrWrongType, NodeID: id, Message: fmt.Sprintf("result %q has wrong type (got %T, want %s)", id, val, typeName[T]())}
 	}
 
 	return typed, nil
 }
 
+// typeName returns the name of T for error messages. Unlike %T of a zero
+// value, it names interface types instead of printing <nil>.
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// assertOutput converts a node output to T. A nil output converts to the
+// zero value when T is an interface type, since a node whose output type
+// is an interface may return a nil interface value.
+func assertOutput[T any](val any) (T, bool) {
+	if typed, ok := val.(T); ok {
+		return typed, true
+	}
+	var zero T
+	if val == nil && reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Interface {
+		return zero, true
+	}
+	return zero, false
+}
+
 // depByID is an internal helper for testing that retrieves a dependency by explicit ID.
 // This is needed for tests that use makeNode() to create nodes without going through Register.
 func depByID[T any](ctx context.Context, nodeID ID) (T, error) {
@@ -228,17 +382,17 @@ func depByID[T any](ctx context.Context, nodeID ID) (T, error) {
 
 	r, ok := getResults(ctx)
 	if !ok {
-		return zero, fmt.Errorf("graft: no results in context")
+		return zero, &DepError{Code: ErrNoContext, Message: "no results in context"}
 	}
 
 	val, ok := r[nodeID]
 	if !ok {
-		return zero, fmt.Errorf("graft: dependency %q not found", nodeID)
+		return zero, &DepError{Code: ErrNotFound, NodeID: nodeID, Message: fmt.Sprintf("dependency %q not found", nodeID)}
 	}
 
-	typed, ok := val.(T)
+	typed, ok := assertOutput[T](val)
 	if !ok {
-		return zero, fmt.Errorf("graft: dependency %q has wrong type (got %T, want %T)", nodeID, val, zero)
+		return zero, &DepError{Code: ErrWrongType, NodeID: nodeID, Message: fmt.Sprintf("dependency %q has wrong type (got %T, want %s)", nodeID, val, typeName[T]())}
 	}
 
 	return typed, nil
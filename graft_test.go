@@ -2,6 +2,7 @@ package graft
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -16,7 +17,7 @@ func TestDepByID(t *testing.T) {
 	}
 
 	// Setup contexts for tests
-	ctxWithResults := withResults(context.Background(), results{
+	ctxWithResults := withResults(context.Background(), Results{
 		"stringNode": "hello",
 		"intNode":    42,
 		"nilNode":    nil,
@@ -78,14 +79,14 @@ func TestDepByIDGenericTypes(t *testing.T) {
 	}
 
 	type tc struct {
-		results   results
+		results   Results
 		nodeID    ID
 		checkFunc func(t *testing.T, got any)
 	}
 
 	tests := map[string]tc{
 		"int type": {
-			results: results{"node": 42},
+			results: Results{"node": 42},
 			nodeID:  "node",
 			checkFunc: func(t *testing.T, got any) {
 				if got.(int) != 42 {
@@ -94,7 +95,7 @@ func TestDepByIDGenericTypes(t *testing.T) {
 			},
 		},
 		"slice type": {
-			results: results{"node": []string{"a", "b", "c"}},
+			results: Results{"node": []string{"a", "b", "c"}},
 			nodeID:  "node",
 			checkFunc: func(t *testing.T, got any) {
 				slice := got.([]string)
@@ -104,7 +105,7 @@ func TestDepByIDGenericTypes(t *testing.T) {
 			},
 		},
 		"struct type": {
-			results: results{"node": customStruct{Name: "test", Value: 100}},
+			results: Results{"node": customStruct{Name: "test", Value: 100}},
 			nodeID:  "node",
 			checkFunc: func(t *testing.T, got any) {
 				s := got.(customStruct)
@@ -114,7 +115,7 @@ func TestDepByIDGenericTypes(t *testing.T) {
 			},
 		},
 		"pointer type": {
-			results: results{"node": &customStruct{Name: "ptr", Value: 200}},
+			results: Results{"node": &customStruct{Name: "ptr", Value: 200}},
 			nodeID:  "node",
 			checkFunc: func(t *testing.T, got any) {
 				s := got.(*customStruct)
@@ -124,7 +125,7 @@ func TestDepByIDGenericTypes(t *testing.T) {
 			},
 		},
 		"map type": {
-			results: results{"node": map[string]int{"key": 1}},
+			results: Results{"node": map[string]int{"key": 1}},
 			nodeID:  "node",
 			checkFunc: func(t *testing.T, got any) {
 				m := got.(map[string]int)
@@ -181,17 +182,17 @@ func TestDepByIDGenericTypes(t *testing.T) {
 
 func TestWithResultsAndGetResults(t *testing.T) {
 	type tc struct {
-		inputResults results
+		inputResults Results
 		wantOK       bool
 	}
 
 	tests := map[string]tc{
 		"valid results": {
-			inputResults: results{"a": 1, "b": "two"},
+			inputResults: Results{"a": 1, "b": "two"},
 			wantOK:       true,
 		},
 		"empty results": {
-			inputResults: results{},
+			inputResults: Results{},
 			wantOK:       true,
 		},
 		"nil results": {
@@ -250,11 +251,11 @@ func TestDep(t *testing.T) {
 		},
 	})
 
-	ctxWithConfig := withResults(context.Background(), results{
+	ctxWithConfig := withResults(context.Background(), Results{
 		"dep_test_config": depTestConfig{Host: "localhost", Port: 5432},
 	})
-	ctxEmpty := withResults(context.Background(), results{})
-	ctxWrongType := withResults(context.Background(), results{
+	ctxEmpty := withResults(context.Background(), Results{})
+	ctxWrongType := withResults(context.Background(), Results{
 		"dep_test_config": "wrong type",
 	})
 
@@ -304,7 +305,7 @@ func TestDep(t *testing.T) {
 
 	// Separate test for unregistered type (requires different type parameter)
 	t.Run("type not registered", func(t *testing.T) {
-		ctx := withResults(context.Background(), results{})
+		ctx := withResults(context.Background(), Results{})
 		_, err := Dep[unregisteredType](ctx)
 		if err == nil {
 			t.Fatal("expected error for unregistered type")
@@ -317,7 +318,7 @@ func TestDep(t *testing.T) {
 
 func TestResult(t *testing.T) {
 	type tc struct {
-		results   results
+		results   Results
 		wantVal   depTestConfig
 		wantErr   bool
 		errSubstr string
@@ -336,17 +337,17 @@ func TestResult(t *testing.T) {
 
 	tests := map[string]tc{
 		"success": {
-			results: results{"dep_test_config": depTestConfig{Host: "testhost", Port: 1234}},
+			results: Results{"dep_test_config": depTestConfig{Host: "testhost", Port: 1234}},
 			wantVal: depTestConfig{Host: "testhost", Port: 1234},
 			wantErr: false,
 		},
 		"result not found": {
-			results:   results{},
+			results:   Results{},
 			wantErr:   true,
 			errSubstr: "not found",
 		},
 		"wrong type in results": {
-			results:   results{"dep_test_config": 12345},
+			results:   Results{"dep_test_config": 12345},
 			wantErr:   true,
 			errSubstr: "wrong type",
 		},
@@ -375,7 +376,7 @@ func TestResult(t *testing.T) {
 
 	// Separate test for unregistered type (requires different type parameter)
 	t.Run("type not registered", func(t *testing.T) {
-		_, err := Result[unregisteredType](results{})
+		_, err := Result[unregisteredType](Results{})
 		if err == nil {
 			t.Fatal("expected error for unregistered type")
 		}
@@ -384,3 +385,179 @@ func TestResult(t *testing.T) {
 		}
 	})
 }
+
+func TestDepErrorCodes(t *testing.T) {
+	ResetRegistry()
+	defer ResetRegistry()
+
+	Register(Node[depTestConfig]{
+		ID:  "dep_test_config",
+		Run: func(ctx context.Context) (depTestConfig, error) { return depTestConfig{}, nil },
+	})
+
+	type tc struct {
+		get        func() error
+		wantCode   DepErrorCode
+		wantNodeID ID
+	}
+
+	tests := map[string]tc{
+		"dep not registered": {
+			get: func() error {
+				_, err := Dep[unregisteredType](withResults(context.Background(), Results{}))
+				return err
+			},
+			wantCode: ErrNotRegistered,
+		},
+		"dep no context": {
+			get: func() error {
+				_, err := Dep[depTestConfig](context.Background())
+				return err
+			},
+			wantCode: ErrNoContext,
+		},
+		"dep not found": {
+			get: func() error {
+				_, err := Dep[depTestConfig](withResults(context.Background(), Results{}))
+				return err
+			},
+			wantCode:   ErrNotFound,
+			wantNodeID: "dep_test_config",
+		},
+		"result wrong type": {
+			get: func() error {
+				_, err := Result[depTestConfig](Results{"dep_test_config": 1})
+				return err
+			},
+			wantCode:   ErrWrongType,
+			wantNodeID: "dep_test_config",
+		},
+		"execute for not registered": {
+			get: func() error {
+				_, _, err := ExecuteFor[unregisteredType](context.Background())
+				return err
+			},
+			wantCode: ErrNotRegistered,
+		},
+		"wrapped by node error": {
+			get: func() error {
+				nodes := map[ID]node{
+					"app": makeNode("app", nil, func(ctx context.Context) (any, error) {
+						return Dep[depTestConfig](ctx)
+					}),
+				}
+				_, err := Execute(context.Background(), WithRegistry(nodes), DisableCache())
+				return err
+			},
+			wantCode:   ErrNotFound,
+			wantNodeID: "dep_test_config",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var depErr *DepError
+			if err := tt.get(); !errors.As(err, &depErr) {
+				t.Fatalf("expected *DepError, got %T: %v", err, err)
+			}
+			if depErr.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", depErr.Code, tt.wantCode)
+			}
+			if depErr.NodeID != tt.wantNodeID {
+				t.Errorf("NodeID = %q, want %q", depErr.NodeID, tt.wantNodeID)
+			}
+			if !strings.HasPrefix(depErr.Error(), "graft: ") {
+				t.Errorf("Error() = %q, want graft: prefix", depErr.Error())
+			}
+		})
+	}
+}
+
+// greeter is an interface output type for TestInterfaceOutputNode.
+type greeter interface {
+	Greet(name string) string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet(name string) string { return "hello " + name }
+
+type greetingOutput struct {
+	Message string
+}
+
+func TestInterfaceOutputNode(t *testing.T) {
+	type tc struct {
+		greeter greeter
+		wantMsg string
+		wantNil bool
+	}
+
+	tests := map[string]tc{
+		"concrete implementation": {
+			greeter: englishGreeter{},
+			wantMsg: "hello graft",
+		},
+		"nil interface output": {
+			greeter: nil,
+			wantMsg: "no greeter",
+			wantNil: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ResetRegistry()
+			t.Cleanup(ResetRegistry)
+
+			Register(Node[greeter]{
+				ID:  "greeter",
+				Run: func(ctx context.Context) (greeter, error) { return tt.greeter, nil },
+			})
+			Register(Node[greetingOutput]{
+				ID:        "greeting",
+				DependsOn: []ID{"greeter"},
+				Run: func(ctx context.Context) (greetingOutput, error) {
+					g, err := Dep[greeter](ctx)
+					if err != nil {
+						return greetingOutput{}, err
+					}
+					if g == nil {
+						return greetingOutput{Message: "no greeter"}, nil
+					}
+					return greetingOutput{Message: g.Greet("graft")}, nil
+				},
+			})
+
+			out, results, err := ExecuteFor[greetingOutput](context.Background(), DisableCache())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", out.Message, tt.wantMsg)
+			}
+
+			g, err := Result[greeter](results)
+			if err != nil {
+				t.Fatalf("Result[greeter] error: %v", err)
+			}
+			if (g == nil) != tt.wantNil {
+				t.Errorf("Result[greeter] = %v, want nil=%v", g, tt.wantNil)
+			}
+		})
+	}
+
+	t.Run("wrong type names the interface", func(t *testing.T) {
+		ResetRegistry()
+		t.Cleanup(ResetRegistry)
+		Register(Node[greeter]{
+			ID:  "greeter",
+			Run: func(ctx context.Context) (greeter, error) { return englishGreeter{}, nil },
+		})
+
+		_, err := Result[greeter](Results{"greeter": "not a greeter"})
+		if err == nil || !strings.Contains(err.Error(), "want graft.greeter") {
+			t.Errorf("expected wrong type error naming graft.greeter, got %v", err)
+		}
+	})
+}
@@ -3,11 +3,27 @@ package graft
 import (
 	"fmt"
 	"io"
-	"sort"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/graft/dag"
 )
 
 // PrintGraph outputs an ASCII representation of the dependency graph to the provided io.Writer.
+//
+// Boxes are drawn in ANSI colors when w looks like a terminal (see
+// [WithANSIColors]): an *os.File that is a character device. This is a
+// heuristic rather than a full isatty check, so other character devices
+// such as /dev/null count too. Writers that are not an *os.File, such as a
+// bytes.Buffer, never get colors unless WithANSIColors is passed.
 func PrintGraph(w io.Writer, opts ...Option) error {
+	return printASCIIGraph(w, opts, nil)
+}
+
+// printASCIIGraph renders the graph selected by opts to w. decorate, if
+// non-nil, adds overlays to the renderer before it draws.
+func printASCIIGraph(w io.Writer, opts []Option, decorate func(*graphRenderer)) error {
 	cfg := &config{registry: Registry()}
 	for _, opt := range opts {
 		opt(cfg)
@@ -24,12 +40,113 @@ func PrintGraph(w io.Writer, opts ...Option) error {
 	}
 
 	renderer := newGraphRenderer(cfg.registry, levels)
-	output := renderer.render()
-	fmt.Fprint(w, output)
+	renderer.colors = cfg.ansiColors || isTerminal(w)
+	if decorate != nil {
+		decorate(renderer)
+	}
+	fmt.Fprint(w, renderer.render())
 
 	return nil
 }
 
+// PrintGraphColored is like [PrintGraph] but always draws each level's boxes
+// in ANSI colors, even when w is not a terminal. See [WithANSIColors].
+//
+// Example:
+//
+//	graft.PrintGraphColored(os.Stderr)
+func PrintGraphColored(w io.Writer, opts ...Option) error {
+	return PrintGraph(w, append(opts, WithANSIColors())...)
+}
+
+// WithANSIColors makes the ASCII graph renderers draw node boxes in ANSI
+// colors by level: level 0 in cyan, level 1 in green, level 2 in yellow,
+// and so on, cycling through the palette. Cacheable nodes are drawn in
+// bold. Colors are applied on output only and never change the layout.
+//
+// Without this option, colors are used only when writing to what looks
+// like a terminal; see [PrintGraph]. Pass it to force colors, for example
+// when the output goes through a pager that interprets them.
+//
+// Example:
+//
+//	graft.PrintGraph(w, graft.WithANSIColors())
+func WithANSIColors() Option {
+	return func(c *config) {
+		c.ansiColors = true
+	}
+}
+
+// isTerminal reports whether w is an *os.File for a character device. It
+// stands in for an isatty check, which would need golang.org/x/term: every
+// terminal is a character device, but so are a few other files such as
+// /dev/null, where the escapes are harmless.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// PrintGraphWithStats is like [PrintGraph] but overlays timing from a
+// previous execution: each node's box shows its duration, and nodes on the
+// critical path (see [ExecutionStats.CriticalPath]) are drawn with
+// double-line borders and marked with ★. Nodes missing from stats are
+// drawn without timing.
+//
+// Example:
+//
+//	var stats graft.ExecutionStats
+//	_, err := graft.Execute(ctx, graft.WithStats(&stats))
+//	graft.PrintGraphWithStats(os.Stdout, stats)
+func PrintGraphWithStats(w io.Writer, stats ExecutionStats, opts ...Option) error {
+	return printASCIIGraph(w, opts, func(renderer *graphRenderer) {
+		renderer.durations = make(map[ID]time.Duration, len(stats.Nodes))
+		for id, ns := range stats.Nodes {
+			if _, ok := renderer.nodes[id]; ok {
+				renderer.durations[id] = ns.Duration
+			}
+		}
+		path, _ := stats.CriticalPath()
+		renderer.critical = make(map[ID]bool, len(path))
+		for _, id := range path {
+			renderer.critical[id] = true
+		}
+	})
+}
+
+// PrintGraphWithExecutionCounts is like [PrintGraph] but appends how many
+// times each node ran, e.g. "[3 runs]", to its box. Cacheable nodes with a
+// count of zero are shown as "[cached]". Nodes missing from counts are
+// treated as having run zero times.
+//
+// Example:
+//
+//	counts := make(map[graft.ID]int)
+//	for _, req := range requests {
+//	    var stats graft.ExecutionStats
+//	    graft.Execute(req.Context(), graft.WithStats(&stats))
+//	    for id, ns := range stats.Nodes {
+//	        if !ns.CacheHit {
+//	            counts[id]++
+//	        }
+//	    }
+//	}
+//	graft.PrintGraphWithExecutionCounts(os.Stdout, counts)
+func PrintGraphWithExecutionCounts(w io.Writer, counts map[ID]int, opts ...Option) error {
+	return printASCIIGraph(w, opts, func(renderer *graphRenderer) {
+		renderer.counts = make(map[ID]int, len(counts))
+		for id, n := range counts {
+			renderer.counts[id] = n
+		}
+	})
+}
+
 // PrintMermaid outputs a Mermaid diagram of the dependency graph to the provided io.Writer.
 func PrintMermaid(w io.Writer, opts ...Option) error {
 	cfg := &config{registry: Registry()}
@@ -43,14 +160,15 @@ func PrintMermaid(w io.Writer, opts ...Option) error {
 		return nil
 	}
 
-	for id, n := range cfg.registry {
-		for _, dep := range n.dependsOn {
+	ids := SortedIDs(cfg.registry)
+	for _, id := range ids {
+		for _, dep := range cfg.registry[id].dependsOn {
 			fmt.Fprintf(w, "    %s --> %s\n", dep, id)
 		}
 	}
 
-	for id, n := range cfg.registry {
-		if n.cacheable {
+	for _, id := range ids {
+		if cfg.registry[id].cacheable {
 			fmt.Fprintf(w, "    style %s fill:#e1f5fe\n", id)
 		}
 	}
@@ -58,64 +176,252 @@ func PrintMermaid(w io.Writer, opts ...Option) error {
 	return nil
 }
 
-// topoSortLevels computes topological levels using Kahn's algorithm.
-// Nodes are grouped into levels where all nodes in a level can execute concurrently.
-// Levels are sorted for deterministic output.
-func topoSortLevels(nodes map[ID]node) ([][]ID, error) {
-	inDegree := make(map[ID]int)
-	for id := range nodes {
-		inDegree[id] = 0
+// PrintDOT outputs a Graphviz DOT diagram of the dependency graph to the
+// provided io.Writer, with edges pointing from a dependency to the node that
+// depends on it.
+//
+// Example:
+//
+//	f, _ := os.Create("graph.dot")
+//	graft.PrintDOT(f)
+//	// dot -Tsvg graph.dot -o graph.svg
+func PrintDOT(w io.Writer, opts ...Option) error {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	for _, n := range nodes {
-		for _, dep := range n.dependsOn {
-			if _, exists := nodes[dep]; !exists {
-				return nil, fmt.Errorf("node %s depends on unknown node %s", n.id, dep)
+	g := make(DependencyGraph, len(cfg.registry))
+	for id, n := range cfg.registry {
+		deps := make([]string, len(n.dependsOn))
+		for i, dep := range n.dependsOn {
+			deps[i] = string(dep)
+		}
+		g[string(id)] = deps
+	}
+
+	fmt.Fprint(w, g.DOT())
+
+	return nil
+}
+
+// SprintGraph is like [PrintGraph] but returns the output as a string.
+//
+// Example:
+//
+//	out, err := graft.SprintGraph()
+//	if err != nil {
+//	    return err
+//	}
+//	w.Write([]byte(out))
+func SprintGraph(opts ...Option) (string, error) {
+	return sprint(PrintGraph, opts)
+}
+
+// SprintMermaid is like [PrintMermaid] but returns the output as a string.
+func SprintMermaid(opts ...Option) (string, error) {
+	return sprint(PrintMermaid, opts)
+}
+
+// SprintDOT is like [PrintDOT] but returns the output as a string.
+func SprintDOT(opts ...Option) (string, error) {
+	return sprint(PrintDOT, opts)
+}
+
+// sprint captures the output of a Print function in a string.
+func sprint(print func(io.Writer, ...Option) error, opts []Option) (string, error) {
+	var b strings.Builder
+	if err := print(&b, opts...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Levels returns the registered nodes grouped into the topological levels
+// Execute runs them in: each node depends only on nodes in earlier levels,
+// and the nodes of a level run concurrently. IDs within a level are sorted.
+// It returns an error if a dependency is unknown or the graph has a cycle.
+//
+// Example:
+//
+//	levels, err := graft.Levels()
+//	if err != nil {
+//	    return err
+//	}
+//	for i, ids := range levels {
+//	    fmt.Printf("level %d: %v\n", i, ids)
+//	}
+func Levels(opts ...Option) ([][]ID, error) {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return topoSortLevels(cfg.registry)
+}
+
+// NodeLevel returns the topological level of the node id, as reported by
+// [Levels]: 0 for nodes without dependencies, otherwise one more than the
+// highest level among its dependencies.
+//
+// Example:
+//
+//	level, err := graft.NodeLevel("api")
+func NodeLevel(id ID, opts ...Option) (int, error) {
+	levels, err := Levels(opts...)
+	if err != nil {
+		return 0, err
+	}
+	for i, ids := range levels {
+		for _, levelID := range ids {
+			if levelID == id {
+				return i, nil
 			}
 		}
-		inDegree[n.id] = len(n.dependsOn)
 	}
+	return 0, fmt.Errorf("unknown node: %s", id)
+}
 
-	dependents := make(map[ID][]ID)
-	for _, n := range nodes {
-		for _, dep := range n.dependsOn {
-			dependents[dep] = append(dependents[dep], n.id)
+// MaxLevel returns the highest topological level in the graph, or -1 if no
+// nodes are registered. Execute runs MaxLevel+1 levels one after another.
+//
+// Example:
+//
+//	max, err := graft.MaxLevel()
+//	fmt.Printf("%d sequential stages\n", max+1)
+func MaxLevel(opts ...Option) (int, error) {
+	levels, err := Levels(opts...)
+	if err != nil {
+		return 0, err
+	}
+	return len(levels) - 1, nil
+}
+
+// FindRoots returns the sorted IDs of the nodes with no dependencies, which
+// are the nodes of level 0 in [Levels]. Like Levels, it returns an error if
+// the graph has a cycle or a dependency on an unknown node.
+//
+// Example:
+//
+//	roots, err := graft.FindRoots()
+func FindRoots(opts ...Option) ([]ID, error) {
+	levels, err := Levels(opts...)
+	if err != nil || len(levels) == 0 {
+		return []ID{}, err
+	}
+	return levels[0], nil
+}
+
+// FindLeaves returns the sorted IDs of the nodes that no other node declares
+// in DependsOn: the terminal outputs of the graph. Errors are reported as by
+// [Levels].
+//
+// Example:
+//
+//	leaves, err := graft.FindLeaves()
+func FindLeaves(opts ...Option) ([]ID, error) {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if _, err := topoSortLevels(cfg.registry); err != nil {
+		return []ID{}, err
+	}
+
+	dependents := dependentCounts(cfg.registry)
+	leaves := []ID{}
+	for _, id := range SortedIDs(cfg.registry) {
+		if dependents[id] == 0 {
+			leaves = append(leaves, id)
 		}
 	}
+	return leaves, nil
+}
 
-	var currentLevel []ID
-	for id, degree := range inDegree {
-		if degree == 0 {
-			currentLevel = append(currentLevel, id)
+// FindOrphans returns the sorted IDs of isolated nodes: nodes that have no
+// dependencies and that no other node depends on. Errors are reported as by
+// [Levels].
+//
+// Example:
+//
+//	orphans, err := graft.FindOrphans()
+func FindOrphans(opts ...Option) ([]ID, error) {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	levels, err := topoSortLevels(cfg.registry)
+	if err != nil || len(levels) == 0 {
+		return []ID{}, err
+	}
+
+	dependents := dependentCounts(cfg.registry)
+	orphans := []ID{}
+	for _, id := range levels[0] {
+		if dependents[id] == 0 {
+			orphans = append(orphans, id)
 		}
 	}
+	return orphans, nil
+}
 
-	var levels [][]ID
-	processed := 0
-
-	for len(currentLevel) > 0 {
-		// Sort for deterministic output
-		sort.Slice(currentLevel, func(i, j int) bool {
-			return currentLevel[i] < currentLevel[j]
-		})
-		levels = append(levels, currentLevel)
-		processed += len(currentLevel)
-
-		var nextLevel []ID
-		for _, id := range currentLevel {
-			for _, dependent := range dependents[id] {
-				inDegree[dependent]--
-				if inDegree[dependent] == 0 {
-					nextLevel = append(nextLevel, dependent)
-				}
-			}
+// dependentCounts returns how many nodes declare each ID in DependsOn.
+func dependentCounts(nodes map[ID]node) map[ID]int {
+	counts := make(map[ID]int)
+	for _, n := range nodes {
+		for _, dep := range n.dependsOn {
+			counts[dep]++
 		}
-		currentLevel = nextLevel
+	}
+	return counts
+}
+
+// topoSortLevels computes topological levels using Kahn's algorithm.
+// Nodes are grouped into levels where all nodes in a level can execute concurrently.
+// Levels are sorted for deterministic output.
+func topoSortLevels(nodes map[ID]node) ([][]ID, error) {
+	levels, err := nodeDAG(nodes).Levels()
+	if err != nil {
+		return nil, err
 	}
 
-	if processed != len(nodes) {
-		return nil, fmt.Errorf("cycle detected in dependency graph")
+	out := make([][]ID, len(levels))
+	for i, level := range levels {
+		out[i] = toIDs(level)
 	}
+	return out, nil
+}
 
-	return levels, nil
+// findCycle returns one dependency cycle in nodes as a path that starts and
+// ends with the same ID (e.g., [a b a]), or nil if the graph is acyclic.
+// Dependencies on unknown nodes are ignored. Nodes are visited in sorted
+// order for deterministic output.
+func findCycle(nodes map[ID]node) []ID {
+	cycles := nodeDAG(nodes).Cycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	return toIDs(cycles[0])
+}
+
+// nodeDAG builds the dependency graph of nodes.
+func nodeDAG(nodes map[ID]node) *dag.DAG {
+	g := dag.New()
+	for id, n := range nodes {
+		deps := make([]string, len(n.dependsOn))
+		for i, dep := range n.dependsOn {
+			deps[i] = string(dep)
+		}
+		g.Add(string(id), deps...)
+	}
+	return g
+}
+
+// toIDs converts graph node IDs back to IDs.
+func toIDs(ids []string) []ID {
+	out := make([]ID, len(ids))
+	for i, id := range ids {
+		out[i] = ID(id)
+	}
+	return out
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // graphRenderer handles rendering the dependency graph to ASCII.
@@ -14,13 +16,17 @@ import (
 //
 // 1. Layout Phase (computeLayout):
 //   - Groups nodes by topological level (already computed)
-//   - Calculates node widths based on ID length and cacheable markers
+//   - Calculates node widths based on node labels (ID, cacheable marker, and
+//     optional timing and execution count overlays)
 //   - Positions nodes in a 2D grid, centering each level horizontally
 //   - Allocates vertical space: 3 rows per node box + 6 rows between levels
 //
 // 2. Node Drawing Phase (drawNodes):
 //   - Draws each node as a box using Unicode box-drawing characters
 //   - Adds cacheable markers (*) to node labels
+//   - With a timing overlay, adds durations to labels and draws critical
+//     path nodes with double-line borders and a ★ marker
+//   - With an execution count overlay, appends [N runs] (or [cached]) to labels
 //   - Places nodes at their computed grid positions
 //
 // 3. Edge Drawing Phase (drawEdges):
@@ -39,6 +45,17 @@ type graphRenderer struct {
 	nodes  map[ID]node
 	levels [][]ID
 
+	// Optional execution timing overlay (see PrintGraphWithStats)
+	durations map[ID]time.Duration
+	critical  map[ID]bool
+
+	// Optional execution count overlay (see PrintGraphWithExecutionCounts)
+	counts map[ID]int
+
+	// Optional ANSI coloring by level (see WithANSIColors)
+	colors bool
+	styles [][]string // SGR parameters per grid cell, parallel to grid
+
 	// Layout state
 	nodePositions map[ID]position // node ID -> (row, col) in grid
 	levelRows     map[int][]int   // level index -> list of row numbers
@@ -81,11 +98,7 @@ func (gr *graphRenderer) computeLayout() {
 	// Calculate node widths (including box borders)
 	nodeWidths := make(map[ID]int)
 	for id := range gr.nodes {
-		width := len(string(id))
-		if gr.nodes[id].cacheable {
-			width++ // Add space for * marker
-		}
-		width += 4 // Box borders: "│ " + " │"
+		width := gr.boxWidth(id)
 		if width < 7 {
 			width = 7 // Minimum width for readability
 		}
@@ -186,6 +199,10 @@ func (gr *graphRenderer) computeLayout() {
 	}
 
 	// Initialize grid
+	gr.styles = make([][]string, gr.maxRow+1)
+	for i := range gr.styles {
+		gr.styles[i] = make([]string, gr.maxCol+1)
+	}
 	gr.grid = make([][]rune, gr.maxRow+1)
 	for i := range gr.grid {
 		gr.grid[i] = make([]rune, gr.maxCol+1)
@@ -198,35 +215,114 @@ func (gr *graphRenderer) computeLayout() {
 // drawNodes draws the node boxes in the grid.
 func (gr *graphRenderer) drawNodes() {
 	for id, pos := range gr.nodePositions {
-		width := len(string(id))
-		if gr.nodes[id].cacheable {
-			width++
+		width := gr.boxWidth(id)
+
+		// Critical path nodes use double-line borders
+		topLeft, topRight, bottomLeft, bottomRight, horizontal, vertical := '┌', '┐', '└', '┘', '─', '│'
+		if gr.critical[id] {
+			topLeft, topRight, bottomLeft, bottomRight, horizontal, vertical = '╔', '╗', '╚', '╝', '═', '║'
 		}
-		width += 4 // Box borders
 
 		// Draw box
 		// Top border
-		gr.setChar(pos.row, pos.col, '┌')
+		gr.setChar(pos.row, pos.col, topLeft)
 		for i := 1; i < width-1; i++ {
-			gr.setChar(pos.row, pos.col+i, '─')
+			gr.setChar(pos.row, pos.col+i, horizontal)
 		}
-		gr.setChar(pos.row, pos.col+width-1, '┐')
+		gr.setChar(pos.row, pos.col+width-1, topRight)
 
 		// Middle with text
-		text := string(id)
-		if gr.nodes[id].cacheable {
-			text += "*"
-		}
-		gr.setChar(pos.row+1, pos.col, '│')
-		gr.setString(pos.row+1, pos.col+2, text)
-		gr.setChar(pos.row+1, pos.col+width-1, '│')
+		gr.setChar(pos.row+1, pos.col, vertical)
+		gr.setString(pos.row+1, pos.col+2, gr.nodeLabel(id))
+		gr.setChar(pos.row+1, pos.col+width-1, vertical)
 
 		// Bottom border
-		gr.setChar(pos.row+2, pos.col, '└')
+		gr.setChar(pos.row+2, pos.col, bottomLeft)
 		for i := 1; i < width-1; i++ {
-			gr.setChar(pos.row+2, pos.col+i, '─')
+			gr.setChar(pos.row+2, pos.col+i, horizontal)
+		}
+		gr.setChar(pos.row+2, pos.col+width-1, bottomRight)
+
+		if gr.colors {
+			style := gr.nodeStyle(id)
+			for r := pos.row; r <= pos.row+2; r++ {
+				for c := pos.col; c < pos.col+width; c++ {
+					gr.setStyle(r, c, style)
+				}
+			}
+		}
+	}
+}
+
+// levelColors are the ANSI foreground colors used for successive levels:
+// cyan, green, yellow, magenta, blue and red.
+var levelColors = []string{"36", "32", "33", "35", "34", "31"}
+
+// nodeStyle returns the SGR parameters for a node's box: its level's color,
+// plus bold for cacheable nodes.
+func (gr *graphRenderer) nodeStyle(id ID) string {
+	level := 0
+	for i, ids := range gr.levels {
+		for _, other := range ids {
+			if other == id {
+				level = i
+			}
 		}
-		gr.setChar(pos.row+2, pos.col+width-1, '┘')
+	}
+	style := levelColors[level%len(levelColors)]
+	if gr.nodes[id].cacheable {
+		style = "1;" + style
+	}
+	return style
+}
+
+// nodeLabel returns the text drawn inside a node's box: the ID, a * marker
+// for cacheable nodes, and the node's duration and a ★ marker for critical
+// path nodes when a timing overlay is present.
+func (gr *graphRenderer) nodeLabel(id ID) string {
+	text := string(id)
+	if gr.nodes[id].cacheable {
+		text += "*"
+	}
+	if d, ok := gr.durations[id]; ok {
+		text += " (" + formatDuration(d) + ")"
+	}
+	if gr.critical[id] {
+		text += " ★"
+	}
+	if gr.counts != nil {
+		text += " " + gr.countLabel(id)
+	}
+	return text
+}
+
+// countLabel returns the execution count suffix for a node.
+func (gr *graphRenderer) countLabel(id ID) string {
+	n := gr.counts[id]
+	switch {
+	case n == 0 && gr.nodes[id].cacheable:
+		return "[cached]"
+	case n == 1:
+		return "[1 run]"
+	default:
+		return fmt.Sprintf("[%d runs]", n)
+	}
+}
+
+// boxWidth returns the width of a node's box including borders.
+func (gr *graphRenderer) boxWidth(id ID) int {
+	return utf8.RuneCountInString(gr.nodeLabel(id)) + 4 // Box borders: "│ " + " │"
+}
+
+// formatDuration rounds d to a readable precision for display.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
 	}
 }
 
@@ -252,16 +348,13 @@ func (gr *graphRenderer) buildChildMap() map[ID][]ID {
 }
 
 // groupChildrenByLevel groups children by their level to handle wrapping.
+// Children keep the sorted order of their level.
 func (gr *graphRenderer) groupChildrenByLevel(children map[ID][]ID) map[int][]ID {
 	childrenByLevel := make(map[int][]ID)
-	for childID := range children {
-		// Find which level this child belongs to
-		for levelIdx, level := range gr.levels {
-			for _, id := range level {
-				if id == childID {
-					childrenByLevel[levelIdx] = append(childrenByLevel[levelIdx], childID)
-					break
-				}
+	for levelIdx, level := range gr.levels {
+		for _, id := range level {
+			if _, ok := children[id]; ok {
+				childrenByLevel[levelIdx] = append(childrenByLevel[levelIdx], id)
 			}
 		}
 	}
@@ -283,7 +376,12 @@ func (gr *graphRenderer) drawAllEdgeLines(children map[ID][]ID, childrenByLevel
 	}
 
 	// Draw edges level by level
-	for levelIdx, childIDs := range childrenByLevel {
+	for levelIdx := range gr.levels {
+		childIDs := childrenByLevel[levelIdx]
+		if len(childIDs) == 0 {
+			continue
+		}
+
 		// Get all rows that contain children from this level
 		childRows := gr.levelRows[levelIdx]
 		if len(childRows) == 0 {
@@ -298,8 +396,14 @@ func (gr *graphRenderer) drawAllEdgeLines(children map[ID][]ID, childrenByLevel
 			}
 		}
 
-		// Draw edges from each parent to all its children in this level
+		parentIDs := make([]ID, 0, len(allParentIDs))
 		for parentID := range allParentIDs {
+			parentIDs = append(parentIDs, parentID)
+		}
+		sort.Slice(parentIDs, func(i, j int) bool { return parentIDs[i] < parentIDs[j] })
+
+		// Draw edges from each parent to all its children in this level
+		for _, parentID := range parentIDs {
 			gr.drawParentToChildrenEdges(parentID, childIDs, children, childRows, getConnector)
 		}
 	}
@@ -513,12 +617,7 @@ func (gr *graphRenderer) selectJunctionGlyph(up, down, left, right bool) rune {
 
 // getNodeCenterOffset returns the column offset to the center of a node.
 func (gr *graphRenderer) getNodeCenterOffset(id ID) int {
-	width := len(string(id))
-	if gr.nodes[id].cacheable {
-		width++
-	}
-	width += 4 // Box borders
-	return width / 2
+	return gr.boxWidth(id) / 2
 }
 
 // Helper methods for grid manipulation
@@ -535,21 +634,57 @@ func (gr *graphRenderer) getChar(row, col int) rune {
 	return ' '
 }
 
+func (gr *graphRenderer) setStyle(row, col int, style string) {
+	if row >= 0 && row < len(gr.styles) && col >= 0 && col < len(gr.styles[row]) {
+		gr.styles[row][col] = style
+	}
+}
+
 func (gr *graphRenderer) setString(row, col int, s string) {
-	for i, r := range s {
+	i := 0
+	for _, r := range s {
 		gr.setChar(row, col+i, r)
+		i++
 	}
 }
 
 func (gr *graphRenderer) gridToString() string {
 	var sb strings.Builder
-	for _, row := range gr.grid {
+	for i, row := range gr.grid {
 		// Trim trailing spaces
 		line := strings.TrimRight(string(row), " ")
-		if line != "" {
-			sb.WriteString(line)
-			sb.WriteString("\n")
+		if line == "" {
+			continue
+		}
+		if gr.colors {
+			line = gr.colorLine([]rune(line), gr.styles[i])
 		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// colorLine wraps each run of equally styled cells in ANSI escape sequences.
+// Escapes are added only here, after layout, so they never affect column
+// positions.
+func (gr *graphRenderer) colorLine(line []rune, styles []string) string {
+	var sb strings.Builder
+	current := ""
+	for j, r := range line {
+		if styles[j] != current {
+			if current != "" {
+				sb.WriteString("\x1b[0m")
+			}
+			if styles[j] != "" {
+				sb.WriteString("\x1b[" + styles[j] + "m")
+			}
+			current = styles[j]
+		}
+		sb.WriteRune(r)
+	}
+	if current != "" {
+		sb.WriteString("\x1b[0m")
 	}
 	return sb.String()
 }
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestPrintGraph_EmptyRegistry(t *testing.T) {
@@ -1151,3 +1156,533 @@ func TestTopoSortLevelsForGraph_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintGraphWithStats(t *testing.T) {
+	nodes := map[ID]node{
+		"config": {id: "config"},
+		"db":     {id: "db", dependsOn: []ID{"config"}},
+		"cache":  {id: "cache", dependsOn: []ID{"config"}, cacheable: true},
+		"api":    {id: "api", dependsOn: []ID{"db", "cache"}},
+	}
+	stats := ExecutionStats{Nodes: map[ID]NodeStats{
+		"config": {Duration: 10 * time.Millisecond},
+		"db":     {Duration: 123 * time.Millisecond, DependsOn: []ID{"config"}},
+		"cache":  {Duration: 5 * time.Millisecond, DependsOn: []ID{"config"}},
+		"api":    {Duration: 2 * time.Second, DependsOn: []ID{"db", "cache"}},
+	}}
+
+	var buf bytes.Buffer
+	if err := PrintGraphWithStats(&buf, stats, WithRegistry(nodes)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := buf.String()
+	t.Logf("Graph output:\n%s", output)
+
+	for _, want := range []string{
+		"║ config (10ms) ★ ║",
+		"║ db (123ms) ★ ║",
+		"│ cache* (5ms) │",
+		"║ api (2s) ★ ║",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+	if strings.Count(output, "╔") != 3 {
+		t.Errorf("expected 3 critical path boxes, got %d", strings.Count(output, "╔"))
+	}
+}
+
+func TestPrintGraphWithStats_MissingStats(t *testing.T) {
+	nodes := map[ID]node{
+		"a": {id: "a"},
+		"b": {id: "b", dependsOn: []ID{"a"}},
+	}
+
+	var withStats, plain bytes.Buffer
+	if err := PrintGraphWithStats(&withStats, ExecutionStats{}, WithRegistry(nodes)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := PrintGraph(&plain, WithRegistry(nodes)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if withStats.String() != plain.String() {
+		t.Errorf("empty stats should render like PrintGraph:\n%s\nvs\n%s", withStats.String(), plain.String())
+	}
+}
+
+func TestPrintGraphWithExecutionCounts(t *testing.T) {
+	type tc struct {
+		counts map[ID]int
+		want   []string
+	}
+
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true},
+		"db":     {id: "db", dependsOn: []ID{"config"}},
+		"api":    {id: "api", dependsOn: []ID{"db"}},
+	}
+
+	tests := map[string]tc{
+		"counts appended to labels": {
+			counts: map[ID]int{"config": 0, "db": 1, "api": 42},
+			want: []string{
+				"│ config* [cached] │",
+				"│ db [1 run] │",
+				"│ api [42 runs] │",
+			},
+		},
+		"missing nodes have zero runs": {
+			counts: map[ID]int{"config": 3},
+			want: []string{
+				"│ config* [3 runs] │",
+				"│ db [0 runs] │",
+				"│ api [0 runs] │",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PrintGraphWithExecutionCounts(&buf, tt.counts, WithRegistry(nodes)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output := buf.String()
+			t.Logf("Graph output:\n%s", output)
+
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintMermaid_Deterministic(t *testing.T) {
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: noop},
+		"db":     makeNode("db", []ID{"config"}, noop),
+		"cache":  {id: "cache", dependsOn: []ID{"config"}, cacheable: true, run: noop},
+		"api":    makeNode("api", []ID{"db", "cache"}, noop),
+		"worker": makeNode("worker", []ID{"db", "cache", "config"}, noop),
+	}
+
+	var first bytes.Buffer
+	if err := PrintMermaid(&first, WithRegistry(nodes)); err != nil {
+		t.Fatalf("PrintMermaid error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+		if err := PrintMermaid(&buf, WithRegistry(nodes)); err != nil {
+			t.Fatalf("PrintMermaid error: %v", err)
+		}
+		if buf.String() != first.String() {
+			t.Fatalf("output differs between calls:\n%s\nvs\n%s", first.String(), buf.String())
+		}
+	}
+
+	// Graph rendering must be stable too
+	var graph bytes.Buffer
+	if err := PrintGraph(&graph, WithRegistry(nodes)); err != nil {
+		t.Fatalf("PrintGraph error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+		if err := PrintGraph(&buf, WithRegistry(nodes)); err != nil {
+			t.Fatalf("PrintGraph error: %v", err)
+		}
+		if buf.String() != graph.String() {
+			t.Fatalf("graph output differs between calls:\n%s\nvs\n%s", graph.String(), buf.String())
+		}
+	}
+}
+
+func TestSprintMatchesPrint(t *testing.T) {
+	type tc struct {
+		sprint func(...Option) (string, error)
+		print  func(io.Writer, ...Option) error
+		want   []string
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: noop},
+		"db":     makeNode("db", []ID{"config"}, noop),
+	}
+
+	tests := map[string]tc{
+		"graph": {
+			sprint: SprintGraph,
+			print:  PrintGraph,
+			want:   []string{"config", "db"},
+		},
+		"mermaid": {
+			sprint: SprintMermaid,
+			print:  PrintMermaid,
+			want:   []string{"graph TD", "config --> db"},
+		},
+		"dot": {
+			sprint: SprintDOT,
+			print:  PrintDOT,
+			want:   []string{"digraph graft {", `"config" -> "db";`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.sprint(WithRegistry(nodes))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tt.print(&buf, WithRegistry(nodes)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != buf.String() {
+				t.Errorf("Sprint output differs from Print:\n%s\nvs\n%s", got, buf.String())
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSprintGraphCycle(t *testing.T) {
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"a": makeNode("a", []ID{"b"}, noop),
+		"b": makeNode("b", []ID{"a"}, noop),
+	}
+
+	out, err := SprintGraph(WithRegistry(nodes))
+	if err == nil {
+		t.Fatal("expected cycle error")
+	}
+	if out != "" {
+		t.Errorf("expected empty output on error, got %q", out)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	type tc struct {
+		nodes   map[ID]node
+		want    [][]ID
+		wantErr string
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	tests := map[string]tc{
+		"diamond": {
+			nodes: map[ID]node{
+				"config": makeNode("config", nil, noop),
+				"db":     makeNode("db", []ID{"config"}, noop),
+				"cache":  makeNode("cache", []ID{"config"}, noop),
+				"api":    makeNode("api", []ID{"db", "cache"}, noop),
+			},
+			want: [][]ID{{"config"}, {"cache", "db"}, {"api"}},
+		},
+		"cycle": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"b"}, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+			},
+			wantErr: "cycle",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Levels(WithRegistry(tt.nodes))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Levels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeLevel(t *testing.T) {
+	type tc struct {
+		id        ID
+		wantLevel int
+		wantErr   string
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"config": makeNode("config", nil, noop),
+		"db":     makeNode("db", []ID{"config"}, noop),
+		"cache":  makeNode("cache", nil, noop),
+		"api":    makeNode("api", []ID{"db", "cache"}, noop),
+	}
+
+	tests := map[string]tc{
+		"root":         {id: "config", wantLevel: 0},
+		"middle":       {id: "db", wantLevel: 1},
+		"deepest dep":  {id: "api", wantLevel: 2},
+		"unknown node": {id: "missing", wantErr: "unknown node: missing"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NodeLevel(tt.id, WithRegistry(nodes))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantLevel {
+				t.Errorf("NodeLevel(%s) = %d, want %d", tt.id, got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestMaxLevel(t *testing.T) {
+	type tc struct {
+		nodes map[ID]node
+		want  int
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	tests := map[string]tc{
+		"empty": {
+			nodes: map[ID]node{},
+			want:  -1,
+		},
+		"single level": {
+			nodes: map[ID]node{"a": makeNode("a", nil, noop), "b": makeNode("b", nil, noop)},
+			want:  0,
+		},
+		"chain": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+				"c": makeNode("c", []ID{"b"}, noop),
+			},
+			want: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := MaxLevel(WithRegistry(tt.nodes))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MaxLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRootsLeavesOrphans(t *testing.T) {
+	type tc struct {
+		nodes       map[ID]node
+		wantRoots   []ID
+		wantLeaves  []ID
+		wantOrphans []ID
+		wantErr     bool
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	tests := map[string]tc{
+		"empty": {
+			nodes:       map[ID]node{},
+			wantRoots:   []ID{},
+			wantLeaves:  []ID{},
+			wantOrphans: []ID{},
+		},
+		"diamond with isolated node": {
+			nodes: map[ID]node{
+				"config": makeNode("config", nil, noop),
+				"db":     makeNode("db", []ID{"config"}, noop),
+				"cache":  makeNode("cache", []ID{"config"}, noop),
+				"api":    makeNode("api", []ID{"db", "cache"}, noop),
+				"worker": makeNode("worker", []ID{"db"}, noop),
+				"flags":  makeNode("flags", nil, noop),
+			},
+			wantRoots:   []ID{"config", "flags"},
+			wantLeaves:  []ID{"api", "flags", "worker"},
+			wantOrphans: []ID{"flags"},
+		},
+		"unknown dependency": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"missing"}, noop),
+			},
+			wantErr: true,
+		},
+		"cycle": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"b"}, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			finders := map[string]struct {
+				find func(...Option) ([]ID, error)
+				want []ID
+			}{
+				"FindRoots":   {FindRoots, tt.wantRoots},
+				"FindLeaves":  {FindLeaves, tt.wantLeaves},
+				"FindOrphans": {FindOrphans, tt.wantOrphans},
+			}
+			for fname, f := range finders {
+				got, err := f.find(WithRegistry(tt.nodes))
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("%s() error = %v, wantErr %v", fname, err, tt.wantErr)
+				}
+				if !tt.wantErr && !reflect.DeepEqual(got, f.want) {
+					t.Errorf("%s() = %v, want %v", fname, got, f.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintGraphColored(t *testing.T) {
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true},
+		"db":     {id: "db", dependsOn: []ID{"config"}},
+		"app":    {id: "app", dependsOn: []ID{"db"}},
+	}
+
+	var plain, colored bytes.Buffer
+	if err := PrintGraph(&plain, WithRegistry(nodes)); err != nil {
+		t.Fatalf("PrintGraph: %v", err)
+	}
+	if err := PrintGraphColored(&colored, WithRegistry(nodes)); err != nil {
+		t.Fatalf("PrintGraphColored: %v", err)
+	}
+
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("PrintGraph to a non-terminal should not emit escapes:\n%s", plain.String())
+	}
+
+	out := colored.String()
+	for _, want := range []string{"\x1b[1;36m", "\x1b[32m", "\x1b[33m", "\x1b[0m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("colored output missing %q:\n%s", want, out)
+		}
+	}
+
+	stripped := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(out, "")
+	if stripped != plain.String() {
+		t.Errorf("colors changed the layout:\ncolored:\n%s\nplain:\n%s", stripped, plain.String())
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	type tc struct {
+		writer func(t *testing.T) io.Writer
+		want   bool
+	}
+
+	tests := map[string]tc{
+		"buffer": {
+			writer: func(t *testing.T) io.Writer { return &bytes.Buffer{} },
+		},
+		"regular file": {
+			writer: func(t *testing.T) io.Writer {
+				f, err := os.CreateTemp(t.TempDir(), "graph")
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { f.Close() })
+				return f
+			},
+		},
+		"pipe": {
+			writer: func(t *testing.T) io.Writer {
+				r, w, err := os.Pipe()
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { r.Close(); w.Close() })
+				return w
+			},
+		},
+		"character device": {
+			writer: func(t *testing.T) io.Writer {
+				f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+				if err != nil {
+					t.Skipf("open %s: %v", os.DevNull, err)
+				}
+				t.Cleanup(func() { f.Close() })
+				return f
+			},
+			want: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isTerminal(tt.writer(t)); got != tt.want {
+				t.Errorf("isTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nonFileWriter is an io.Writer that is not an *os.File, even though it
+// writes to one.
+type nonFileWriter struct{ w io.Writer }
+
+func (n nonFileWriter) Write(p []byte) (int, error) { return n.w.Write(p) }
+
+func TestPrintGraphNoColorsForNonFileWriter(t *testing.T) {
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true},
+		"app":    {id: "app", dependsOn: []ID{"config"}},
+	}
+
+	// Wrapping a character device hides it from the check
+	f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("open %s: %v", os.DevNull, err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	w := nonFileWriter{io.MultiWriter(f, &buf)}
+	if err := PrintGraph(w, WithRegistry(nodes)); err != nil {
+		t.Fatalf("PrintGraph: %v", err)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("PrintGraph to a non-*os.File writer should not emit escapes:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := PrintGraph(w, WithRegistry(nodes), WithANSIColors()); err != nil {
+		t.Fatalf("PrintGraph: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("WithANSIColors should force escapes:\n%s", buf.String())
+	}
+}
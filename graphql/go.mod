@@ -0,0 +1,16 @@
+module github.com/grindlemire/graft/graphql
+
+go 1.25.1
+
+replace github.com/grindlemire/graft => ../
+
+require (
+	github.com/graphql-go/graphql v0.8.1
+	github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
+)
+
+require (
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
@@ -0,0 +1,426 @@
+// Package graphql exposes the graft node graph as a GraphQL schema built
+// with github.com/graphql-go/graphql, for developer tooling that wants to
+// query the graph.
+//
+// The schema:
+//
+//	scalar JSON
+//
+//	type Query {
+//	  nodes: [Node!]!
+//	  node(id: ID!): Node
+//	  execute(nodeId: ID!, input: JSON): ExecutionResult
+//	}
+//
+//	type Node {
+//	  id: ID!
+//	  dependsOn: [ID!]!
+//	  dependents: [ID!]!
+//	  cacheable: Boolean!
+//	  level: Int!
+//	}
+//
+//	type ExecutionResult {
+//	  nodeId: ID!
+//	  output: JSON
+//	  results: [NodeResult!]!
+//	}
+//
+//	type NodeResult {
+//	  id: ID!
+//	  output: JSON
+//	}
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/grindlemire/graft"
+)
+
+// inputKey is the context key for the input of an execute query.
+type inputKey struct{}
+
+// Input returns the input argument of the execute query that started the
+// execution, encoded as JSON, or nil if there was none. Nodes read it from
+// the context passed to Run.
+//
+// Example:
+//
+//	Run: func(ctx context.Context) (Output, error) {
+//	    var req Request
+//	    if in := graftgraphql.Input(ctx); in != nil {
+//	        if err := json.Unmarshal(in, &req); err != nil {
+//	            return Output{}, err
+//	        }
+//	    }
+//	    ...
+//	}
+func Input(ctx context.Context) json.RawMessage {
+	in, _ := ctx.Value(inputKey{}).(json.RawMessage)
+	return in
+}
+
+// NewSchema returns a schema over the registered nodes. The nodes are read
+// when a query runs, so nodes registered after NewSchema are included.
+// opts are passed to every graft call the schema makes, including the
+// execution started by the execute field.
+//
+// execute runs nodeId and its transitive dependencies and returns the
+// outputs encoded with encoding/json. An execution error is reported as a
+// GraphQL error on the field.
+//
+// Example:
+//
+//	schema := graftgraphql.NewSchema()
+//	res := graphql.Do(graphql.Params{
+//	    Schema:        schema,
+//	    RequestString: `{ nodes { id level dependents } }`,
+//	})
+func NewSchema(opts ...graft.Option) graphql.Schema {
+	s := &schema{opts: opts}
+	out, err := graphql.NewSchema(graphql.SchemaConfig{Query: s.queryType()})
+	if err != nil {
+		// The schema is fixed, so this is a bug in this package.
+		panic(fmt.Sprintf("graft/graphql: invalid schema: %v", err))
+	}
+	return out
+}
+
+type schema struct {
+	opts []graft.Option
+}
+
+// graphView is the registry as seen by a single query, with the dependents
+// and levels computed once for all the nodes it returns.
+type graphView struct {
+	nodes      []graft.NodeSummary
+	dependents map[graft.ID][]graft.ID
+	levels     map[graft.ID]int
+}
+
+// nodeView is the source value of the Node type.
+type nodeView struct {
+	graft.NodeSummary
+	graph *graphView
+}
+
+// nodeResult is the source value of the NodeResult type.
+type nodeResult struct {
+	id     graft.ID
+	output any
+}
+
+// executionResult is the source value of the ExecutionResult type.
+type executionResult struct {
+	nodeID  graft.ID
+	results []nodeResult
+}
+
+func (s *schema) graph() (*graphView, error) {
+	levels, err := graft.Levels(s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	g := &graphView{
+		nodes:      graft.ListNodes(s.opts...),
+		dependents: map[graft.ID][]graft.ID{},
+		levels:     map[graft.ID]int{},
+	}
+	for level, ids := range levels {
+		for _, id := range ids {
+			g.levels[id] = level
+		}
+	}
+	// ListNodes is sorted by ID, so each dependents list is too.
+	for _, n := range g.nodes {
+		for _, dep := range n.DependsOn {
+			g.dependents[dep] = append(g.dependents[dep], n.ID)
+		}
+	}
+	return g, nil
+}
+
+func (s *schema) queryType() *graphql.Object {
+	nodeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Node",
+		Description: "A registered graft node.",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return string(p.Source.(nodeView).ID), nil
+				},
+			},
+			"dependsOn": &graphql.Field{
+				Type:        idList,
+				Description: "IDs of the nodes this node depends on.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return idStrings(p.Source.(nodeView).DependsOn), nil
+				},
+			},
+			"dependents": &graphql.Field{
+				Type:        idList,
+				Description: "IDs of the nodes that depend on this node, sorted.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					n := p.Source.(nodeView)
+					return idStrings(n.graph.dependents[n.ID]), nil
+				},
+			},
+			"cacheable": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(nodeView).Cacheable, nil
+				},
+			},
+			"level": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Execution level: 0 for nodes without dependencies.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					n := p.Source.(nodeView)
+					return n.graph.levels[n.ID], nil
+				},
+			},
+		},
+	})
+
+	nodeResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "NodeResult",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return string(p.Source.(nodeResult).id), nil
+				},
+			},
+			"output": &graphql.Field{
+				Type: JSON,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(nodeResult).output, nil
+				},
+			},
+		},
+	})
+
+	executionResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ExecutionResult",
+		Description: "The outputs of an executed node and its dependencies.",
+		Fields: graphql.Fields{
+			"nodeId": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return string(p.Source.(executionResult).nodeID), nil
+				},
+			},
+			"output": &graphql.Field{
+				Type:        JSON,
+				Description: "Output of the executed node.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					r := p.Source.(executionResult)
+					for _, res := range r.results {
+						if res.id == r.nodeID {
+							return res.output, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			"results": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nodeResultType))),
+				Description: "Outputs of every node that ran, sorted by ID.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(executionResult).results, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"nodes": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nodeType))),
+				Description: "All registered nodes, sorted by ID.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					g, err := s.graph()
+					if err != nil {
+						return nil, err
+					}
+					out := make([]nodeView, len(g.nodes))
+					for i, n := range g.nodes {
+						out[i] = nodeView{NodeSummary: n, graph: g}
+					}
+					return out, nil
+				},
+			},
+			"node": &graphql.Field{
+				Type: nodeType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					g, err := s.graph()
+					if err != nil {
+						return nil, err
+					}
+					id := graft.ID(p.Args["id"].(string))
+					for _, n := range g.nodes {
+						if n.ID == id {
+							return nodeView{NodeSummary: n, graph: g}, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			"execute": &graphql.Field{
+				Type:        executionResultType,
+				Description: "Executes a node and its transitive dependencies.",
+				Args: graphql.FieldConfigArgument{
+					"nodeId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{
+						Type:        JSON,
+						Description: "Made available to nodes through graftgraphql.Input.",
+					},
+				},
+				Resolve: s.execute,
+			},
+		},
+	})
+}
+
+func (s *schema) execute(p graphql.ResolveParams) (any, error) {
+	id := graft.ID(p.Args["nodeId"].(string))
+	if !known(id, s.opts) {
+		return nil, fmt.Errorf("unknown node: %s", id)
+	}
+
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if in, ok := p.Args["input"]; ok && in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("encode input: %w", err)
+		}
+		ctx = context.WithValue(ctx, inputKey{}, json.RawMessage(data))
+	}
+
+	results, err := graft.ExecuteForPattern(ctx, matchExactly(id), s.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]graft.ID, 0, len(results))
+	for rid := range results {
+		ids = append(ids, rid)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	out := executionResult{nodeID: id}
+	for _, rid := range ids {
+		output, err := toJSONValue(results[rid])
+		if err != nil {
+			return nil, fmt.Errorf("encode output of %s: %w", rid, err)
+		}
+		out.results = append(out.results, nodeResult{id: rid, output: output})
+	}
+	return out, nil
+}
+
+// known reports whether id is a registered node.
+func known(id graft.ID, opts []graft.Option) bool {
+	for _, n := range graft.ListNodes(opts...) {
+		if n.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchExactly returns a pattern for [graft.ExecuteForPattern] that only
+// matches id, escaping the characters path.Match treats specially.
+func matchExactly(id graft.ID) string {
+	var b strings.Builder
+	for _, r := range string(id) {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toJSONValue converts v to the maps, slices and scalars encoding/json
+// would produce for it, which is what the JSON scalar serializes.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+func idStrings(ids []graft.ID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = string(id)
+	}
+	return out
+}
+
+var idList = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))
+
+// JSON is a scalar holding any JSON value. Node outputs are serialized
+// through encoding/json, so json struct tags apply.
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Any JSON value.",
+	Serialize:   func(v any) any { return v },
+	ParseValue:  func(v any) any { return v },
+	ParseLiteral: func(v ast.Value) any {
+		return literalValue(v)
+	},
+})
+
+// literalValue converts a JSON argument written inline in a query.
+func literalValue(v ast.Value) any {
+	switch v := v.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n
+		}
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case *ast.ListValue:
+		out := make([]any, len(v.Values))
+		for i, item := range v.Values {
+			out[i] = literalValue(item)
+		}
+		return out
+	case *ast.ObjectValue:
+		out := make(map[string]any, len(v.Fields))
+		for _, f := range v.Fields {
+			out[f.Name.Value] = literalValue(f.Value)
+		}
+		return out
+	default:
+		return nil
+	}
+}
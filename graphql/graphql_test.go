@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/grindlemire/graft"
+)
+
+type configOutput struct {
+	Port int `json:"port"`
+}
+
+type userOutput struct {
+	Name string `json:"name"`
+}
+
+type requestOutput struct {
+	Raw string `json:"raw"`
+}
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+	t.Cleanup(graft.ResetDefaultCache)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:        "config",
+		Cacheable: true,
+		Run: func(ctx context.Context) (configOutput, error) {
+			return configOutput{Port: 8080}, nil
+		},
+	})
+	graft.Register(graft.Node[userOutput]{
+		ID:        "user",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (userOutput, error) {
+			return userOutput{Name: "alice"}, nil
+		},
+	})
+	graft.Register(graft.Node[requestOutput]{
+		ID:        "request*",
+		DependsOn: []graft.ID{"config", "user"},
+		Run: func(ctx context.Context) (requestOutput, error) {
+			return requestOutput{Raw: string(Input(ctx))}, nil
+		},
+	})
+	graft.Register(graft.Node[int]{
+		ID:  "failing",
+		Run: func(ctx context.Context) (int, error) { return 0, errors.New("boom") },
+	})
+}
+
+func TestSchema(t *testing.T) {
+	type tc struct {
+		query     string
+		variables map[string]any
+		want      string
+		wantErr   string
+	}
+
+	tests := map[string]tc{
+		"all nodes": {
+			query: `{ nodes { id dependsOn dependents cacheable level } }`,
+			want: `{"nodes":[` +
+				`{"cacheable":true,"dependents":["request*","user"],"dependsOn":[],"id":"config","level":0},` +
+				`{"cacheable":false,"dependents":[],"dependsOn":[],"id":"failing","level":0},` +
+				`{"cacheable":false,"dependents":[],"dependsOn":["config","user"],"id":"request*","level":2},` +
+				`{"cacheable":false,"dependents":["request*"],"dependsOn":["config"],"id":"user","level":1}]}`,
+		},
+		"single node": {
+			query: `{ node(id: "user") { id level dependents } }`,
+			want:  `{"node":{"dependents":["request*"],"id":"user","level":1}}`,
+		},
+		"unknown node": {
+			query: `{ node(id: "missing") { id } }`,
+			want:  `{"node":null}`,
+		},
+		"execute": {
+			query: `{ execute(nodeId: "user") { nodeId output results { id output } } }`,
+			want: `{"execute":{"nodeId":"user","output":{"name":"alice"},` +
+				`"results":[{"id":"config","output":{"port":8080}},{"id":"user","output":{"name":"alice"}}]}}`,
+		},
+		"execute with inline input": {
+			query: `{ execute(nodeId: "request*", input: {user: "bob", ids: [1, 2]}) { output } }`,
+			want:  `{"execute":{"output":{"raw":"{\"ids\":[1,2],\"user\":\"bob\"}"}}}`,
+		},
+		"execute with input variable": {
+			query:     `query($in: JSON) { execute(nodeId: "request*", input: $in) { output } }`,
+			variables: map[string]any{"in": map[string]any{"user": "carol"}},
+			want:      `{"execute":{"output":{"raw":"{\"user\":\"carol\"}"}}}`,
+		},
+		"execute without input": {
+			query: `{ execute(nodeId: "request*") { output } }`,
+			want:  `{"execute":{"output":{"raw":""}}}`,
+		},
+		"execute unknown node": {
+			query:   `{ execute(nodeId: "missing") { nodeId } }`,
+			wantErr: "unknown node: missing",
+		},
+		"execute error": {
+			query:   `{ execute(nodeId: "failing") { nodeId } }`,
+			wantErr: "boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			res := graphql.Do(graphql.Params{
+				Schema:         NewSchema(),
+				RequestString:  tt.query,
+				VariableValues: tt.variables,
+				Context:        context.Background(),
+			})
+			if tt.wantErr != "" {
+				if len(res.Errors) == 0 || !strings.Contains(res.Errors[0].Message, tt.wantErr) {
+					t.Fatalf("errors = %v, want one containing %q", res.Errors, tt.wantErr)
+				}
+				return
+			}
+			if len(res.Errors) > 0 {
+				t.Fatalf("errors = %v", res.Errors)
+			}
+			got, err := json.Marshal(res.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("data mismatch\ngot:  %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaUsesOptions(t *testing.T) {
+	registerNodes(t)
+
+	res := graphql.Do(graphql.Params{
+		Schema:        NewSchema(graft.PatchValue(configOutput{Port: 9090})),
+		RequestString: `{ execute(nodeId: "config") { output } }`,
+	})
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors = %v", res.Errors)
+	}
+	got, _ := json.Marshal(res.Data)
+	if want := `{"execute":{"output":{"port":9090}}}`; string(got) != want {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+package graft
+
+import (
+	"context"
+	"time"
+)
+
+// OnNodeStart registers a callback invoked just before each node is
+// resolved (from the cache or by running it). level is the node's
+// topological level, starting at 0 for nodes without dependencies.
+//
+// The option may be passed multiple times; callbacks run in the order they
+// were registered. Nodes in the same level run concurrently, so callbacks
+// must be safe for concurrent use.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.OnNodeStart(func(id graft.ID, level int) {
+//	        log.Printf("starting %s (level %d)", id, level)
+//	    }),
+//	)
+func OnNodeStart(fn func(id ID, level int)) Option {
+	return func(c *config) {
+		c.startHooks = append(c.startHooks, fn)
+	}
+}
+
+// OnNodeComplete registers a callback invoked after each node is resolved,
+// with the time taken and the error (if any) that the node produced.
+//
+// The option may be passed multiple times; callbacks run in the order they
+// were registered. Nodes in the same level run concurrently, so callbacks
+// must be safe for concurrent use.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.OnNodeComplete(func(id graft.ID, level int, d time.Duration, err error) {
+//	        log.Printf("%s finished in %s (err=%v)", id, d, err)
+//	    }),
+//	)
+func OnNodeComplete(fn func(id ID, level int, d time.Duration, err error)) Option {
+	return func(c *config) {
+		c.completeHooks = append(c.completeHooks, fn)
+	}
+}
+
+// OnNodeResult registers a callback invoked with each node's output as soon
+// as the node succeeds, whether it ran or was served from the cache. It is
+// not called for failed nodes, or for outputs reused by [WithReplay] or
+// produced by [WithDryRun].
+//
+// The option may be passed multiple times; callbacks run in the order they
+// were registered. Nodes in the same level run concurrently, so callbacks
+// must be safe for concurrent use.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.OnNodeResult(func(id graft.ID, output any) {
+//	        events <- Event{Node: id, Output: output}
+//	    }),
+//	)
+func OnNodeResult(fn func(id ID, output any)) Option {
+	return func(c *config) {
+		c.resultHooks = append(c.resultHooks, fn)
+	}
+}
+
+// OnLevelComplete registers a callback invoked after every node in a level
+// has completed successfully and before the next level starts, with a copy
+// of the results produced so far.
+//
+// The callback runs synchronously on the execution's goroutine, so blocking
+// in it pauses the execution between levels. This makes it suitable for
+// stepping through a graph while debugging.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.OnLevelComplete(func(level int, results graft.Results) {
+//	        fmt.Printf("level %d done: %v\n", level, results)
+//	        fmt.Scanln() // wait for Enter before the next level
+//	    }),
+//	)
+func OnLevelComplete(fn func(level int, results Results)) Option {
+	return func(c *config) {
+		c.levelHooks = append(c.levelHooks, fn)
+	}
+}
+
+// WithPreExecuteHook registers a callback invoked once the graph has been
+// sorted and before any node runs, with the nodes about to be executed. If
+// it returns an error, execution is aborted and Execute returns that error.
+//
+// The option may be passed multiple times; callbacks run in the order they
+// were registered and the first error stops the rest.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.WithPreExecuteHook(func(ctx context.Context, nodes []graft.NodeSummary) error {
+//	        if len(nodes) > 100 {
+//	            return fmt.Errorf("refusing to run %d nodes", len(nodes))
+//	        }
+//	        return nil
+//	    }),
+//	)
+func WithPreExecuteHook(fn func(ctx context.Context, nodes []NodeSummary) error) Option {
+	return func(c *config) {
+		c.preHooks = append(c.preHooks, fn)
+	}
+}
+
+// WithPostExecuteHook registers a callback invoked after execution ends,
+// whether it succeeded or not, with a copy of the results produced and the
+// error Execute is about to return. It is not called if the graph could
+// not be sorted or a pre-execute hook aborted the execution.
+//
+// The option may be passed multiple times; callbacks run in the order they
+// were registered.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.WithPostExecuteHook(func(ctx context.Context, results graft.Results, err error) {
+//	        log.Printf("graph finished with %d results (err=%v)", len(results), err)
+//	    }),
+//	)
+func WithPostExecuteHook(fn func(ctx context.Context, results Results, err error)) Option {
+	return func(c *config) {
+		c.postHooks = append(c.postHooks, fn)
+	}
+}
+
+// Hooks groups node lifecycle callbacks so that integrations such as
+// loggers and tracers can be installed with a single option. Nil fields are
+// ignored.
+type Hooks struct {
+	// OnStart is called just before each node is resolved. See [OnNodeStart].
+	OnStart func(id ID, level int)
+
+	// OnCacheHit is called when a node's output is served from the cache,
+	// before OnComplete is called for the same node.
+	OnCacheHit func(id ID)
+
+	// OnComplete is called after each node is resolved. See [OnNodeComplete].
+	OnComplete func(id ID, level int, d time.Duration, err error)
+
+	// BeforeExecute is called before any node runs and may abort the
+	// execution. See [WithPreExecuteHook].
+	BeforeExecute func(ctx context.Context, nodes []NodeSummary) error
+
+	// AfterExecute is called once execution ends. See [WithPostExecuteHook].
+	AfterExecute func(ctx context.Context, results Results, err error)
+}
+
+// WithHooks registers every non-nil callback in h. Like [OnNodeStart] and
+// [OnNodeComplete], it may be passed multiple times and the callbacks must
+// be safe for concurrent use.
+//
+// Example:
+//
+//	func WithTracing(tr Tracer) graft.Option {
+//	    return graft.WithHooks(graft.Hooks{
+//	        OnStart:    func(id graft.ID, level int) { tr.Begin(string(id)) },
+//	        OnComplete: func(id graft.ID, level int, d time.Duration, err error) { tr.End(string(id), err) },
+//	    })
+//	}
+func WithHooks(h Hooks) Option {
+	return func(c *config) {
+		if h.OnStart != nil {
+			c.startHooks = append(c.startHooks, h.OnStart)
+		}
+		if h.OnCacheHit != nil {
+			c.cacheHitHooks = append(c.cacheHitHooks, h.OnCacheHit)
+		}
+		if h.OnComplete != nil {
+			c.completeHooks = append(c.completeHooks, h.OnComplete)
+		}
+		if h.BeforeExecute != nil {
+			c.preHooks = append(c.preHooks, h.BeforeExecute)
+		}
+		if h.AfterExecute != nil {
+			c.postHooks = append(c.postHooks, h.AfterExecute)
+		}
+	}
+}
+
+// nodeStarted invokes the registered start hooks.
+func (e *engine) nodeStarted(id ID, level int) {
+	for _, fn := range e.startHooks {
+		fn(id, level)
+	}
+}
+
+// nodeCompleted invokes the registered completion hooks.
+func (e *engine) nodeCompleted(id ID, level int, d time.Duration, err error) {
+	for _, fn := range e.completeHooks {
+		fn(id, level, d, err)
+	}
+}
+
+// nodeCacheHit invokes the registered cache hit hooks.
+func (e *engine) nodeCacheHit(id ID) {
+	for _, fn := range e.cacheHitHooks {
+		fn(id)
+	}
+}
+
+// beforeExecute invokes the registered pre-execute hooks, stopping at the
+// first error.
+func (e *engine) beforeExecute(ctx context.Context) error {
+	if len(e.preHooks) == 0 {
+		return nil
+	}
+	nodes := nodeSummaries(e.nodes)
+	for _, fn := range e.preHooks {
+		if err := fn(ctx, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterExecute invokes the registered post-execute hooks.
+func (e *engine) afterExecute(ctx context.Context, err error) {
+	if len(e.postHooks) == 0 {
+		return
+	}
+	e.mu.RLock()
+	results := e.copyResults()
+	e.mu.RUnlock()
+	for _, fn := range e.postHooks {
+		fn(ctx, results, err)
+	}
+}
+
+// levelCompleted invokes the registered level hooks.
+func (e *engine) levelCompleted(level int) {
+	if len(e.levelHooks) == 0 {
+		return
+	}
+	e.mu.RLock()
+	results := e.copyResults()
+	e.mu.RUnlock()
+	for _, fn := range e.levelHooks {
+		fn(level, results)
+	}
+}
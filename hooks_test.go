@@ -0,0 +1,343 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNodeHooks(t *testing.T) {
+	type tc struct {
+		nodes         map[ID]node
+		wantErr       bool
+		wantStarts    []string
+		wantCompletes []string
+	}
+
+	tests := map[string]tc{
+		"hooks receive levels": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+				"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) { return 2, nil }),
+				"c": makeNode("c", []ID{"a"}, func(ctx context.Context) (any, error) { return 3, nil }),
+			},
+			wantStarts:    []string{"a@0", "b@1", "c@1"},
+			wantCompletes: []string{"a@0 err=<nil>", "b@1 err=<nil>", "c@1 err=<nil>"},
+		},
+		"complete hook receives error": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+			},
+			wantErr:       true,
+			wantStarts:    []string{"a@0"},
+			wantCompletes: []string{"a@0 err=node a: boom"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var starts, completes []string
+
+			_, err := Execute(context.Background(),
+				WithRegistry(tt.nodes),
+				DisableCache(),
+				OnNodeStart(func(id ID, level int) {
+					mu.Lock()
+					defer mu.Unlock()
+					starts = append(starts, fmt.Sprintf("%s@%d", id, level))
+				}),
+				OnNodeComplete(func(id ID, level int, d time.Duration, err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					completes = append(completes, fmt.Sprintf("%s@%d err=%v", id, level, err))
+				}),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			sort.Strings(starts)
+			sort.Strings(completes)
+			if !reflect.DeepEqual(starts, tt.wantStarts) {
+				t.Errorf("starts = %v, want %v", starts, tt.wantStarts)
+			}
+			if !reflect.DeepEqual(completes, tt.wantCompletes) {
+				t.Errorf("completes = %v, want %v", completes, tt.wantCompletes)
+			}
+		})
+	}
+}
+
+func TestNodeHooksAppend(t *testing.T) {
+	nodes := map[ID]node{
+		"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+	}
+
+	var calls []string
+	_, err := Execute(context.Background(),
+		WithRegistry(nodes),
+		OnNodeStart(func(id ID, level int) { calls = append(calls, "start1") }),
+		OnNodeStart(func(id ID, level int) { calls = append(calls, "start2") }),
+		OnNodeComplete(func(id ID, level int, d time.Duration, err error) { calls = append(calls, "complete1") }),
+		OnNodeComplete(func(id ID, level int, d time.Duration, err error) { calls = append(calls, "complete2") }),
+	)
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	want := []string{"start1", "start2", "complete1", "complete2"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestWithHooks(t *testing.T) {
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: func(ctx context.Context) (any, error) { return "cfg", nil }},
+		"app":    makeNode("app", []ID{"config"}, func(ctx context.Context) (any, error) { return "app", nil }),
+	}
+	cache := NewMemoryCache()
+	if err := cache.Set(context.Background(), "config", "cached"); err != nil {
+		t.Fatalf("cache set: %v", err)
+	}
+
+	var mu sync.Mutex
+	var events []string
+	record := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf(format, args...))
+	}
+
+	_, err := Execute(context.Background(),
+		WithRegistry(nodes),
+		WithCache(cache),
+		WithHooks(Hooks{
+			OnStart:    func(id ID, level int) { record("start %s", id) },
+			OnCacheHit: func(id ID) { record("hit %s", id) },
+			OnComplete: func(id ID, level int, d time.Duration, err error) { record("complete %s", id) },
+			BeforeExecute: func(ctx context.Context, nodes []NodeSummary) error {
+				record("before %d", len(nodes))
+				return nil
+			},
+			AfterExecute: func(ctx context.Context, results Results, err error) { record("after %d", len(results)) },
+		}),
+		// Nil fields are ignored
+		WithHooks(Hooks{}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"before 2", "start config", "hit config", "complete config", "start app", "complete app", "after 2"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestOnNodeResult(t *testing.T) {
+	nodes := map[ID]node{
+		"a":    {id: "a", cacheable: true, run: func(ctx context.Context) (any, error) { return 1, nil }},
+		"b":    makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) { return 2, nil }),
+		"fail": makeNode("fail", []ID{"a"}, func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+	}
+	cache := NewMemoryCache()
+
+	for run := 0; run < 2; run++ {
+		var mu sync.Mutex
+		got := make(map[ID]any)
+		_, _ = Execute(context.Background(),
+			WithRegistry(nodes),
+			WithCache(cache),
+			OnNodeResult(func(id ID, output any) {
+				mu.Lock()
+				defer mu.Unlock()
+				got[id] = output
+			}),
+		)
+
+		// The second run serves a from the cache
+		want := map[ID]any{"a": 1, "b": 2}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("run %d: results = %v, want %v", run, got, want)
+		}
+	}
+}
+
+func TestOnLevelComplete(t *testing.T) {
+	type tc struct {
+		nodes      map[ID]node
+		wantErr    bool
+		wantLevels []string
+	}
+
+	tests := map[string]tc{
+		"results accumulate per level": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+				"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) { return 2, nil }),
+				"c": makeNode("c", []ID{"a"}, func(ctx context.Context) (any, error) { return 3, nil }),
+			},
+			wantLevels: []string{"0:[a]", "1:[a b c]"},
+		},
+		"failed level is not reported": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+				"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+			},
+			wantErr:    true,
+			wantLevels: []string{"0:[a]"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var levels []string
+			_, err := Execute(context.Background(),
+				WithRegistry(tt.nodes),
+				DisableCache(),
+				OnLevelComplete(func(level int, results Results) {
+					ids := make([]string, 0, len(results))
+					for id := range results {
+						ids = append(ids, string(id))
+					}
+					sort.Strings(ids)
+					levels = append(levels, fmt.Sprintf("%d:%v", level, ids))
+				}),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(levels, tt.wantLevels) {
+				t.Errorf("levels = %v, want %v", levels, tt.wantLevels)
+			}
+		})
+	}
+}
+
+func TestOnLevelCompleteBlocksNextLevel(t *testing.T) {
+	var bStarted atomic.Bool
+	resume := make(chan struct{})
+	nodes := map[ID]node{
+		"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+		"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) {
+			bStarted.Store(true)
+			return 2, nil
+		}),
+	}
+
+	paused := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := Execute(context.Background(),
+			WithRegistry(nodes),
+			DisableCache(),
+			OnLevelComplete(func(level int, results Results) {
+				if level == 0 {
+					close(paused)
+					<-resume
+				}
+			}),
+		)
+		done <- err
+	}()
+
+	<-paused
+	if bStarted.Load() {
+		t.Fatal("level 1 started while paused after level 0")
+	}
+	close(resume)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bStarted.Load() {
+		t.Error("level 1 did not run after resuming")
+	}
+}
+
+func TestExecuteHooks(t *testing.T) {
+	type tc struct {
+		preErr    error
+		nodeErr   error
+		wantCalls []string
+		wantErr   error
+	}
+
+	errPre := errors.New("pre failed")
+	errNode := errors.New("node failed")
+
+	tests := map[string]tc{
+		"success": {
+			wantCalls: []string{"pre1:[a b]", "pre2:[a b]", "run", "post:[a b]:<nil>"},
+		},
+		"pre hook aborts": {
+			preErr:    errPre,
+			wantCalls: []string{"pre1:[a b]"},
+			wantErr:   errPre,
+		},
+		"post hook sees node error": {
+			nodeErr:   errNode,
+			wantCalls: []string{"pre1:[a b]", "pre2:[a b]", "run", "post:[a]:node b: " + errNode.Error()},
+			wantErr:   errNode,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			nodes := map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+				"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) {
+					calls = append(calls, "run")
+					return 2, tt.nodeErr
+				}),
+			}
+			nodeIDs := func(nodes []NodeSummary) []ID {
+				var ids []ID
+				for _, n := range nodes {
+					ids = append(ids, n.ID)
+				}
+				return ids
+			}
+
+			_, err := Execute(context.Background(),
+				WithRegistry(nodes),
+				WithPreExecuteHook(func(ctx context.Context, nodes []NodeSummary) error {
+					calls = append(calls, fmt.Sprintf("pre1:%v", nodeIDs(nodes)))
+					return tt.preErr
+				}),
+				WithPreExecuteHook(func(ctx context.Context, nodes []NodeSummary) error {
+					calls = append(calls, fmt.Sprintf("pre2:%v", nodeIDs(nodes)))
+					return nil
+				}),
+				WithPostExecuteHook(func(ctx context.Context, results Results, err error) {
+					if ctx.Err() != nil {
+						t.Errorf("post hook context is done: %v", ctx.Err())
+					}
+					calls = append(calls, fmt.Sprintf("post:%v:%v", sortedIDs(resultIDs(results)), err))
+				}),
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func resultIDs(results Results) []ID {
+	ids := make([]ID, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,89 @@
+// Package httpserver provides HTTP middleware that executes a graft graph
+// for every request and makes the results available to handlers.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/user/", httpserver.Middleware(httpserver.For[user.Output]())(
+//	    http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        u, err := httpserver.ResultFromContext[user.Output](r.Context())
+//	        if err != nil {
+//	            http.Error(w, err.Error(), http.StatusInternalServerError)
+//	            return
+//	        }
+//	        json.NewEncoder(w).Encode(u)
+//	    }),
+//	))
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/grindlemire/graft"
+)
+
+// contextKey is the type for context keys used by this package.
+type contextKey struct{}
+
+// resultsKey is the context key for storing graph results.
+var resultsKey = contextKey{}
+
+// Runner executes a graph for a single request and returns its results.
+type Runner func(ctx context.Context) (graft.Results, error)
+
+// For returns a Runner that executes the node producing T and its
+// transitive dependencies via [graft.ExecuteFor].
+func For[T any](opts ...graft.Option) Runner {
+	return func(ctx context.Context) (graft.Results, error) {
+		_, results, err := graft.ExecuteFor[T](ctx, opts...)
+		return results, err
+	}
+}
+
+// All returns a Runner that executes the whole graph via [graft.Execute].
+func All(opts ...graft.Option) Runner {
+	return func(ctx context.Context) (graft.Results, error) {
+		return graft.Execute(ctx, opts...)
+	}
+}
+
+// Middleware runs the graph on every request using the request's context
+// and stores the results in the context passed to the next handler.
+// Retrieve them with [FromContext] or [ResultFromContext].
+//
+// If execution fails, the middleware responds with HTTP 500 and does not
+// call the next handler.
+func Middleware(run Runner) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			results, err := run(r.Context())
+			if err != nil {
+				http.Error(w, fmt.Sprintf("graft: %v", err), http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), resultsKey, results)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the graph results stored by [Middleware], or nil if
+// the context was not produced by the middleware.
+func FromContext(ctx context.Context) graft.Results {
+	results, _ := ctx.Value(resultsKey).(graft.Results)
+	return results
+}
+
+// ResultFromContext retrieves a node's output from the results stored by
+// [Middleware] with type assertion. See [graft.Result].
+func ResultFromContext[T any](ctx context.Context) (T, error) {
+	results, ok := ctx.Value(resultsKey).(graft.Results)
+	if !ok {
+		var zero T
+		return zero, &graft.DepError{Code: graft.ErrNoContext, Message: "no results in context"}
+	}
+	return graft.Result[T](results)
+}
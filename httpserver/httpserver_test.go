@@ -0,0 +1,119 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type configOutput struct{ Env string }
+type userOutput struct{ Name string }
+type failingOutput struct{}
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{Env: "test"}, nil },
+	})
+	graft.Register(graft.Node[userOutput]{
+		ID:        "user",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (userOutput, error) {
+			cfg, err := graft.Dep[configOutput](ctx)
+			if err != nil {
+				return userOutput{}, err
+			}
+			return userOutput{Name: "alice-" + cfg.Env}, nil
+		},
+	})
+	graft.Register(graft.Node[failingOutput]{
+		ID:  "failing",
+		Run: func(ctx context.Context) (failingOutput, error) { return failingOutput{}, errors.New("boom") },
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	type tc struct {
+		run        Runner
+		wantStatus int
+		wantBody   string
+	}
+
+	tests := map[string]tc{
+		"typed results available to handler": {
+			run:        For[userOutput](),
+			wantStatus: http.StatusOK,
+			wantBody:   "alice-test",
+		},
+		"execution error returns 500": {
+			run:        For[failingOutput](),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "boom",
+		},
+		"execute all fails on any node error": {
+			run:        All(),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			handler := Middleware(tt.run)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				u, err := ResultFromContext[userOutput](r.Context())
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusTeapot)
+					return
+				}
+				io.WriteString(w, u.Name)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/user/1", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	registerNodes(t)
+
+	var got graft.Results
+	handler := Middleware(For[userOutput]())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(got) != 2 {
+		t.Fatalf("FromContext() = %v, want config and user results", got)
+	}
+	if _, ok := got["config"]; !ok {
+		t.Error("expected config result")
+	}
+
+	if FromContext(context.Background()) != nil {
+		t.Error("FromContext() on a plain context should be nil")
+	}
+	if _, err := ResultFromContext[userOutput](context.Background()); err == nil {
+		t.Error("ResultFromContext() on a plain context should fail")
+	}
+}
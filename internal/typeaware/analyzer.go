@@ -2,6 +2,8 @@ package typeaware
 
 import (
 	"fmt"
+
+	"golang.org/x/tools/go/packages"
 )
 
 // Config configures the type-aware analyzer
@@ -52,6 +54,30 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 	}
 	a.debugf("Loaded %d packages", len(pkgs))
 
+	return a.analyzePackages(pkgs)
+}
+
+// AnalyzeWorkspace performs type-aware dependency analysis on every module
+// used by the go.work file in root, so nodes and Dep[T] calls can span
+// module boundaries.
+func (a *Analyzer) AnalyzeWorkspace(root string) ([]Result, error) {
+	a.debugf("Starting type-aware analysis of workspace %s", root)
+
+	// Phase 1: Load packages from all workspace modules
+	a.debugf("Loading workspace packages...")
+	loader := newPackageLoader(a.cfg)
+	pkgs, err := loader.LoadWorkspace(root)
+	if err != nil {
+		return nil, fmt.Errorf("loading workspace: %w", err)
+	}
+	a.debugf("Loaded %d packages", len(pkgs))
+
+	return a.analyzePackages(pkgs)
+}
+
+// analyzePackages runs the analysis phases that follow package loading
+func (a *Analyzer) analyzePackages(pkgs []*packages.Package) ([]Result, error) {
+
 	// Phase 2: Build SSA
 	a.debugf("Building SSA program...")
 	builder := newSSABuilder()
@@ -66,16 +92,32 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 	// Phase 3: Discover nodes
 	a.debugf("Discovering nodes...")
 	discoverer := newNodeDiscoverer(prog, prog.Fset, srcPkgs)
-	nodes, err := discoverer.FindNodes()
+	discovered, err := discoverer.FindNodes()
 	if err != nil {
 		return nil, fmt.Errorf("discovering nodes: %w", err)
 	}
-	a.debugf("Discovered %d nodes", len(nodes))
+
+	// Patch overrides replace registered nodes; keep them out of the
+	// mapping, cycle detection and reachability
+	var nodes, overrides []NodeDefinition
+	for _, node := range discovered {
+		if node.Override {
+			overrides = append(overrides, node)
+		} else {
+			nodes = append(nodes, node)
+		}
+	}
+	a.debugf("Discovered %d nodes and %d overrides", len(nodes), len(overrides))
 
 	for _, node := range nodes {
 		a.debugf("  - %s", node.String())
 	}
 
+	// The same ID registered twice panics at runtime
+	if err := checkDuplicateIDs(nodes); err != nil {
+		return nil, err
+	}
+
 	// Phase 4: Build type-to-ID mapping
 	a.debugf("Building type-to-ID mapping...")
 	mapper := newTypeIDMapper()
@@ -94,6 +136,9 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 	a.debugf("Extracting and analyzing dependencies...")
 	extractor := newDependencyExtractor(mapper, prog, prog.Fset)
 
+	// Overrides count as Run functions: a mock may share helpers too
+	outsideRun := depCallersOutsideRun(*srcPkgs, discovered)
+
 	var results []Result
 	for _, node := range nodes {
 		result, err := extractor.AnalyzeNode(node)
@@ -103,6 +148,13 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 			continue
 		}
 
+		result.DepCallsOutsideRun = sharedDepCallers(node, outsideRun)
+		for _, fn := range result.DepCallsOutsideRun {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%s calls graft.Dep but is also called outside any node's Run function, where Dep fails", fn,
+			))
+		}
+
 		a.debugf("Analyzed node %q: declared=%v, used=%v",
 			result.NodeID, result.DeclaredDeps, result.UsedDeps)
 
@@ -114,6 +166,17 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 		results = append(results, result)
 	}
 
+	// Warn about dependencies on deprecated nodes
+	deprecated := make(map[string]string)
+	for _, node := range nodes {
+		if node.Deprecated != "" {
+			deprecated[node.ID] = node.Deprecated
+		}
+	}
+	for i := range results {
+		results[i].Warnings = append(results[i].Warnings, deprecationWarnings(results[i], deprecated)...)
+	}
+
 	// Phase 6: Detect cycles and annotate results
 	a.debugf("Detecting cycles...")
 	detector := newCycleDetector(results)
@@ -137,6 +200,37 @@ func (a *Analyzer) Analyze(dir string) ([]Result, error) {
 		}
 	}
 
+	// Phase 7: Compute dependents and annotate leaf/unreachable nodes
+	a.debugf("Computing reachability...")
+	targets := findExecutionTargets(*srcPkgs, mapper)
+	annotateReachability(results, targets)
+	for _, r := range results {
+		if r.IsUnreachable {
+			a.debugf("Node %q is unreachable", r.NodeID)
+		}
+	}
+
+	// Phase 8: Check Patch overrides against the nodes they replace
+	for _, node := range overrides {
+		id, err := mapper.ResolveType(node.OutputType)
+		if err != nil {
+			// Patch is a no-op for an unregistered type
+			a.debugf("Skipping override of unregistered type %v", node.OutputType)
+			continue
+		}
+		node.ID = id
+
+		result, err := extractor.AnalyzeNode(node)
+		if err != nil {
+			a.debugf("Error analyzing override of %q: %v", id, err)
+			continue
+		}
+		result.IsOverride = true
+		result.Unused = nil
+		result.Warnings = nil
+		results = append(results, result)
+	}
+
 	a.debugf("Analysis complete: %d nodes analyzed", len(results))
 
 	return results, nil
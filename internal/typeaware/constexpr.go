@@ -0,0 +1,200 @@
+package typeaware
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxResolveDepth bounds how far resolveString follows values, guarding
+// against pathological initializers
+const maxResolveDepth = 32
+
+// resolveString evaluates an SSA value to a string when it is built only
+// from constants: literals and constant expressions (including iota), string
+// concatenation, package-level variables initialized once, and fmt.Sprintf
+// calls with a constant format
+func resolveString(v ssa.Value) (string, bool) {
+	val, ok := resolveValue(v, 0)
+	if !ok {
+		return "", false
+	}
+	s, ok := val.(string)
+	return s, ok
+}
+
+// resolveValue evaluates v to a string, int64, float64 or bool
+func resolveValue(v ssa.Value, depth int) (any, bool) {
+	if depth > maxResolveDepth {
+		return nil, false
+	}
+
+	switch v := v.(type) {
+	case *ssa.Const:
+		return constValue(v)
+
+	case *ssa.ChangeType:
+		return resolveValue(v.X, depth+1)
+
+	case *ssa.MakeInterface:
+		return resolveValue(v.X, depth+1)
+
+	case *ssa.Convert:
+		// Only conversions between string types keep the value intact
+		val, ok := resolveValue(v.X, depth+1)
+		if _, isStr := val.(string); !ok || !isStr {
+			return nil, false
+		}
+		return val, true
+
+	case *ssa.UnOp:
+		if g, ok := v.X.(*ssa.Global); ok && v.Op == token.MUL {
+			return resolveGlobal(g, depth+1)
+		}
+		return resolveValue(v.X, depth+1)
+
+	case *ssa.BinOp:
+		if v.Op != token.ADD {
+			return nil, false
+		}
+		x, okX := resolveValue(v.X, depth+1)
+		y, okY := resolveValue(v.Y, depth+1)
+		xs, isStrX := x.(string)
+		ys, isStrY := y.(string)
+		if !okX || !okY || !isStrX || !isStrY {
+			return nil, false
+		}
+		return xs + ys, true
+
+	case *ssa.Call:
+		return resolveSprintf(v, depth+1)
+	}
+
+	return nil, false
+}
+
+// constValue converts an SSA constant to a Go value
+func constValue(c *ssa.Const) (any, bool) {
+	if c.Value == nil {
+		return nil, false
+	}
+	switch c.Value.Kind() {
+	case constant.String:
+		return constant.StringVal(c.Value), true
+	case constant.Int:
+		n, exact := constant.Int64Val(c.Value)
+		return n, exact
+	case constant.Float:
+		f, _ := constant.Float64Val(c.Value)
+		return f, true
+	case constant.Bool:
+		return constant.BoolVal(c.Value), true
+	}
+	return nil, false
+}
+
+// resolveGlobal evaluates a package-level variable from the value stored to
+// it by the package initializer
+func resolveGlobal(g *ssa.Global, depth int) (any, bool) {
+	if g.Pkg == nil {
+		return nil, false
+	}
+	init := g.Pkg.Func("init")
+	if init == nil {
+		return nil, false
+	}
+
+	var val ssa.Value
+	for _, block := range init.Blocks {
+		for _, instr := range block.Instrs {
+			if store, ok := instr.(*ssa.Store); ok && store.Addr == g {
+				if val != nil {
+					// Assigned more than once; the value is not fixed
+					return nil, false
+				}
+				val = store.Val
+			}
+		}
+	}
+	if val == nil {
+		return nil, false
+	}
+	return resolveValue(val, depth)
+}
+
+// resolveSprintf evaluates fmt.Sprintf(format, args...) when the format and
+// every argument resolve to constants
+func resolveSprintf(call *ssa.Call, depth int) (any, bool) {
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.String() != "fmt.Sprintf" {
+		return nil, false
+	}
+	args := call.Common().Args
+	if len(args) != 2 {
+		return nil, false
+	}
+
+	format, ok := resolveValue(args[0], depth)
+	formatStr, isStr := format.(string)
+	if !ok || !isStr {
+		return nil, false
+	}
+
+	fmtArgs, ok := resolveVariadic(args[1], depth)
+	if !ok {
+		return nil, false
+	}
+	return fmt.Sprintf(formatStr, fmtArgs...), true
+}
+
+// resolveVariadic evaluates the slice passed as a function's variadic
+// argument. The elements are stored into an array that is then sliced, or
+// the slice is nil when no arguments are given.
+func resolveVariadic(v ssa.Value, depth int) ([]any, bool) {
+	if c, ok := v.(*ssa.Const); ok && c.IsNil() {
+		return nil, true
+	}
+
+	slice, ok := v.(*ssa.Slice)
+	if !ok {
+		return nil, false
+	}
+	arr, ok := slice.X.(*ssa.Alloc)
+	if !ok || arr.Referrers() == nil {
+		return nil, false
+	}
+
+	vals := make(map[int64]any)
+	for _, instr := range *arr.Referrers() {
+		indexAddr, ok := instr.(*ssa.IndexAddr)
+		if !ok || indexAddr.Referrers() == nil {
+			continue
+		}
+		idx, ok := resolveValue(indexAddr.Index, depth)
+		i, isInt := idx.(int64)
+		if !ok || !isInt {
+			return nil, false
+		}
+		for _, ref := range *indexAddr.Referrers() {
+			if store, ok := ref.(*ssa.Store); ok {
+				val, ok := resolveValue(store.Val, depth)
+				if !ok {
+					return nil, false
+				}
+				vals[i] = val
+			}
+		}
+	}
+
+	out := make([]any, len(vals))
+	for i := range out {
+		val, ok := vals[int64(i)]
+		if !ok {
+			return nil, false
+		}
+		out[i] = val
+	}
+	return out, true
+}
@@ -0,0 +1,112 @@
+package typeaware
+
+import (
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// callsDep reports whether fn itself (not its callees) calls graft.Dep[T]
+func callsDep(fn *ssa.Function) bool {
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			if call, ok := instr.(*ssa.Call); ok && isGraftDepCall(call) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// depCallersOutsideRun returns the functions that call graft.Dep[T] and can
+// be reached through static calls from code outside every node's Run
+// function. Dep only works in a context prepared by the engine, so such a
+// call fails whenever it is reached that way
+func depCallersOutsideRun(pkgs []*ssa.Package, nodes []NodeDefinition) map[*ssa.Function]bool {
+	analyzed := make(map[*ssa.Package]bool)
+	for _, pkg := range pkgs {
+		analyzed[pkg] = true
+	}
+
+	var runs []*ssa.Function
+	for _, node := range nodes {
+		if node.RunFunc != nil {
+			runs = append(runs, node.RunFunc)
+		}
+	}
+	inRun := reachableFunctions(runs, analyzed, true)
+
+	var roots []*ssa.Function
+	for _, fn := range packageFunctions(pkgs) {
+		if !inRun[fn] {
+			roots = append(roots, fn)
+		}
+	}
+
+	outside := make(map[*ssa.Function]bool)
+	// Closures declared outside Run are roots of their own, and those
+	// declared in a registration are the Run functions themselves, so nested
+	// closures are not followed here
+	for fn := range reachableFunctions(roots, analyzed, false) {
+		if callsDep(fn) {
+			outside[fn] = true
+		}
+	}
+	return outside
+}
+
+// reachableFunctions returns roots together with every function they reach
+// through static calls into the analyzed packages and, if closures is set,
+// through the closures nested in them
+func reachableFunctions(roots []*ssa.Function, analyzed map[*ssa.Package]bool, closures bool) map[*ssa.Function]bool {
+	seen := make(map[*ssa.Function]bool)
+
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		if seen[fn] {
+			return
+		}
+		seen[fn] = true
+
+		if closures {
+			for _, anon := range fn.AnonFuncs {
+				visit(anon)
+			}
+		}
+
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				callee := call.Common().StaticCallee()
+				if callee != nil && analyzed[callee.Package()] {
+					visit(callee)
+				}
+			}
+		}
+	}
+
+	for _, fn := range roots {
+		visit(fn)
+	}
+	return seen
+}
+
+// sharedDepCallers returns the sorted names of the functions reached from
+// node's Run function that are also in outside
+func sharedDepCallers(node NodeDefinition, outside map[*ssa.Function]bool) []string {
+	if node.RunFunc == nil {
+		return nil
+	}
+
+	var names []string
+	for _, fn := range runFunctions(node.RunFunc) {
+		if outside[fn] {
+			names = append(names, fn.String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
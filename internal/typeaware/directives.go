@@ -0,0 +1,95 @@
+package typeaware
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// ignoreDirective suppresses analysis findings for the graft.Register call
+// it is written on or directly above, e.g.
+//
+//	//graft:ignore unused:cache undeclared:db
+const ignoreDirective = "//graft:ignore"
+
+// ignoreDirectives returns the findings named by //graft:ignore comments
+// directly above or inside a Register call
+func (d *nodeDiscoverer) ignoreDirectives(call *ssa.Call) []string {
+	ce := callExpr(call)
+	if ce == nil {
+		return nil
+	}
+	start := d.fset.Position(ce.Pos())
+	end := d.fset.Position(ce.End())
+
+	var ignores []string
+	for _, cg := range d.fileComments(start.Filename) {
+		first := d.commentFset.Position(cg.Pos()).Line
+		last := d.commentFset.Position(cg.End()).Line
+		if last != start.Line-1 && (first < start.Line || last > end.Line) {
+			continue
+		}
+		for _, c := range cg.List {
+			ignores = append(ignores, parseIgnoreDirective(c.Text)...)
+		}
+	}
+	return ignores
+}
+
+// fileComments returns the comments of a source file, parsing it on first
+// use. SSA does not retain comments
+func (d *nodeDiscoverer) fileComments(filename string) []*ast.CommentGroup {
+	if groups, ok := d.comments[filename]; ok {
+		return groups
+	}
+	var groups []*ast.CommentGroup
+	f, err := parser.ParseFile(d.commentFset, filename, nil, parser.ParseComments|parser.SkipObjectResolution)
+	if err == nil {
+		groups = f.Comments
+	}
+	d.comments[filename] = groups
+	return groups
+}
+
+// parseIgnoreDirective returns the findings listed in a //graft:ignore
+// comment, or nil if text is not one
+func parseIgnoreDirective(text string) []string {
+	rest, ok := strings.CutPrefix(text, ignoreDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil
+	}
+	return strings.Fields(rest)
+}
+
+// suppressFindings removes the findings named by a node's //graft:ignore
+// directives from Undeclared and Unused, recording each one removed in
+// Suppressed. It returns warnings for directives it does not understand
+func suppressFindings(result *Result, ignores []string) []string {
+	var warnings []string
+	for _, ignore := range ignores {
+		kind, id, _ := strings.Cut(ignore, ":")
+		var findings *[]string
+		switch kind {
+		case "unused":
+			findings = &result.Unused
+		case "undeclared":
+			findings = &result.Undeclared
+		}
+		if findings == nil || id == "" {
+			warnings = append(warnings, fmt.Sprintf("unknown %s directive %q; want unused:<id> or undeclared:<id>", ignoreDirective, ignore))
+			continue
+		}
+
+		for i, f := range *findings {
+			if f == id {
+				*findings = append((*findings)[:i:i], (*findings)[i+1:]...)
+				result.Suppressed = append(result.Suppressed, ignore)
+				break
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,94 @@
+package typeaware
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnoreDirective(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want []string
+	}{
+		"single finding": {
+			text: "//graft:ignore unused:cache",
+			want: []string{"unused:cache"},
+		},
+		"multiple findings": {
+			text: "//graft:ignore unused:cache undeclared:db",
+			want: []string{"unused:cache", "undeclared:db"},
+		},
+		"no findings": {
+			text: "//graft:ignore",
+			want: []string{},
+		},
+		"other directive": {
+			text: "//graft:ignored unused:cache",
+		},
+		"spaced comment": {
+			text: "// graft:ignore unused:cache",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := parseIgnoreDirective(tt.text)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIgnoreDirective(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressFindings(t *testing.T) {
+	tests := map[string]struct {
+		result         Result
+		ignores        []string
+		wantUnused     []string
+		wantUndeclared []string
+		wantSuppressed []string
+		wantWarnings   int
+	}{
+		"suppresses matching findings": {
+			result:         Result{Unused: []string{"cache", "db"}, Undeclared: []string{"config"}},
+			ignores:        []string{"unused:cache", "undeclared:config"},
+			wantUnused:     []string{"db"},
+			wantSuppressed: []string{"unused:cache", "undeclared:config"},
+		},
+		"kind must match": {
+			result:         Result{Unused: []string{"cache"}},
+			ignores:        []string{"undeclared:cache"},
+			wantUnused:     []string{"cache"},
+			wantSuppressed: nil,
+		},
+		"unknown directive warns": {
+			result:       Result{Unused: []string{"cache"}},
+			ignores:      []string{"cache", "unused:", "cycle:cache"},
+			wantUnused:   []string{"cache"},
+			wantWarnings: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.result
+			warnings := suppressFindings(&result, tt.ignores)
+
+			if len(result.Unused) != len(tt.wantUnused) || (len(tt.wantUnused) > 0 && !reflect.DeepEqual(result.Unused, tt.wantUnused)) {
+				t.Errorf("Unused = %v, want %v", result.Unused, tt.wantUnused)
+			}
+			if len(result.Undeclared) != len(tt.wantUndeclared) {
+				t.Errorf("Undeclared = %v, want %v", result.Undeclared, tt.wantUndeclared)
+			}
+			if !reflect.DeepEqual(result.Suppressed, tt.wantSuppressed) {
+				t.Errorf("Suppressed = %v, want %v", result.Suppressed, tt.wantSuppressed)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -2,23 +2,38 @@ package typeaware
 
 import (
 	"fmt"
+	"go/ast"
 	"go/constant"
 	"go/token"
 	"go/types"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"golang.org/x/tools/go/ssa"
 )
 
+// DynamicID is the node ID reported for a registration whose ID could not
+// be determined statically, such as a node built from runtime values
+const DynamicID = "<dynamic>"
+
 // NodeDefinition represents a discovered node registration
 type NodeDefinition struct {
 	ID         string         // Node ID (e.g., "db")
 	File       string         // Source file path
 	OutputType types.Type     // The T in Node[T]
 	DependsOn  ssa.Value      // The DependsOn field value (for dataflow analysis)
-	RunFunc    *ssa.Function  // The Run function body
+	RunFunc    *ssa.Function  // The Run (or RunWithContext) function body
+	Cacheable  bool           // The Cacheable field value, if set to a constant
 	Position   token.Position // Source location for error reporting
+	EmptyID    bool           // The ID field is absent or an empty string literal
+	IsTest     bool           // Registered in a _test.go file
+	Override   bool           // Passed to graft.Patch[T] rather than graft.Register
+	Dynamic    bool           // The ID could not be determined statically; ID is DynamicID
+	Ignores    []string       // Findings to suppress from //graft:ignore directives, e.g. "unused:cache"
+	Deprecated string         // The Deprecated field value, if set to a constant
+
+	idDynamic bool // The ID field is set to a non-constant value
 }
 
 // String returns a human-readable summary of the node definition
@@ -48,6 +63,21 @@ func isGraftRegisterCall(call *ssa.Call) bool {
 		nameRegex.MatchString(callee.Name())
 }
 
+var patchNameRegex = regexp.MustCompile(`^Patch\[.*\]$`)
+
+// isGraftPatchCall checks if a call instruction calls graft.Patch. The
+// Node[T] passed to Patch replaces the registered node for T, so it has no
+// ID of its own
+func isGraftPatchCall(call *ssa.Call) bool {
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.Origin() == nil {
+		return false
+	}
+
+	return callee.Origin().String() == "github.com/grindlemire/graft.Patch" &&
+		patchNameRegex.MatchString(callee.Name())
+}
+
 var depNameRegex = regexp.MustCompile(`^Dep\[.*\]$`)
 
 // isGraftDepCall checks if a call instruction calls graft.Dep
@@ -71,6 +101,9 @@ type nodeDiscoverer struct {
 	prog    *ssa.Program
 	fset    *token.FileSet
 	srcPkgs *[]*ssa.Package // Source packages we're analyzing
+
+	commentFset *token.FileSet                 // Files parsed for comments; only line numbers are used
+	comments    map[string][]*ast.CommentGroup // Comments by file name
 }
 
 // newNodeDiscoverer creates a new node discoverer
@@ -79,37 +112,51 @@ func newNodeDiscoverer(prog *ssa.Program, fset *token.FileSet, srcPkgs *[]*ssa.P
 		prog:    prog,
 		fset:    fset,
 		srcPkgs: srcPkgs,
+
+		commentFset: token.NewFileSet(),
+		comments:    make(map[string][]*ast.CommentGroup),
 	}
 }
 
-// FindNodes finds all graft.Register() calls and extracts node definitions
+// FindNodes finds all graft.Register() and graft.Patch() calls and extracts
+// node definitions
 func (d *nodeDiscoverer) FindNodes() ([]NodeDefinition, error) {
 	var nodes []NodeDefinition
 
-	// Walk only the source packages we're analyzing
-	// This is more targeted than walking all packages in the program
-	for _, pkg := range *d.srcPkgs {
-		// Look in all functions (not just init)
-		// Register calls can be in init, package-level functions, or methods
-		for _, member := range pkg.Members {
-			fn, ok := member.(*ssa.Function)
-			if !ok {
-				continue
-			}
-
-			// Walk instructions in function looking for Register calls
-			for _, block := range fn.Blocks {
-				for _, instr := range block.Instrs {
-					if call, ok := instr.(*ssa.Call); ok {
-						if isGraftRegisterCall(call) {
-							node, err := d.extractNodeDefinition(call)
-							if err != nil {
-								// Log warning but continue analyzing other nodes
-								continue
-							}
-							nodes = append(nodes, node)
-						}
+	// Walk only the source packages we're analyzing. Register calls can be
+	// in init, package-level functions, methods, or closures such as the
+	// one passed to sync.Once.Do
+	for _, fn := range packageFunctions(*d.srcPkgs) {
+		// Walk instructions in function looking for Register calls
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				switch {
+				case isGraftRegisterCall(call):
+					node, err := d.extractNodeDefinition(call)
+					if err != nil {
+						// Log warning but continue analyzing other nodes
+						continue
+					}
+					if node.EmptyID {
+						return nil, fmt.Errorf("%s: graft.Node[%s] registered with empty ID", node.Position, node.OutputType)
 					}
+					nodes = append(nodes, node)
+
+				case isGraftPatchCall(call):
+					// The override's ID is resolved from its output type
+					// once all registrations are mapped
+					node, err := d.extractNodeDefinition(call)
+					if err != nil {
+						continue
+					}
+					node.ID = ""
+					node.Dynamic = false
+					node.Override = true
+					nodes = append(nodes, node)
 				}
 			}
 		}
@@ -156,6 +203,8 @@ func (d *nodeDiscoverer) extractNodeDefinition(call *ssa.Call) (NodeDefinition,
 		OutputType: outputType,
 		Position:   pos,
 		File:       pos.Filename,
+		IsTest:     strings.HasSuffix(pos.Filename, "_test.go"),
+		Ignores:    d.ignoreDirectives(call),
 	}
 
 	// Try to extract ID, DependsOn, and Run from the node value
@@ -164,15 +213,52 @@ func (d *nodeDiscoverer) extractNodeDefinition(call *ssa.Call) (NodeDefinition,
 	_ = d.extractNodeFieldsFromFunction(fn, nodeValue, &nodeDef)
 	// Don't fail if field extraction doesn't work - we still have the type
 
-	// For now, if we can't extract ID, try to use the package name as a fallback
+	// A node built by a factory (graft.Register(newDBNode())) has its
+	// fields set inside the factory
+	if nodeDef.ID == "" && !nodeDef.idDynamic {
+		if factory, ret := factoryResult(nodeValue); factory != nil {
+			_ = d.extractNodeFieldsFromFunction(factory, ret, &nodeDef)
+		}
+	}
+
 	if nodeDef.ID == "" {
-		// Use "unknown" as placeholder - we'll improve extraction later
-		nodeDef.ID = fmt.Sprintf("unknown_%s", outputType.String())
+		nodeDef.ID = DynamicID
+		nodeDef.Dynamic = true
 	}
 
 	return nodeDef, nil
 }
 
+// factoryResult returns the function called to build a node value and the
+// value it returns, when v is the result of a static call to a function
+// with a single return statement
+func factoryResult(v ssa.Value) (*ssa.Function, ssa.Value) {
+	call, ok := v.(*ssa.Call)
+	if !ok {
+		return nil, nil
+	}
+	callee := call.Common().StaticCallee()
+	if callee == nil {
+		return nil, nil
+	}
+
+	var ret *ssa.Return
+	for _, block := range callee.Blocks {
+		for _, instr := range block.Instrs {
+			if r, ok := instr.(*ssa.Return); ok {
+				if ret != nil {
+					return nil, nil
+				}
+				ret = r
+			}
+		}
+	}
+	if ret == nil || len(ret.Results) != 1 {
+		return nil, nil
+	}
+	return callee, ret.Results[0]
+}
+
 // extractOutputType extracts T from Node[T] type
 func (d *nodeDiscoverer) extractOutputType(t types.Type) (types.Type, error) {
 	// Handle pointer types
@@ -209,6 +295,14 @@ func (d *nodeDiscoverer) extractNodeFieldsFromFunction(fn *ssa.Function, nodeVal
 		baseValue = unop.X
 	}
 
+	// A package-level var (var dbNode = graft.Node[T]{...}) has its fields
+	// stored by the package initializer, not by the function registering it
+	if g, ok := baseValue.(*ssa.Global); ok && g.Pkg != nil {
+		if init := g.Pkg.Func("init"); init != nil {
+			fn = init
+		}
+	}
+
 	// Walk all instructions in all blocks to find field assignments
 	for _, block := range fn.Blocks {
 		for _, instr := range block.Instrs {
@@ -226,6 +320,12 @@ func (d *nodeDiscoverer) extractNodeFieldsFromFunction(fn *ssa.Function, nodeVal
 		}
 	}
 
+	// Only a literal built in this function shows every field assignment;
+	// a node returned from a helper may have its ID set elsewhere.
+	if _, ok := baseValue.(*ssa.Alloc); ok && nodeDef.ID == "" && !nodeDef.idDynamic {
+		nodeDef.EmptyID = true
+	}
+
 	return nil
 }
 
@@ -262,17 +362,33 @@ func (d *nodeDiscoverer) extractFieldValue(fieldName string, fa *ssa.FieldAddr,
 		if store, ok := instr.(*ssa.Store); ok {
 			switch fieldName {
 			case "ID":
-				// Extract ID - should be a string constant or graft.ID value
+				// Extract ID - a constant, or a value built only from constants
 				if c, ok := store.Val.(*ssa.Const); ok {
-					nodeDef.ID = constant.StringVal(c.Value)
+					if c.Value != nil {
+						nodeDef.ID = constant.StringVal(c.Value)
+					}
+				} else if id, ok := resolveString(store.Val); ok {
+					nodeDef.ID = id
+				} else {
+					// A value only known at runtime
+					nodeDef.idDynamic = true
+				}
+
+			case "Cacheable":
+				if c, ok := store.Val.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.Bool {
+					nodeDef.Cacheable = constant.BoolVal(c.Value)
+				}
+
+			case "Deprecated":
+				if msg, ok := resolveString(store.Val); ok {
+					nodeDef.Deprecated = msg
 				}
-				// Could also be a variable - trace it if needed
 
 			case "DependsOn":
 				// Store the SSA value for later analysis
 				nodeDef.DependsOn = store.Val
 
-			case "Run":
+			case "Run", "RunWithContext":
 				// Extract the Run function
 				if fn, ok := store.Val.(*ssa.Function); ok {
 					nodeDef.RunFunc = fn
@@ -0,0 +1,72 @@
+package typeaware
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// DuplicateIDError reports node IDs registered by more than one
+// graft.Register call. Registering the same ID twice panics at runtime, so
+// analysis stops rather than reporting results for an ambiguous graph.
+type DuplicateIDError struct {
+	// Duplicates maps each duplicated node ID to the positions of the
+	// graft.Register calls that register it, sorted by position.
+	Duplicates map[string][]token.Position
+}
+
+// Error lists each duplicated ID with its registration sites
+func (e *DuplicateIDError) Error() string {
+	ids := make([]string, 0, len(e.Duplicates))
+	for id := range e.Duplicates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	b.WriteString("duplicate node IDs:")
+	for _, id := range ids {
+		sites := make([]string, len(e.Duplicates[id]))
+		for i, pos := range e.Duplicates[id] {
+			sites[i] = pos.String()
+		}
+		fmt.Fprintf(&b, "\n  %q registered at %s", id, strings.Join(sites, ", "))
+	}
+	return b.String()
+}
+
+// checkDuplicateIDs returns a *DuplicateIDError if two registrations at
+// different positions share a node ID. Dynamic IDs are unknown, and test
+// files often register their own copies of nodes for separate test
+// binaries, so both are ignored.
+func checkDuplicateIDs(nodes []NodeDefinition) error {
+	sites := make(map[string][]token.Position)
+	seen := make(map[token.Position]bool)
+	for _, node := range nodes {
+		if node.Dynamic || node.IsTest || seen[node.Position] {
+			continue
+		}
+		seen[node.Position] = true
+		sites[node.ID] = append(sites[node.ID], node.Position)
+	}
+
+	dups := make(map[string][]token.Position)
+	for id, positions := range sites {
+		if len(positions) < 2 {
+			continue
+		}
+		sort.Slice(positions, func(i, j int) bool {
+			if positions[i].Filename != positions[j].Filename {
+				return positions[i].Filename < positions[j].Filename
+			}
+			return positions[i].Offset < positions[j].Offset
+		})
+		dups[id] = positions
+	}
+
+	if len(dups) == 0 {
+		return nil
+	}
+	return &DuplicateIDError{Duplicates: dups}
+}
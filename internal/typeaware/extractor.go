@@ -2,7 +2,7 @@ package typeaware
 
 import (
 	"fmt"
-	"go/constant"
+	"go/ast"
 	"go/token"
 	"go/types"
 
@@ -25,28 +25,62 @@ func newDependencyExtractor(mapper *typeIDMapper, prog *ssa.Program, fset *token
 	}
 }
 
+// depRef is a dependency ID together with the source position that names
+// it: the DependsOn element or the Dep[T] call. pos is token.NoPos when the
+// position is unknown
+type depRef struct {
+	id  string
+	pos token.Pos
+}
+
+// refIDs returns the IDs of refs in order
+func refIDs(refs []depRef) []string {
+	ids := make([]string, len(refs))
+	for i, r := range refs {
+		ids[i] = r.id
+	}
+	return ids
+}
+
+// refPositions maps each ID in refs to the first known position naming it
+func (e *dependencyExtractor) refPositions(refs []depRef) map[string]token.Position {
+	positions := make(map[string]token.Position)
+	for _, r := range refs {
+		if _, ok := positions[r.id]; ok || !r.pos.IsValid() {
+			continue
+		}
+		positions[r.id] = e.fset.Position(r.pos)
+	}
+	return positions
+}
+
 // ExtractDeclared extracts declared dependencies from a node's DependsOn field
 func (e *dependencyExtractor) ExtractDeclared(node NodeDefinition) ([]string, error) {
+	return refIDs(e.declaredRefs(node)), nil
+}
+
+// declaredRefs extracts declared dependencies with their positions
+func (e *dependencyExtractor) declaredRefs(node NodeDefinition) []depRef {
 	if node.DependsOn == nil {
 		// No dependencies declared
-		return []string{}, nil
+		return []depRef{}
 	}
 
 	// DependsOn is []graft.ID
 	// In SSA, this is typically a slice literal or a reference to one
 	// We need to trace it to find the actual ID values
 
-	ids, err := e.extractIDsFromValue(node.DependsOn)
+	refs, err := e.extractIDsFromValue(node.DependsOn)
 	if err != nil {
 		// If we can't extract, return empty list
-		return []string{}, nil
+		return []depRef{}
 	}
 
-	return ids, nil
+	return refs
 }
 
 // extractIDsFromValue extracts ID strings from an SSA value representing []graft.ID
-func (e *dependencyExtractor) extractIDsFromValue(v ssa.Value) ([]string, error) {
+func (e *dependencyExtractor) extractIDsFromValue(v ssa.Value) ([]depRef, error) {
 	// Check if this is an Alloc (local variable)
 	if alloc, ok := v.(*ssa.Alloc); ok {
 		// Find stores to this alloc
@@ -63,13 +97,61 @@ func (e *dependencyExtractor) extractIDsFromValue(v ssa.Value) ([]string, error)
 		return e.extractIDsFromMakeSlice(makeSlice)
 	}
 
+	// Check if this is a graft.Deps or graft.DepsOf call
+	if call, ok := v.(*ssa.Call); ok {
+		return e.extractIDsFromDepsCall(call)
+	}
+
 	// For now, return empty if we can't handle it
-	return []string{}, fmt.Errorf("cannot extract IDs from %T", v)
+	return []depRef{}, fmt.Errorf("cannot extract IDs from %T", v)
+}
+
+// extractIDsFromDepsCall extracts IDs from graft.Deps(ids...), which takes
+// the IDs as arguments, and graft.DepsOf* calls, which name them by type
+func (e *dependencyExtractor) extractIDsFromDepsCall(call *ssa.Call) ([]depRef, error) {
+	callee := call.Common().StaticCallee()
+	if callee == nil {
+		return []depRef{}, fmt.Errorf("cannot extract IDs from dynamic call")
+	}
+
+	if callee.Origin() == nil {
+		if callee.String() != "github.com/grindlemire/graft.Deps" {
+			return []depRef{}, fmt.Errorf("cannot extract IDs from call to %s", callee)
+		}
+		args := call.Common().Args
+		if len(args) == 0 {
+			return []depRef{}, nil
+		}
+		if _, ok := args[0].(*ssa.Const); ok {
+			// Deps() with no arguments passes a nil slice
+			return []depRef{}, nil
+		}
+		return e.extractIDsFromValue(args[0])
+	}
+
+	switch callee.Origin().String() {
+	case "github.com/grindlemire/graft.DepsOf",
+		"github.com/grindlemire/graft.DepsOf2",
+		"github.com/grindlemire/graft.DepsOf3",
+		"github.com/grindlemire/graft.DepsOf4":
+	default:
+		return []depRef{}, fmt.Errorf("cannot extract IDs from call to %s", callee)
+	}
+
+	// The type arguments have no positions of their own; use the call's
+	var refs []depRef
+	pos := callPos(call)
+	for _, typeArg := range callee.TypeArgs() {
+		if id, err := e.mapper.ResolveType(typeArg); err == nil {
+			refs = append(refs, depRef{id: id, pos: pos})
+		}
+	}
+	return refs, nil
 }
 
 // extractIDsFromAlloc extracts IDs from an allocated slice
-func (e *dependencyExtractor) extractIDsFromAlloc(alloc *ssa.Alloc) ([]string, error) {
-	var ids []string
+func (e *dependencyExtractor) extractIDsFromAlloc(alloc *ssa.Alloc) ([]depRef, error) {
+	var ids []depRef
 
 	if alloc.Referrers() == nil {
 		return ids, nil
@@ -84,7 +166,7 @@ func (e *dependencyExtractor) extractIDsFromAlloc(alloc *ssa.Alloc) ([]string, e
 					if s, ok := store.(*ssa.Store); ok {
 						// Extract the ID from the stored value
 						if id, err := e.extractIDFromValue(s.Val); err == nil {
-							ids = append(ids, id)
+							ids = append(ids, depRef{id: id, pos: s.Pos()})
 						}
 					}
 				}
@@ -96,14 +178,14 @@ func (e *dependencyExtractor) extractIDsFromAlloc(alloc *ssa.Alloc) ([]string, e
 }
 
 // extractIDsFromSlice extracts IDs from a slice operation
-func (e *dependencyExtractor) extractIDsFromSlice(slice *ssa.Slice) ([]string, error) {
+func (e *dependencyExtractor) extractIDsFromSlice(slice *ssa.Slice) ([]depRef, error) {
 	// Extract from the underlying array
 	return e.extractIDsFromValue(slice.X)
 }
 
 // extractIDsFromMakeSlice extracts IDs from a MakeSlice
-func (e *dependencyExtractor) extractIDsFromMakeSlice(makeSlice *ssa.MakeSlice) ([]string, error) {
-	var ids []string
+func (e *dependencyExtractor) extractIDsFromMakeSlice(makeSlice *ssa.MakeSlice) ([]depRef, error) {
+	var ids []depRef
 
 	// Look for stores to the slice
 	if makeSlice.Referrers() == nil {
@@ -116,7 +198,7 @@ func (e *dependencyExtractor) extractIDsFromMakeSlice(makeSlice *ssa.MakeSlice)
 				for _, store := range *indexAddr.Referrers() {
 					if s, ok := store.(*ssa.Store); ok {
 						if id, err := e.extractIDFromValue(s.Val); err == nil {
-							ids = append(ids, id)
+							ids = append(ids, depRef{id: id, pos: s.Pos()})
 						}
 					}
 				}
@@ -129,69 +211,145 @@ func (e *dependencyExtractor) extractIDsFromMakeSlice(makeSlice *ssa.MakeSlice)
 
 // extractIDFromValue extracts a single ID from an SSA value
 func (e *dependencyExtractor) extractIDFromValue(v ssa.Value) (string, error) {
-	// Handle constants (string literals)
-	if c, ok := v.(*ssa.Const); ok {
-		if c.Value != nil {
-			return constant.StringVal(c.Value), nil
-		}
-	}
-
-	// Handle UnOp (dereferencing, address-of, etc.)
-	if unop, ok := v.(*ssa.UnOp); ok {
-		return e.extractIDFromValue(unop.X)
-	}
-
-	// Handle ChangeType
-	if ct, ok := v.(*ssa.ChangeType); ok {
-		return e.extractIDFromValue(ct.X)
+	if id, ok := resolveString(v); ok {
+		return id, nil
 	}
-
-	// Handle Convert
-	if conv, ok := v.(*ssa.Convert); ok {
-		return e.extractIDFromValue(conv.X)
-	}
-
 	return "", fmt.Errorf("cannot extract ID from %T", v)
 }
 
 // ExtractUsed extracts used dependencies from a node's Run function
 func (e *dependencyExtractor) ExtractUsed(node NodeDefinition) ([]string, error) {
+	return refIDs(e.usedRefs(node)), nil
+}
+
+// usedRefs extracts used dependencies with the position of the first
+// Dep[T] call for each
+func (e *dependencyExtractor) usedRefs(node NodeDefinition) []depRef {
 	if node.RunFunc == nil {
 		// No Run function - no dependencies can be used
-		return []string{}, nil
+		return []depRef{}
 	}
 
-	var ids []string
+	var refs []depRef
 	seen := make(map[string]bool)
 
-	// Walk all instructions in the Run function
-	for _, block := range node.RunFunc.Blocks {
-		for _, instr := range block.Instrs {
-			if call, ok := instr.(*ssa.Call); ok {
-				if isGraftDepCall(call) {
-					// Extract the type parameter
-					depType, err := e.extractDepTypeParameter(call)
-					if err != nil {
-						continue
-					}
+	// Walk all instructions in the Run function and everything it reaches
+	for _, fn := range runFunctions(node.RunFunc) {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				if call, ok := instr.(*ssa.Call); ok {
+					if isGraftDepCall(call) {
+						// Extract the type parameter
+						depType, err := e.extractDepTypeParameter(call)
+						if err != nil {
+							continue
+						}
 
-					// Resolve type to ID
-					id, err := e.mapper.ResolveType(depType)
-					if err != nil {
-						// Type not in mapping - skip this dependency
-						continue
-					}
+						// Resolve type to ID
+						id, err := e.mapper.ResolveType(depType)
+						if err != nil && e.mapper.IsDynamic(depType) {
+							id = DynamicID
+						} else if err != nil {
+							// Type not in mapping - skip this dependency
+							continue
+						}
 
-					if !seen[id] {
-						ids = append(ids, id)
-						seen[id] = true
+						if !seen[id] {
+							refs = append(refs, depRef{id: id, pos: callPos(call)})
+							seen[id] = true
+						}
 					}
 				}
 			}
 		}
 	}
 
-	return ids, nil
+	return refs
+}
+
+// callPos returns the start of a call expression. SSA records a call at its
+// opening parenthesis, so the enclosing function's syntax is searched for
+// the call expression; the parenthesis is used if it cannot be found
+func callPos(call *ssa.Call) token.Pos {
+	if ce := callExpr(call); ce != nil {
+		return ce.Pos()
+	}
+	return call.Pos()
+}
+
+// callExpr returns the syntax of a call, or nil if the enclosing function
+// has no syntax
+func callExpr(call *ssa.Call) *ast.CallExpr {
+	lparen := call.Pos()
+	syntax := call.Parent().Syntax()
+	if syntax == nil || !lparen.IsValid() {
+		return nil
+	}
+
+	var found *ast.CallExpr
+	ast.Inspect(syntax, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && ce.Lparen == lparen {
+			found = ce
+		}
+		return found == nil
+	})
+	return found
+}
+
+// hasNestedExecution reports whether a node's Run function, or anything it
+// reaches, calls graft.Execute or graft.ExecuteFor[T] and its variants
+func hasNestedExecution(node NodeDefinition) bool {
+	if node.RunFunc == nil {
+		return false
+	}
+	for _, fn := range runFunctions(node.RunFunc) {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				if call, ok := instr.(*ssa.Call); ok && (isGraftExecuteCall(call) || isGraftExecuteForCall(call)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// runFunctions returns the Run function together with every function it can
+// reach through nested closures and static calls to functions and methods
+// declared in the same package, so that Dep[T] calls made from helpers are
+// attributed to the node
+func runFunctions(run *ssa.Function) []*ssa.Function {
+	var fns []*ssa.Function
+	seen := make(map[*ssa.Function]bool)
+
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		if fn == nil || seen[fn] {
+			return
+		}
+		seen[fn] = true
+		fns = append(fns, fn)
+
+		for _, anon := range fn.AnonFuncs {
+			visit(anon)
+		}
+
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				callee := call.Common().StaticCallee()
+				if callee != nil && callee.Pkg != nil && callee.Pkg == run.Pkg {
+					visit(callee)
+				}
+			}
+		}
+	}
+
+	visit(run)
+	return fns
 }
 
 // extractDepTypeParameter extracts the type parameter from a Dep[T]() call
@@ -223,23 +381,30 @@ func (e *dependencyExtractor) AnalyzeNode(node NodeDefinition) (Result, error) {
 	result := Result{
 		NodeID: node.ID,
 		File:   node.File,
+		Line:   node.Position.Line,
+		IsTest: node.IsTest,
 	}
 
 	// Extract declared dependencies
-	declared, err := e.ExtractDeclared(node)
-	if err != nil {
-		// Log but continue
-		declared = []string{}
-	}
+	declaredRefs := e.declaredRefs(node)
+	declared := refIDs(declaredRefs)
 	result.DeclaredDeps = declared
-
-	// Extract used dependencies
-	used, err := e.ExtractUsed(node)
-	if err != nil {
-		// Log but continue
-		used = []string{}
+	result.DeclaredDepPositions = e.refPositions(declaredRefs)
+
+	// Extract used dependencies. The ID of a node registered dynamically is
+	// unknown, so its use cannot be matched against DependsOn
+	usedRefs := e.usedRefs(node)
+	usesDynamic := false
+	for i, r := range usedRefs {
+		if r.id == DynamicID {
+			usedRefs = append(usedRefs[:i:i], usedRefs[i+1:]...)
+			usesDynamic = true
+			break
+		}
 	}
+	used := refIDs(usedRefs)
 	result.UsedDeps = used
+	result.UsedDepPositions = e.refPositions(usedRefs)
 
 	// Build sets for comparison
 	declaredSet := make(map[string]bool)
@@ -266,5 +431,67 @@ func (e *dependencyExtractor) AnalyzeNode(node NodeDefinition) (Result, error) {
 		}
 	}
 
+	if usesDynamic {
+		result.Unused = nil
+	}
+	directiveWarnings := suppressFindings(&result, node.Ignores)
+
+	result.HasNestedExecution = hasNestedExecution(node)
+	result.Warnings = nodeWarnings(result, node.Cacheable)
+	result.Warnings = append(result.Warnings, directiveWarnings...)
+	if node.Dynamic {
+		result.Warnings = append(result.Warnings,
+			"node ID could not be determined statically; dependency analysis of this node is incomplete")
+	}
+	if usesDynamic {
+		result.Warnings = append(result.Warnings,
+			"uses the output of a node whose ID could not be determined statically; unused dependencies are not reported")
+	}
+
 	return result, nil
 }
+
+// highCouplingThreshold is the number of declared dependencies above which
+// a node is reported as highly coupled
+const highCouplingThreshold = 10
+
+// nodeWarnings returns non-fatal observations about an analyzed node
+func nodeWarnings(r Result, cacheable bool) []string {
+	var warnings []string
+
+	if len(r.DeclaredDeps) > highCouplingThreshold {
+		warnings = append(warnings, fmt.Sprintf(
+			"high coupling: %d declared dependencies (more than %d)",
+			len(r.DeclaredDeps), highCouplingThreshold,
+		))
+	}
+
+	if len(r.UsedDeps) == 0 {
+		if cacheable {
+			warnings = append(warnings, "cacheable node never calls Dep[T]; caching a node without dependencies is rarely useful")
+		}
+		if len(r.DeclaredDeps) > 0 {
+			warnings = append(warnings, "DependsOn is declared but Run never calls Dep[T]; dependencies may be accessed without type safety")
+		}
+	}
+
+	if r.HasNestedExecution {
+		warnings = append(warnings, fmt.Sprintf(
+			"node %s invokes graft.Execute internally — dependencies may be untracked", r.NodeID,
+		))
+	}
+
+	return warnings
+}
+
+// deprecationWarnings returns a warning for each declared dependency of r
+// on a deprecated node, given the deprecation message of each such node
+func deprecationWarnings(r Result, deprecated map[string]string) []string {
+	var warnings []string
+	for _, dep := range r.DeclaredDeps {
+		if msg, ok := deprecated[dep]; ok {
+			warnings = append(warnings, fmt.Sprintf("depends on deprecated node %q: %s", dep, msg))
+		}
+	}
+	return warnings
+}
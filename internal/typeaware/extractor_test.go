@@ -187,3 +187,59 @@ func TestNewDependencyExtractor(t *testing.T) {
 		t.Error("extractor.fset not set correctly")
 	}
 }
+
+func TestNodeWarnings(t *testing.T) {
+	manyDeps := []string{"d1", "d2", "d3", "d4", "d5", "d6", "d7", "d8", "d9", "d10", "d11"}
+
+	tests := map[string]struct {
+		result       Result
+		cacheable    bool
+		wantWarnings []string
+	}{
+		"no warnings": {
+			result: Result{DeclaredDeps: []string{"config"}, UsedDeps: []string{"config"}},
+		},
+		"high coupling": {
+			result: Result{DeclaredDeps: manyDeps, UsedDeps: manyDeps},
+			wantWarnings: []string{
+				"high coupling: 11 declared dependencies (more than 10)",
+			},
+		},
+		"cacheable without Dep calls": {
+			result:    Result{DeclaredDeps: []string{}, UsedDeps: []string{}},
+			cacheable: true,
+			wantWarnings: []string{
+				"cacheable node never calls Dep[T]; caching a node without dependencies is rarely useful",
+			},
+		},
+		"DependsOn without Dep calls": {
+			result: Result{DeclaredDeps: []string{"config"}, UsedDeps: []string{}},
+			wantWarnings: []string{
+				"DependsOn is declared but Run never calls Dep[T]; dependencies may be accessed without type safety",
+			},
+		},
+		"nested execution": {
+			result: Result{NodeID: "app", DeclaredDeps: []string{"config"}, UsedDeps: []string{"config"}, HasNestedExecution: true},
+			wantWarnings: []string{
+				"node app invokes graft.Execute internally — dependencies may be untracked",
+			},
+		},
+		"non-cacheable leaf has no warnings": {
+			result: Result{DeclaredDeps: []string{}, UsedDeps: []string{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := nodeWarnings(tt.result, tt.cacheable)
+			if len(got) != len(tt.wantWarnings) {
+				t.Fatalf("nodeWarnings() = %v, want %v", got, tt.wantWarnings)
+			}
+			for i := range got {
+				if got[i] != tt.wantWarnings[i] {
+					t.Errorf("warning[%d] = %q, want %q", i, got[i], tt.wantWarnings[i])
+				}
+			}
+		})
+	}
+}
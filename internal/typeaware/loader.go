@@ -2,6 +2,8 @@ package typeaware
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -34,11 +36,46 @@ func newPackageLoader(cfg Config) *packageLoader {
 
 // Load loads all packages in the given directory
 func (l *packageLoader) Load(dir string) ([]*packages.Package, error) {
+	return l.LoadPatterns(dir, "./...")
+}
+
+// LoadWorkspace loads every package of every module used by the go.work
+// file in root.
+func (l *packageLoader) LoadWorkspace(root string) ([]*packages.Package, error) {
+	modPaths, err := workspaceModulePaths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := make([]string, len(modPaths))
+	for i, p := range modPaths {
+		patterns[i] = p + "/..."
+	}
+
+	// Point the go command at this workspace explicitly, overriding any
+	// GOWORK from the environment. Workspace mode rejects -mod=mod, so drop
+	// any -mod flag inherited from GOFLAGS.
+	var goflags []string
+	for _, f := range strings.Fields(os.Getenv("GOFLAGS")) {
+		if !strings.HasPrefix(f, "-mod=") {
+			goflags = append(goflags, f)
+		}
+	}
+	l.cfg.Env = append(os.Environ(),
+		"GOWORK="+filepath.Join(root, "go.work"),
+		"GOFLAGS="+strings.Join(goflags, " "),
+	)
+
+	return l.LoadPatterns(root, patterns...)
+}
+
+// LoadPatterns loads the packages matching patterns, resolved from dir
+func (l *packageLoader) LoadPatterns(dir string, patterns ...string) ([]*packages.Package, error) {
 	// Update Dir to the target directory
 	l.cfg.Dir = dir
 
-	// Load all packages recursively
-	pkgs, err := packages.Load(l.cfg, "./...")
+	// Load all matching packages
+	pkgs, err := packages.Load(l.cfg, patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("loading packages: %w", err)
 	}
@@ -60,5 +97,33 @@ func (l *packageLoader) Load(dir string) ([]*packages.Package, error) {
 		return nil, fmt.Errorf("package errors: %v", errs[0])
 	}
 
+	if l.cfg.Tests {
+		pkgs = dropTestDuplicates(pkgs)
+	}
+
 	return pkgs, nil
 }
+
+// dropTestDuplicates removes the packages that loading with tests adds
+// alongside the ones under analysis: a package is dropped in favor of its
+// test variant ("pkg [pkg.test]"), which contains the same files plus the
+// package's _test.go files, and generated test main packages are dropped
+// entirely. Otherwise every node would be discovered twice
+func dropTestDuplicates(pkgs []*packages.Package) []*packages.Package {
+	variants := make(map[string]bool)
+	for _, p := range pkgs {
+		variants[p.ID] = true
+	}
+
+	var kept []*packages.Package
+	for _, p := range pkgs {
+		if variants[p.ID+" ["+p.PkgPath+".test]"] {
+			continue
+		}
+		if p.Name == "main" && strings.HasSuffix(p.PkgPath, ".test") {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
@@ -10,6 +10,7 @@ import (
 type typeIDMapper struct {
 	typeToID map[string]string     // Canonical type string → node ID
 	idToType map[string]types.Type // Node ID → type
+	dynamic  map[string]bool       // Canonical type strings of nodes with a DynamicID
 }
 
 // newTypeIDMapper creates a new type-to-ID mapper
@@ -17,6 +18,7 @@ func newTypeIDMapper() *typeIDMapper {
 	return &typeIDMapper{
 		typeToID: make(map[string]string),
 		idToType: make(map[string]types.Type),
+		dynamic:  make(map[string]bool),
 	}
 }
 
@@ -24,7 +26,7 @@ func newTypeIDMapper() *typeIDMapper {
 // This handles type aliases, named types, pointers, etc.
 func (m *typeIDMapper) normalizeType(t types.Type) string {
 	// Use types.TypeString with full package paths for canonical representation
-	return types.TypeString(t, func(p *types.Package) string {
+	return types.TypeString(resolveTypeAlias(t), func(p *types.Package) string {
 		if p == nil {
 			return ""
 		}
@@ -32,6 +34,28 @@ func (m *typeIDMapper) normalizeType(t types.Type) string {
 	})
 }
 
+// resolveTypeAlias follows type aliases to the type they denote, including
+// aliases nested inside pointer, slice, array, map, and channel types.
+// Without this, an alias declared in one package for a type defined in a
+// third package (type Output = thirdparty.Settings) would print as the
+// alias name and fail to match nodes registered with the aliased type
+func resolveTypeAlias(t types.Type) types.Type {
+	switch u := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return types.NewPointer(resolveTypeAlias(u.Elem()))
+	case *types.Slice:
+		return types.NewSlice(resolveTypeAlias(u.Elem()))
+	case *types.Array:
+		return types.NewArray(resolveTypeAlias(u.Elem()), u.Len())
+	case *types.Map:
+		return types.NewMap(resolveTypeAlias(u.Key()), resolveTypeAlias(u.Elem()))
+	case *types.Chan:
+		return types.NewChan(u.Dir(), resolveTypeAlias(u.Elem()))
+	default:
+		return u
+	}
+}
+
 // typeKey returns a unique key for a type
 func (m *typeIDMapper) typeKey(t types.Type) string {
 	return m.normalizeType(t)
@@ -46,6 +70,13 @@ func (m *typeIDMapper) BuildMapping(nodes []NodeDefinition) error {
 
 		key := m.typeKey(node.OutputType)
 
+		// A node without a known ID cannot be mapped; remember its type so
+		// Dep[T] calls on it are recognized rather than ignored
+		if node.Dynamic {
+			m.dynamic[key] = true
+			continue
+		}
+
 		// Check for conflicts: same type registered by multiple nodes
 		if existingID, exists := m.typeToID[key]; exists {
 			if existingID != node.ID {
@@ -78,6 +109,12 @@ func (m *typeIDMapper) ResolveType(t types.Type) (string, error) {
 	return id, nil
 }
 
+// IsDynamic checks if a type is produced by a node whose ID could not be
+// determined statically
+func (m *typeIDMapper) IsDynamic(t types.Type) bool {
+	return m.dynamic[m.typeKey(t)]
+}
+
 // GetType returns the output type for a given node ID
 func (m *typeIDMapper) GetType(nodeID string) (types.Type, error) {
 	t, ok := m.idToType[nodeID]
@@ -338,6 +338,61 @@ func TestTypeIDMapper_NormalizeType(t *testing.T) {
 	}
 }
 
+func TestTypeIDMapper_ResolveTypeAlias(t *testing.T) {
+	// thirdparty.Settings is the defined type; config.Output aliases it
+	thirdPkg := types.NewPackage("example.com/thirdparty", "thirdparty")
+	settings := types.NewNamed(
+		types.NewTypeName(0, thirdPkg, "Settings", nil),
+		types.NewStruct(nil, nil),
+		nil,
+	)
+	configPkg := types.NewPackage("example.com/config", "config")
+	output := types.NewAlias(types.NewTypeName(0, configPkg, "Output", nil), settings)
+
+	tests := map[string]struct {
+		registered types.Type
+		lookup     types.Type
+	}{
+		"alias resolves to third package type": {
+			registered: settings,
+			lookup:     output,
+		},
+		"registered alias matches aliased type": {
+			registered: output,
+			lookup:     settings,
+		},
+		"pointer to alias": {
+			registered: types.NewPointer(settings),
+			lookup:     types.NewPointer(output),
+		},
+		"slice of alias": {
+			registered: types.NewSlice(settings),
+			lookup:     types.NewSlice(output),
+		},
+		"map of alias": {
+			registered: types.NewMap(types.Typ[types.String], settings),
+			lookup:     types.NewMap(types.Typ[types.String], output),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mapper := newTypeIDMapper()
+			if err := mapper.BuildMapping([]NodeDefinition{{ID: "config", OutputType: tt.registered}}); err != nil {
+				t.Fatalf("BuildMapping() error = %v", err)
+			}
+
+			id, err := mapper.ResolveType(tt.lookup)
+			if err != nil {
+				t.Fatalf("ResolveType() error = %v", err)
+			}
+			if id != "config" {
+				t.Errorf("ResolveType() = %q, want %q", id, "config")
+			}
+		})
+	}
+}
+
 func TestTypeIDMapper_TypeKey(t *testing.T) {
 	mapper := newTypeIDMapper()
 
@@ -0,0 +1,130 @@
+package typeaware
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// executionTargets records which nodes the analyzed code executes directly
+type executionTargets struct {
+	ids        map[string]bool // Node IDs requested via ExecuteFor[T] and variants
+	executeAll bool            // True if graft.Execute runs the whole graph
+}
+
+// isGraftExecuteCall checks if a call instruction calls graft.Execute
+func isGraftExecuteCall(call *ssa.Call) bool {
+	callee := call.Common().StaticCallee()
+	if callee == nil {
+		return false
+	}
+	return callee.String() == "github.com/grindlemire/graft.Execute"
+}
+
+// isGraftExecuteForCall checks if a call instruction calls graft.ExecuteFor
+// or one of its multi-output variants, whose type arguments all name targets
+func isGraftExecuteForCall(call *ssa.Call) bool {
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.Origin() == nil {
+		return false
+	}
+	switch callee.Origin().String() {
+	case "github.com/grindlemire/graft.ExecuteFor",
+		"github.com/grindlemire/graft.ExecuteForMultiple",
+		"github.com/grindlemire/graft.ExecuteForMultiple3":
+		return true
+	}
+	return false
+}
+
+// packageFunctions returns every function declared in the given packages,
+// including methods and (recursively) anonymous functions
+func packageFunctions(pkgs []*ssa.Package) []*ssa.Function {
+	var fns []*ssa.Function
+	seen := make(map[*ssa.Function]bool)
+
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		seen[fn] = true
+		fns = append(fns, fn)
+		for _, anon := range fn.AnonFuncs {
+			visit(anon)
+		}
+	}
+
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+		for _, member := range pkg.Members {
+			switch m := member.(type) {
+			case *ssa.Function:
+				visit(m)
+			case *ssa.Type:
+				// Value and pointer receivers; promoted methods are wrappers
+				// that belong to no package and are skipped
+				for _, t := range []types.Type{m.Type(), types.NewPointer(m.Type())} {
+					mset := pkg.Prog.MethodSets.MethodSet(t)
+					for i := 0; i < mset.Len(); i++ {
+						if fn := pkg.Prog.MethodValue(mset.At(i)); fn != nil && fn.Pkg == pkg && !seen[fn] {
+							visit(fn)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return fns
+}
+
+// findExecutionTargets scans the analyzed packages for graft.Execute and
+// graft.ExecuteFor[T] calls, resolving each ExecuteFor type to its node ID
+func findExecutionTargets(pkgs []*ssa.Package, mapper *typeIDMapper) executionTargets {
+	targets := executionTargets{ids: make(map[string]bool)}
+
+	for _, fn := range packageFunctions(pkgs) {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+
+				if isGraftExecuteCall(call) {
+					targets.executeAll = true
+					continue
+				}
+
+				if isGraftExecuteForCall(call) {
+					for _, typeArg := range call.Common().StaticCallee().TypeArgs() {
+						if id, err := mapper.ResolveType(typeArg); err == nil {
+							targets.ids[id] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return targets
+}
+
+// annotateReachability marks leaf nodes (no dependents) and unreachable nodes
+// (leaves that are never executed as a target)
+func annotateReachability(results []Result, targets executionTargets) {
+	hasDependents := make(map[string]bool)
+	for _, r := range results {
+		for _, dep := range r.DeclaredDeps {
+			if dep != r.NodeID {
+				hasDependents[dep] = true
+			}
+		}
+	}
+
+	for i := range results {
+		id := results[i].NodeID
+		results[i].IsLeaf = !hasDependents[id]
+		results[i].IsUnreachable = results[i].IsLeaf && !targets.executeAll && !targets.ids[id]
+	}
+}
@@ -2,6 +2,7 @@ package typeaware
 
 import (
 	"fmt"
+	"go/token"
 	"strings"
 )
 
@@ -16,12 +17,23 @@ type Result struct {
 	// File is the path to the source file containing the node.
 	File string
 
+	// Line is the line of the graft.Register call in File, or 0 if unknown.
+	Line int
+
 	// DeclaredDeps are the dependency IDs listed in the DependsOn field.
 	DeclaredDeps []string
 
 	// UsedDeps are the dependency IDs accessed via Dep[T] calls in Run.
 	UsedDeps []string
 
+	// DeclaredDepPositions maps each declared dependency ID to the position
+	// of the DependsOn element naming it, when known.
+	DeclaredDepPositions map[string]token.Position
+
+	// UsedDepPositions maps each used dependency ID to the position of its
+	// first Dep[T] call, when known.
+	UsedDepPositions map[string]token.Position
+
 	// Undeclared are dependencies used but not declared in DependsOn.
 	// These will cause runtime errors.
 	Undeclared []string
@@ -30,10 +42,47 @@ type Result struct {
 	// These indicate dead code or missing implementation.
 	Unused []string
 
+	// Suppressed are findings silenced by a //graft:ignore directive on the
+	// node's graft.Register call, such as "unused:cache". They are not
+	// included in Undeclared or Unused.
+	Suppressed []string
+
 	// Cycles are circular dependency paths this node participates in.
 	// Each cycle is represented as a path of node IDs forming a loop.
 	// For example: ["svc5", "svc5-2", "svc5"] indicates svc5 → svc5-2 → svc5.
 	Cycles [][]string
+
+	// IsLeaf is true if no other analyzed node declares this node in DependsOn.
+	IsLeaf bool
+
+	// IsUnreachable is true if the node is a leaf and the analyzed code never
+	// executes it: there is no graft.ExecuteFor[T] call for its output type and
+	// no graft.Execute call that runs the whole graph.
+	IsUnreachable bool
+
+	// HasNestedExecution is true if the node's Run function (or a helper it
+	// calls) invokes graft.Execute or graft.ExecuteFor[T], whose dependencies
+	// are hidden from the outer graph.
+	HasNestedExecution bool
+
+	// DepCallsOutsideRun are the functions reached from the node's Run
+	// function that call graft.Dep[T] and are also called from code outside
+	// every node's Run function, where the Dep call fails at runtime.
+	DepCallsOutsideRun []string
+
+	// IsTest is true if the node is registered (or patched) in a _test.go
+	// file. Test files are only analyzed when tests are included.
+	IsTest bool
+
+	// IsOverride is true if the node is a replacement passed to
+	// graft.Patch[T] rather than a registration. Its NodeID is the ID of the
+	// node it replaces, and unused dependencies are not reported since a
+	// mock commonly ignores inputs that the real node needs.
+	IsOverride bool
+
+	// Warnings are non-fatal observations that deserve attention but do not
+	// count as issues (e.g., a node with unusually many dependencies).
+	Warnings []string
 }
 
 // HasIssues returns true if there are undeclared, unused dependencies, or cycles.
@@ -41,6 +90,39 @@ func (r Result) HasIssues() bool {
 	return len(r.Undeclared) > 0 || len(r.Unused) > 0 || len(r.Cycles) > 0
 }
 
+// HasWarnings returns true if the analysis produced any warnings.
+func (r Result) HasWarnings() bool {
+	return len(r.Warnings) > 0
+}
+
+// Diagnostics returns one compiler-style line per undeclared or unused
+// dependency, positioned at the Dep[T] call or DependsOn element involved.
+// When that position is unknown, the node's registration is used instead.
+//
+// Example output:
+//
+//	nodes/db/db.go:42:10: undeclared dep "cache"
+//	nodes/db/db.go:21:25: unused dep "config"
+func (r Result) Diagnostics() []string {
+	var diags []string
+	for _, dep := range r.Undeclared {
+		diags = append(diags, fmt.Sprintf("%s: undeclared dep %q", r.depPosition(r.UsedDepPositions, dep), dep))
+	}
+	for _, dep := range r.Unused {
+		diags = append(diags, fmt.Sprintf("%s: unused dep %q", r.depPosition(r.DeclaredDepPositions, dep), dep))
+	}
+	return diags
+}
+
+// depPosition returns the position of dep in positions, falling back to the
+// node's registration.
+func (r Result) depPosition(positions map[string]token.Position, dep string) token.Position {
+	if pos, ok := positions[dep]; ok {
+		return pos
+	}
+	return token.Position{Filename: r.File, Line: r.Line}
+}
+
 // String returns a human-readable summary of issues.
 //
 // Returns "NodeID: OK" if there are no issues, otherwise returns
@@ -1,6 +1,8 @@
 package typeaware
 
 import (
+	"go/token"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -196,3 +198,48 @@ func TestResult_String_NoIssuesDoesNotContainFile(t *testing.T) {
 		t.Errorf("String() = %q, want %q", got, want)
 	}
 }
+
+func TestResult_Diagnostics(t *testing.T) {
+	type tc struct {
+		result Result
+		want   []string
+	}
+
+	tests := map[string]tc{
+		"no issues": {
+			result: Result{NodeID: "db", File: "db.go", Line: 10},
+		},
+		"positions known": {
+			result: Result{
+				NodeID:               "db",
+				File:                 "db.go",
+				Line:                 10,
+				Undeclared:           []string{"cache"},
+				Unused:               []string{"config"},
+				UsedDepPositions:     map[string]token.Position{"cache": {Filename: "db.go", Line: 42, Column: 10}},
+				DeclaredDepPositions: map[string]token.Position{"config": {Filename: "db.go", Line: 12, Column: 25}},
+			},
+			want: []string{
+				`db.go:42:10: undeclared dep "cache"`,
+				`db.go:12:25: unused dep "config"`,
+			},
+		},
+		"falls back to registration": {
+			result: Result{
+				NodeID:     "db",
+				File:       "db.go",
+				Line:       10,
+				Undeclared: []string{"cache"},
+			},
+			want: []string{`db.go:10: undeclared dep "cache"`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.result.Diagnostics(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diagnostics() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package typeaware
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// FindWorkspaceRoot returns the directory containing the go.work file that
+// applies to dir, searching dir and then its parents. It returns "" if there
+// is none or if workspaces are disabled with GOWORK=off.
+func FindWorkspaceRoot(dir string) (string, error) {
+	if os.Getenv("GOWORK") == "off" {
+		return "", nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(abs, "go.work")); err == nil {
+			return abs, nil
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", nil
+		}
+		abs = parent
+	}
+}
+
+// workspaceModulePaths parses root/go.work and returns the module path of
+// every module it uses, read from each module's go.mod.
+func workspaceModulePaths(root string) ([]string, error) {
+	workFile := filepath.Join(root, "go.work")
+	data, err := os.ReadFile(workFile)
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := modfile.ParseWork(workFile, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", workFile, err)
+	}
+
+	var paths []string
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+
+		modData, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("reading go.mod for %s: %w", use.Path, err)
+		}
+		modPath := modfile.ModulePath(modData)
+		if modPath == "" {
+			return nil, fmt.Errorf("%s/go.mod has no module directive", use.Path)
+		}
+		paths = append(paths, modPath)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s uses no modules", workFile)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,124 @@
+package typeaware
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func TestFindWorkspaceRoot(t *testing.T) {
+	t.Setenv("GOWORK", "")
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"ws/go.work":           "go 1.25\n\nuse ./svc\n",
+		"ws/svc/go.mod":        "module svc\n",
+		"ws/svc/nodes/keep.go": "package nodes\n",
+		"plain/go.mod":         "module plain\n",
+	})
+
+	tests := map[string]struct {
+		dir  string
+		want string
+	}{
+		"workspace root":    {dir: "ws", want: "ws"},
+		"nested directory":  {dir: "ws/svc/nodes", want: "ws"},
+		"outside workspace": {dir: "plain", want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FindWorkspaceRoot(filepath.Join(root, tt.dir))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(root, tt.want)
+			}
+			if got != want {
+				t.Errorf("FindWorkspaceRoot() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFindWorkspaceRootGoworkOff(t *testing.T) {
+	t.Setenv("GOWORK", "off")
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"go.work": "go 1.25\n"})
+
+	got, err := FindWorkspaceRoot(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("FindWorkspaceRoot() = %q, want empty with GOWORK=off", got)
+	}
+}
+
+func TestWorkspaceModulePaths(t *testing.T) {
+	tests := map[string]struct {
+		files   map[string]string
+		want    []string
+		wantErr string
+	}{
+		"multiple modules": {
+			files: map[string]string{
+				"go.work":           "go 1.25\n\nuse (\n\t./api\n\t./lib/shared\n)\n",
+				"api/go.mod":        "module example.com/api\n",
+				"lib/shared/go.mod": "module example.com/shared\n",
+			},
+			want: []string{"example.com/api", "example.com/shared"},
+		},
+		"missing go.mod": {
+			files: map[string]string{
+				"go.work": "go 1.25\n\nuse ./missing\n",
+			},
+			wantErr: "reading go.mod for ./missing",
+		},
+		"no modules": {
+			files: map[string]string{
+				"go.work": "go 1.25\n",
+			},
+			wantErr: "uses no modules",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+
+			got, err := workspaceModulePaths(root)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package k8s provides HTTP handlers for Kubernetes readiness and liveness
+// probes backed by the graft registry and the default cache.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/readyz", k8s.ReadinessHandler())
+//	mux.Handle("/livez", k8s.LivenessHandler())
+//
+//	go graft.WarmCache(ctx)
+//	http.ListenAndServe(":8080", mux)
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grindlemire/graft"
+)
+
+// Probe status values reported in [Status].
+const (
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+	StatusAlive    = "alive"
+)
+
+// NodeStatus is the probe status of a single cacheable node.
+type NodeStatus struct {
+	ID     graft.ID `json:"id"`
+	Cached bool     `json:"cached"`
+}
+
+// Status is the JSON body written by both probe handlers.
+type Status struct {
+	Status string       `json:"status"`
+	Nodes  []NodeStatus `json:"nodes"`
+}
+
+// ReadinessHandler returns a handler that responds 200 once every cacheable
+// node in the registry has an entry in [graft.DefaultCache], and 503
+// otherwise. Pass [graft.WithRegistry] to check a custom registry.
+//
+// Example response:
+//
+//	{"status":"not ready","nodes":[{"id":"config","cached":true},{"id":"db","cached":false}]}
+func ReadinessHandler(opts ...graft.Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := nodeStatus(r.Context(), opts)
+		status.Status = StatusReady
+		code := http.StatusOK
+		for _, n := range status.Nodes {
+			if !n.Cached {
+				status.Status = StatusNotReady
+				code = http.StatusServiceUnavailable
+				break
+			}
+		}
+		writeStatus(w, code, status)
+	})
+}
+
+// LivenessHandler returns a handler that always responds 200 while the
+// process is running. The body reports the same per-node cache status as
+// [ReadinessHandler] for debugging.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := nodeStatus(r.Context(), nil)
+		status.Status = StatusAlive
+		writeStatus(w, http.StatusOK, status)
+	})
+}
+
+// nodeStatus reports whether each cacheable node has a cached output. A
+// cache error is reported as not cached.
+func nodeStatus(ctx context.Context, opts []graft.Option) Status {
+	status := Status{Nodes: []NodeStatus{}}
+	for _, n := range graft.ListNodes(opts...) {
+		if !n.Cacheable {
+			continue
+		}
+		ok, err := graft.CacheHas(ctx, graft.DefaultCache(), n.ID)
+		ok = ok && err == nil
+		status.Nodes = append(status.Nodes, NodeStatus{ID: n.ID, Cached: ok})
+	}
+	return status
+}
+
+// writeStatus writes status as JSON with the given HTTP status code.
+func writeStatus(w http.ResponseWriter, code int, status Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type configOutput struct{ Env string }
+type dbOutput struct{ DSN string }
+type requestOutput struct{}
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+	t.Cleanup(graft.ResetDefaultCache)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:        "config",
+		Cacheable: true,
+		Run:       func(ctx context.Context) (configOutput, error) { return configOutput{Env: "test"}, nil },
+	})
+	graft.Register(graft.Node[dbOutput]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Cacheable: true,
+		Run:       func(ctx context.Context) (dbOutput, error) { return dbOutput{DSN: "postgres://"}, nil },
+	})
+	graft.Register(graft.Node[requestOutput]{
+		ID:  "request",
+		Run: func(ctx context.Context) (requestOutput, error) { return requestOutput{}, nil },
+	})
+}
+
+func serve(t *testing.T, h http.Handler) (int, Status) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	return rec.Code, status
+}
+
+func TestReadinessHandler(t *testing.T) {
+	type tc struct {
+		warm       func(t *testing.T)
+		wantCode   int
+		wantStatus Status
+	}
+
+	tests := map[string]tc{
+		"nothing cached": {
+			warm:     func(t *testing.T) {},
+			wantCode: http.StatusServiceUnavailable,
+			wantStatus: Status{Status: StatusNotReady, Nodes: []NodeStatus{
+				{ID: "config", Cached: false},
+				{ID: "db", Cached: false},
+			}},
+		},
+		"partially cached": {
+			warm: func(t *testing.T) {
+				if _, _, err := graft.ExecuteFor[configOutput](context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantStatus: Status{Status: StatusNotReady, Nodes: []NodeStatus{
+				{ID: "config", Cached: true},
+				{ID: "db", Cached: false},
+			}},
+		},
+		"all cacheable nodes cached": {
+			warm: func(t *testing.T) {
+				if err := graft.WarmCache(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCode: http.StatusOK,
+			wantStatus: Status{Status: StatusReady, Nodes: []NodeStatus{
+				{ID: "config", Cached: true},
+				{ID: "db", Cached: true},
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+			tt.warm(t)
+
+			code, status := serve(t, ReadinessHandler())
+			if code != tt.wantCode {
+				t.Errorf("code = %d, want %d", code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(status, tt.wantStatus) {
+				t.Errorf("status = %+v, want %+v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestReadinessHandlerNoCacheableNodes(t *testing.T) {
+	registerNodes(t)
+
+	code, status := serve(t, ReadinessHandler(graft.WithRegistry(nil)))
+	if code != http.StatusOK {
+		t.Errorf("code = %d, want %d", code, http.StatusOK)
+	}
+	if status.Status != StatusReady || len(status.Nodes) != 0 {
+		t.Errorf("status = %+v, want ready with no nodes", status)
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	registerNodes(t)
+
+	code, status := serve(t, LivenessHandler())
+	if code != http.StatusOK {
+		t.Errorf("code = %d, want %d", code, http.StatusOK)
+	}
+	if status.Status != StatusAlive {
+		t.Errorf("status = %q, want %q", status.Status, StatusAlive)
+	}
+	if len(status.Nodes) != 2 {
+		t.Errorf("expected 2 cacheable nodes, got %+v", status.Nodes)
+	}
+}
@@ -0,0 +1,70 @@
+package graft
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metric names emitted by the engine when a [MetricsSink] is configured.
+const (
+	// MetricNodeExecutions counts node resolutions, labeled with "node",
+	// "cache_hit" ("true"/"false"), and "status" ("success"/"error").
+	MetricNodeExecutions = "graft_node_executions_total"
+
+	// MetricNodeDuration records how long each node's Run function took,
+	// labeled with "node". Cache hits are not timed.
+	MetricNodeDuration = "graft_node_duration_seconds"
+)
+
+// MetricsSink receives execution metrics from the engine. Implementations
+// adapt graft's metrics to a metrics backend such as Prometheus or StatsD.
+// The github.com/grindlemire/graft/prometheus module provides a Prometheus
+// implementation.
+//
+// Methods are called concurrently from node goroutines and must be safe
+// for concurrent use.
+type MetricsSink interface {
+	// IncrCounter increments the named counter by one.
+	IncrCounter(name string, labels map[string]string)
+
+	// RecordDuration records an observation for the named timing metric.
+	RecordDuration(name string, d time.Duration, labels map[string]string)
+}
+
+// WithMetrics reports per-node execution counts and durations to sink.
+// See [MetricNodeExecutions] and [MetricNodeDuration] for the emitted metrics.
+//
+// Example:
+//
+//	out, _, err := graft.ExecuteFor[app.Output](ctx, graft.WithMetrics(sink))
+func WithMetrics(sink MetricsSink) Option {
+	return func(c *config) {
+		c.metrics = sink
+	}
+}
+
+// recordExecution reports a node resolution to the metrics sink, if any.
+func (e *engine) recordExecution(id ID, cacheHit bool, err error) {
+	if e.metrics == nil {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	e.metrics.IncrCounter(MetricNodeExecutions, map[string]string{
+		"node":      string(id),
+		"cache_hit": strconv.FormatBool(cacheHit),
+		"status":    status,
+	})
+}
+
+// recordDuration reports a node's Run duration to the metrics sink, if any.
+func (e *engine) recordDuration(id ID, d time.Duration) {
+	if e.metrics == nil {
+		return
+	}
+	e.metrics.RecordDuration(MetricNodeDuration, d, map[string]string{
+		"node": string(id),
+	})
+}
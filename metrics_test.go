@@ -0,0 +1,106 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink is a MetricsSink that records every emitted metric.
+type recordingSink struct {
+	mu        sync.Mutex
+	counters  []string
+	durations []string
+}
+
+func (s *recordingSink) IncrCounter(name string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters = append(s.counters, name+" node="+labels["node"]+" cache_hit="+labels["cache_hit"]+" status="+labels["status"])
+}
+
+func (s *recordingSink) RecordDuration(name string, d time.Duration, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations = append(s.durations, name+" node="+labels["node"])
+}
+
+func TestWithMetrics(t *testing.T) {
+	type tc struct {
+		nodes         map[ID]node
+		cached        map[ID]any
+		wantErr       bool
+		wantCounters  []string
+		wantDurations []string
+	}
+
+	tests := map[string]tc{
+		"successful execution": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return 1, nil }),
+				"b": makeNode("b", []ID{"a"}, func(ctx context.Context) (any, error) { return 2, nil }),
+			},
+			wantCounters: []string{
+				"graft_node_executions_total node=a cache_hit=false status=success",
+				"graft_node_executions_total node=b cache_hit=false status=success",
+			},
+			wantDurations: []string{
+				"graft_node_duration_seconds node=a",
+				"graft_node_duration_seconds node=b",
+			},
+		},
+		"failed execution": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+			},
+			wantErr: true,
+			wantCounters: []string{
+				"graft_node_executions_total node=a cache_hit=false status=error",
+			},
+			wantDurations: []string{
+				"graft_node_duration_seconds node=a",
+			},
+		},
+		"cache hit is not timed": {
+			nodes: map[ID]node{
+				"a": {id: "a", cacheable: true, run: func(ctx context.Context) (any, error) { return 1, nil }},
+			},
+			cached: map[ID]any{"a": 1},
+			wantCounters: []string{
+				"graft_node_executions_total node=a cache_hit=true status=success",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := NewMemoryCache()
+			for id, v := range tt.cached {
+				_ = cache.Set(context.Background(), id, v)
+			}
+			sink := &recordingSink{}
+
+			_, err := Execute(context.Background(),
+				WithRegistry(tt.nodes),
+				WithCache(cache),
+				WithMetrics(sink),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			sort.Strings(sink.counters)
+			sort.Strings(sink.durations)
+			if !reflect.DeepEqual(sink.counters, tt.wantCounters) {
+				t.Errorf("counters = %v, want %v", sink.counters, tt.wantCounters)
+			}
+			if !reflect.DeepEqual(sink.durations, tt.wantDurations) {
+				t.Errorf("durations = %v, want %v", sink.durations, tt.wantDurations)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package mock builds configurable mock nodes for graft tests.
+//
+// Example:
+//
+//	func TestApp(t *testing.T) {
+//	    db := mock.AutoMock[db.Output](t).Return(db.Output{Pool: fakePool})
+//
+//	    out, _, err := graft.ExecuteFor[app.Output](ctx, graft.Patch[db.Output](db.Node()))
+//	    ...
+//	    if db.CallCount() != 1 {
+//	        t.Errorf("db ran %d times", db.CallCount())
+//	    }
+//	}
+package mock
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+// Mock is a mock node producing T. Configure it with [Mock.Return],
+// [Mock.ReturnError] or [Mock.OnCall] and install it with [Mock.Node]. Its
+// methods are safe for concurrent use.
+type Mock[T any] struct {
+	mu     sync.Mutex
+	value  T
+	err    error
+	fn     func(ctx context.Context) (T, error)
+	called int
+}
+
+// AutoMock returns a mock that returns the zero value of T. The mock is
+// reset when the test and its subtests complete.
+//
+// Example:
+//
+//	m := mock.AutoMock[config.Output](t)
+func AutoMock[T any](t testing.TB) *Mock[T] {
+	t.Helper()
+	m := &Mock[T]{}
+	t.Cleanup(m.reset)
+	return m
+}
+
+// Return makes the node return value with no error.
+//
+// Example:
+//
+//	m.Return(config.Output{Port: 8080})
+func (m *Mock[T]) Return(value T) *Mock[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.value, m.err, m.fn = value, nil, nil
+	return m
+}
+
+// ReturnError makes the node fail with err.
+//
+// Example:
+//
+//	m.ReturnError(errors.New("connection refused"))
+func (m *Mock[T]) ReturnError(err error) *Mock[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero T
+	m.value, m.err, m.fn = zero, err, nil
+	return m
+}
+
+// OnCall makes the node delegate to fn, which may call graft.Dep for the
+// dependencies of the node it replaces.
+//
+// Example:
+//
+//	m.OnCall(func(ctx context.Context) (db.Output, error) {
+//	    cfg, err := graft.Dep[config.Output](ctx)
+//	    return db.Output{DSN: cfg.DSN}, err
+//	})
+func (m *Mock[T]) OnCall(fn func(ctx context.Context) (T, error)) *Mock[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fn = fn
+	return m
+}
+
+// CallCount returns how many times the node has run.
+func (m *Mock[T]) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.called
+}
+
+// Node returns a graft node running the mock, for use with graft.Patch.
+// Patch takes the node's ID from T, so the returned node has none.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.Patch[db.Output](m.Node()))
+func (m *Mock[T]) Node() graft.Node[T] {
+	return graft.Node[T]{Run: m.run}
+}
+
+// run records the call and produces the configured output.
+func (m *Mock[T]) run(ctx context.Context) (T, error) {
+	m.mu.Lock()
+	m.called++
+	value, err, fn := m.value, m.err, m.fn
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx)
+	}
+	return value, err
+}
+
+// reset restores the zero-value behavior and clears the call count.
+func (m *Mock[T]) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero T
+	m.value, m.err, m.fn, m.called = zero, nil, nil, 0
+}
@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type configOutput struct{ Port int }
+type appOutput struct{ Port int }
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{Port: 80}, nil },
+	})
+	graft.Register(graft.Node[appOutput]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (appOutput, error) {
+			cfg, err := graft.Dep[configOutput](ctx)
+			return appOutput{Port: cfg.Port}, err
+		},
+	})
+}
+
+func TestMock(t *testing.T) {
+	type tc struct {
+		configure func(m *Mock[configOutput])
+		wantPort  int
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"zero value by default": {
+			configure: func(m *Mock[configOutput]) {},
+			wantPort:  0,
+		},
+		"return": {
+			configure: func(m *Mock[configOutput]) { m.Return(configOutput{Port: 8080}) },
+			wantPort:  8080,
+		},
+		"return error": {
+			configure: func(m *Mock[configOutput]) { m.ReturnError(errors.New("unavailable")) },
+			errSubstr: "node config: unavailable",
+		},
+		"on call": {
+			configure: func(m *Mock[configOutput]) {
+				m.OnCall(func(ctx context.Context) (configOutput, error) { return configOutput{Port: 9090}, nil })
+			},
+			wantPort: 9090,
+		},
+		"last setting wins": {
+			configure: func(m *Mock[configOutput]) {
+				m.ReturnError(errors.New("unavailable")).Return(configOutput{Port: 1})
+			},
+			wantPort: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t)
+
+			m := AutoMock[configOutput](t)
+			tt.configure(m)
+
+			out, _, err := graft.ExecuteFor[appOutput](context.Background(),
+				graft.DisableCache(), graft.Patch[configOutput](m.Node()))
+			if m.CallCount() != 1 {
+				t.Errorf("CallCount() = %d, want 1", m.CallCount())
+			}
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Port != tt.wantPort {
+				t.Errorf("app port = %d, want %d", out.Port, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestMockResetOnCleanup(t *testing.T) {
+	var m *Mock[configOutput]
+	t.Run("configure", func(t *testing.T) {
+		m = AutoMock[configOutput](t).Return(configOutput{Port: 8080})
+		if _, err := m.Node().Run(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if m.CallCount() != 0 {
+		t.Errorf("CallCount() after cleanup = %d, want 0", m.CallCount())
+	}
+	out, err := m.Node().Run(context.Background())
+	if err != nil || out.Port != 0 {
+		t.Errorf("Run() after cleanup = %+v, %v; want zero value", out, err)
+	}
+}
@@ -0,0 +1,138 @@
+// Package nats publishes graft node outputs to NATS subjects as nodes
+// complete, and subscribes to them on the consuming side.
+//
+// Each output is JSON-encoded and published to the subject
+// "<prefix>.<node ID>", so consumers can follow a single node or, with a
+// wildcard such as "graft.>", every node of a graph.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/grindlemire/graft"
+	"github.com/nats-io/nats.go"
+)
+
+// PublishOption configures [WithPublisher].
+type PublishOption func(*publishOptions)
+
+type publishOptions struct {
+	onError func(id graft.ID, err error)
+}
+
+// OnPublishError sets a callback for outputs that could not be encoded or
+// published. By default such errors are ignored, since they cannot fail a
+// node that has already succeeded.
+//
+// Example:
+//
+//	graftnats.WithPublisher(conn, "orders", graftnats.OnPublishError(func(id graft.ID, err error) {
+//	    log.Printf("publish %s: %v", id, err)
+//	}))
+func OnPublishError(fn func(id graft.ID, err error)) PublishOption {
+	return func(o *publishOptions) {
+		o.onError = fn
+	}
+}
+
+// WithPublisher publishes each node's output to subjectPrefix + "." + the
+// node ID as soon as the node succeeds, including outputs served from the
+// cache (see [graft.OnNodeResult]). Outputs are encoded with encoding/json.
+//
+// Publishing only hands the message to conn's outgoing buffer; call
+// conn.Flush after the execution to wait for the server to receive it.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graftnats.WithPublisher(conn, "orders"))
+func WithPublisher(conn *nats.Conn, subjectPrefix string, opts ...PublishOption) graft.Option {
+	o := &publishOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return graft.OnNodeResult(func(id graft.ID, output any) {
+		data, err := json.Marshal(output)
+		if err == nil {
+			err = conn.Publish(subject(subjectPrefix, id), data)
+		}
+		if err != nil && o.onError != nil {
+			o.onError(id, err)
+		}
+	})
+}
+
+// Subscribe returns a channel receiving the outputs of the node producing T
+// that a [WithPublisher] with the same subjectPrefix publishes. The node is
+// looked up by output type in the global registry. Messages that do not
+// decode into a T are skipped.
+//
+// The subscription ends and the channel is closed when ctx is done. Up to
+// 64 messages are buffered while the receiver is busy; beyond that NATS
+// drops messages and reports a slow consumer.
+//
+// Example:
+//
+//	orders, err := graftnats.Subscribe[order.Output](ctx, conn, "orders")
+//	if err != nil {
+//	    return err
+//	}
+//	for o := range orders {
+//	    ...
+//	}
+func Subscribe[T any](ctx context.Context, conn *nats.Conn, subjectPrefix string) (<-chan T, error) {
+	id, err := nodeFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make(chan *nats.Msg, 64)
+	sub, err := conn.ChanSubscribe(subject(subjectPrefix, id), msgs)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %s: %w", id, err)
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-msgs:
+				var v T
+				if err := json.Unmarshal(msg.Data, &v); err != nil {
+					continue
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// nodeFor returns the ID of the registered node whose output type is typ.
+func nodeFor(typ reflect.Type) (graft.ID, error) {
+	for _, n := range graft.ListNodes() {
+		if t, err := graft.NodeOutputType(n.ID); err == nil && t == typ {
+			return n.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no registered node produces %s", typ)
+}
+
+// subject returns the subject a node's outputs are published to.
+func subject(prefix string, id graft.ID) string {
+	if prefix == "" {
+		return string(id)
+	}
+	return prefix + "." + string(id)
+}
@@ -0,0 +1,292 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/graft"
+	"github.com/nats-io/nats.go"
+)
+
+type config struct {
+	Port int `json:"port"`
+}
+
+type report struct {
+	Summary string `json:"summary"`
+}
+
+// fakeServer speaks enough of the NATS protocol for a single client to
+// publish to and subscribe on exact subjects.
+type fakeServer struct {
+	ln net.Listener
+}
+
+func startFakeServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return "nats://" + ln.Addr().String()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	var mu sync.Mutex
+	write := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(conn, format, args...)
+	}
+
+	write("INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+	subs := map[string]string{} // subject -> sid
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			write("PONG\r\n")
+		case "SUB":
+			subs[fields[1]] = fields[len(fields)-1]
+		case "UNSUB":
+			for subject, sid := range subs {
+				if sid == fields[1] {
+					delete(subs, subject)
+				}
+			}
+		case "PUB":
+			n, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, n+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			if sid, ok := subs[fields[1]]; ok {
+				write("MSG %s %s %d\r\n%s\r\n", fields[1], sid, n, payload[:n])
+			}
+		}
+	}
+}
+
+func connect(t *testing.T) *nats.Conn {
+	t.Helper()
+	conn, err := nats.Connect(startFakeServer(t))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+	t.Cleanup(graft.ResetDefaultCache)
+
+	graft.Register(graft.Node[config]{
+		ID:        "config",
+		Cacheable: true,
+		Run: func(ctx context.Context) (config, error) {
+			return config{Port: 8080}, nil
+		},
+	})
+	graft.Register(graft.Node[report]{
+		ID:        "report",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (report, error) {
+			cfg, err := graft.Dep[config](ctx)
+			if err != nil {
+				return report{}, err
+			}
+			return report{Summary: fmt.Sprintf("port %d", cfg.Port)}, nil
+		},
+	})
+}
+
+func TestPublishAndSubscribe(t *testing.T) {
+	registerNodes(t)
+	conn := connect(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configs, err := Subscribe[config](ctx, conn, "app")
+	if err != nil {
+		t.Fatalf("Subscribe[config]: %v", err)
+	}
+	reports, err := Subscribe[report](ctx, conn, "app")
+	if err != nil {
+		t.Fatalf("Subscribe[report]: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second run serves config from the cache; it is published again
+	for run := 0; run < 2; run++ {
+		if _, err := graft.Execute(context.Background(), WithPublisher(conn, "app")); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if err := conn.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case got := <-configs:
+			if got.Port != 8080 {
+				t.Errorf("run %d: config = %+v, want port 8080", run, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d: no config received", run)
+		}
+		select {
+		case got := <-reports:
+			if got.Summary != "port 8080" {
+				t.Errorf("run %d: report = %+v, want summary %q", run, got, "port 8080")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d: no report received", run)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-configs:
+		if ok {
+			t.Error("channel should be closed after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestSubscribeSkipsUndecodable(t *testing.T) {
+	registerNodes(t)
+	conn := connect(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configs, err := Subscribe[config](ctx, conn, "")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.Publish("config", []byte("not json"))
+	conn.Publish("config", []byte(`{"port":1}`))
+	select {
+	case got := <-configs:
+		if got.Port != 1 {
+			t.Errorf("config = %+v, want port 1", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no config received")
+	}
+}
+
+func TestSubscribeUnknownType(t *testing.T) {
+	registerNodes(t)
+	conn := connect(t)
+
+	_, err := Subscribe[string](context.Background(), conn, "app")
+	if err == nil || err.Error() != "no registered node produces string" {
+		t.Fatalf("error = %v, want %q", err, "no registered node produces string")
+	}
+}
+
+func TestOnPublishError(t *testing.T) {
+	type tc struct {
+		register func(t *testing.T)
+		closed   bool
+		isErr    func(error) bool
+	}
+
+	tests := map[string]tc{
+		"unencodable output": {
+			register: func(t *testing.T) {
+				graft.Register(graft.Node[chan int]{
+					ID:  "events",
+					Run: func(ctx context.Context) (chan int, error) { return make(chan int), nil },
+				})
+			},
+			isErr: func(err error) bool {
+				var typeErr *json.UnsupportedTypeError
+				return errors.As(err, &typeErr)
+			},
+		},
+		"closed connection": {
+			register: registerNodes,
+			closed:   true,
+			isErr:    func(err error) bool { return errors.Is(err, nats.ErrConnectionClosed) },
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			graft.ResetRegistry()
+			graft.ResetDefaultCache()
+			t.Cleanup(graft.ResetRegistry)
+			t.Cleanup(graft.ResetDefaultCache)
+			tt.register(t)
+
+			conn := connect(t)
+			if tt.closed {
+				conn.Close()
+			}
+
+			var mu sync.Mutex
+			failed := map[graft.ID]error{}
+			opt := WithPublisher(conn, "app", OnPublishError(func(id graft.ID, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				failed[id] = err
+			}))
+			if _, err := graft.Execute(context.Background(), opt); err != nil {
+				t.Fatalf("publish errors should not fail the execution: %v", err)
+			}
+
+			if len(failed) == 0 {
+				t.Fatal("OnPublishError not called")
+			}
+			for id, err := range failed {
+				if !tt.isErr(err) {
+					t.Errorf("node %s: unexpected error %v", id, err)
+				}
+			}
+		})
+	}
+}
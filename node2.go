@@ -0,0 +1,166 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Node2 is a node whose Run function produces two outputs that belong
+// together, such as the body and headers of a single HTTP call. Each
+// output is addressable on its own: T under ID and U under SecondID, so
+// dependents declare whichever they need and read it with Dep[T], Dep[U],
+// or both at once with [Dep2].
+//
+// Register it with [Register2]. Run is called once per execution and both
+// outputs come from that call.
+//
+// Example:
+//
+//	graft.Register2(graft.Node2[catalog.Items, catalog.Headers]{
+//	    ID:        "catalog.items",
+//	    SecondID:  "catalog.headers",
+//	    DependsOn: []graft.ID{"config"},
+//	    Run: func(ctx context.Context) (catalog.Items, catalog.Headers, error) {
+//	        return fetchCatalog(ctx)
+//	    },
+//	})
+type Node2[T, U any] struct {
+	// ID identifies the first output, T.
+	ID ID
+
+	// SecondID identifies the second output, U.
+	SecondID ID
+
+	// DependsOn lists the IDs of nodes that must complete before Run.
+	DependsOn []ID
+
+	// Run executes the node and returns both outputs.
+	Run func(ctx context.Context) (T, U, error)
+
+	// Cacheable indicates whether the pair of outputs should be cached.
+	Cacheable bool
+
+	// Description is an optional human-readable summary of what the node
+	// produces.
+	Description string
+
+	// Tags are optional free-form labels used to group nodes.
+	Tags []string
+}
+
+// outputPair holds both outputs of a Node2 run.
+type outputPair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// pairID returns the ID of the internal node that runs a Node2 and
+// produces both of its outputs.
+func pairID(first, second ID) ID {
+	return first + "+" + second
+}
+
+// Register2 adds a [Node2] to the global registry. Internally it registers
+// a node with ID "<ID>+<SecondID>" that calls Run, plus one node per output
+// that depends on it, so introspection helpers such as ListNodes and
+// PrintGraph show all three.
+//
+// Panics if either ID is empty, if either ID is already registered, or if
+// T and U are the same type (Dep[T] could not tell the outputs apart).
+//
+// Example:
+//
+//	func init() {
+//	    graft.Register2(graft.Node2[Items, Headers]{
+//	        ID:       "items",
+//	        SecondID: "headers",
+//	        Run:      fetchCatalog,
+//	    })
+//	}
+func Register2[T, U any](n Node2[T, U]) {
+	if n.ID == "" || n.SecondID == "" {
+		panic("graft: Node2 registered with empty ID")
+	}
+	if reflect.TypeOf((*T)(nil)) == reflect.TypeOf((*U)(nil)) {
+		panic(fmt.Sprintf("graft: Node2 %s has two outputs of the same type %s", n.ID, typeName[T]()))
+	}
+	source := pairID(n.ID, n.SecondID)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, id := range []ID{n.ID, n.SecondID, source} {
+		if _, exists := registry[id]; exists {
+			panic("graft: duplicate node registration: " + string(id))
+		}
+	}
+
+	registry[source] = node{
+		id:        source,
+		dependsOn: n.DependsOn,
+		run: func(ctx context.Context) (any, error) {
+			t, u, err := n.Run(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return outputPair[T, U]{First: t, Second: u}, nil
+		},
+		cacheable:   n.Cacheable,
+		description: n.Description,
+		tags:        n.Tags,
+		outputType:  reflect.TypeOf(outputPair[T, U]{}),
+	}
+	registry[n.ID] = node{
+		id:          n.ID,
+		dependsOn:   []ID{source},
+		run:         projectPair[T, U](source, func(p outputPair[T, U]) any { return p.First }),
+		description: n.Description,
+		tags:        n.Tags,
+		outputType:  reflect.TypeOf((*T)(nil)).Elem(),
+	}
+	registry[n.SecondID] = node{
+		id:          n.SecondID,
+		dependsOn:   []ID{source},
+		run:         projectPair[T, U](source, func(p outputPair[T, U]) any { return p.Second }),
+		description: n.Description,
+		tags:        n.Tags,
+		outputType:  reflect.TypeOf((*U)(nil)).Elem(),
+	}
+
+	typeToID[(*T)(nil)] = n.ID
+	typeToID[(*U)(nil)] = n.SecondID
+}
+
+// projectPair returns the run function of a Node2 output node, which picks
+// one output from the pair produced by the source node.
+func projectPair[T, U any](source ID, pick func(outputPair[T, U]) any) func(ctx context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		p, err := depByID[outputPair[T, U]](ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		return pick(p), nil
+	}
+}
+
+// Dep2 retrieves both outputs of a [Node2] from the context. The calling
+// node must declare both IDs in DependsOn. Since both outputs come from a
+// single Run call, they are always consistent with each other.
+//
+// Example:
+//
+//	items, headers, err := graft.Dep2[catalog.Items, catalog.Headers](ctx)
+func Dep2[T, U any](ctx context.Context) (T, U, error) {
+	var zeroT T
+	var zeroU U
+
+	t, err := Dep[T](ctx)
+	if err != nil {
+		return zeroT, zeroU, err
+	}
+	u, err := Dep[U](ctx)
+	if err != nil {
+		return zeroT, zeroU, err
+	}
+	return t, u, nil
+}
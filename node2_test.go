@@ -0,0 +1,126 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type node2Body struct{ Items []string }
+type node2Headers struct{ ETag string }
+type node2Summary struct{ Text string }
+
+func TestRegister2(t *testing.T) {
+	type tc struct {
+		runErr  error
+		wantErr string
+		want    node2Summary
+	}
+
+	tests := map[string]tc{
+		"both outputs from one run": {
+			want: node2Summary{Text: "2 items etag=abc"},
+		},
+		"run error fails the execution": {
+			runErr:  errors.New("upstream down"),
+			wantErr: "upstream down",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ResetRegistry()
+			t.Cleanup(ResetRegistry)
+
+			var calls atomic.Int32
+			Register2(Node2[node2Body, node2Headers]{
+				ID:       "body",
+				SecondID: "headers",
+				Run: func(ctx context.Context) (node2Body, node2Headers, error) {
+					calls.Add(1)
+					return node2Body{Items: []string{"a", "b"}}, node2Headers{ETag: "abc"}, tt.runErr
+				},
+			})
+			Register(Node[node2Summary]{
+				ID:        "summary",
+				DependsOn: []ID{"body", "headers"},
+				Run: func(ctx context.Context) (node2Summary, error) {
+					body, headers, err := Dep2[node2Body, node2Headers](ctx)
+					if err != nil {
+						return node2Summary{}, err
+					}
+					return node2Summary{Text: fmt.Sprintf("%d items etag=%s", len(body.Items), headers.ETag)}, nil
+				},
+			})
+
+			got, results, err := ExecuteFor[node2Summary](context.Background(), DisableCache())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if calls.Load() != 1 {
+				t.Errorf("Run called %d times, want 1", calls.Load())
+			}
+			if headers, err := Result[node2Headers](results); err != nil || headers.ETag != "abc" {
+				t.Errorf("Result[node2Headers] = %+v, %v", headers, err)
+			}
+		})
+	}
+}
+
+func TestRegister2Panics(t *testing.T) {
+	type tc struct {
+		register func()
+		want     string
+	}
+
+	run := func(ctx context.Context) (node2Body, node2Headers, error) {
+		return node2Body{}, node2Headers{}, nil
+	}
+
+	tests := map[string]tc{
+		"empty second ID": {
+			register: func() { Register2(Node2[node2Body, node2Headers]{ID: "body", Run: run}) },
+			want:     "empty ID",
+		},
+		"same output types": {
+			register: func() {
+				Register2(Node2[node2Body, node2Body]{ID: "a", SecondID: "b"})
+			},
+			want: "same type",
+		},
+		"duplicate ID": {
+			register: func() {
+				Register(Node[node2Summary]{ID: "headers"})
+				Register2(Node2[node2Body, node2Headers]{ID: "body", SecondID: "headers", Run: run})
+			},
+			want: "duplicate node registration: headers",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ResetRegistry()
+			t.Cleanup(ResetRegistry)
+
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(r.(string), tt.want) {
+					t.Errorf("panic = %v, want one containing %q", r, tt.want)
+				}
+			}()
+			tt.register()
+		})
+	}
+}
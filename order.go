@@ -0,0 +1,90 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WithNodeOrder runs the nodes of each level one at a time instead of
+// concurrently. Within a level, nodes listed in order run first in the
+// listed order; the rest follow sorted by ID. Dependencies still decide the
+// levels, so order only breaks ties between independent nodes.
+//
+// Execution fails before any node runs if order names a node that is not in
+// the registry. Listed nodes outside the subgraph of ExecuteFor are
+// ignored. Use it for deterministic debugging or to replay the interleaving
+// of a previous execution.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.WithNodeOrder([]graft.ID{"db", "cache"}))
+func WithNodeOrder(order []ID) Option {
+	return func(c *config) {
+		c.nodeOrder = append([]ID{}, order...)
+	}
+}
+
+// nodeOrder is the sequential schedule requested with WithNodeOrder.
+type nodeOrder struct {
+	rank map[ID]int // position of each listed node
+	err  error      // set if a listed node is not registered
+}
+
+// newNodeOrder ranks the IDs in order, checking them against registry.
+func newNodeOrder(registry map[ID]node, order []ID) *nodeOrder {
+	o := &nodeOrder{rank: make(map[ID]int, len(order))}
+	for i, id := range order {
+		if _, ok := registry[id]; !ok {
+			o.err = fmt.Errorf("node order: unknown node: %s", id)
+			return o
+		}
+		if _, dup := o.rank[id]; !dup {
+			o.rank[id] = i
+		}
+	}
+	return o
+}
+
+// sorted returns level in execution order: ranked nodes first by rank, then
+// the rest by ID.
+func (o *nodeOrder) sorted(level []ID) []ID {
+	out := append([]ID{}, level...)
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, iok := o.rank[out[i]]
+		rj, jok := o.rank[out[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return out[i] < out[j]
+		}
+	})
+	return out
+}
+
+// runLevelInOrder runs the nodes of a level one after another, stopping at
+// the first error.
+func (e *engine) runLevelInOrder(ctx context.Context, levelIdx int, level []ID) error {
+	prefetched, err := e.prefetchLevel(ctx, level)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range e.nodeOrder.sorted(level) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		e.nodeStarted(id, levelIdx)
+		start := time.Now()
+		err := e.runNodeRecovered(ctx, id, prefetched)
+		e.nodeCompleted(id, levelIdx, time.Since(start), err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithNodeOrder(t *testing.T) {
+	type tc struct {
+		order     []ID
+		failNode  ID
+		want      []ID
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"listed nodes first, rest sorted": {
+			order: []ID{"d", "b"},
+			want:  []ID{"d", "b", "a", "c", "app"},
+		},
+		"no order is alphabetical": {
+			order: []ID{},
+			want:  []ID{"a", "b", "c", "d", "app"},
+		},
+		"order cannot break dependencies": {
+			order: []ID{"app", "c"},
+			want:  []ID{"c", "a", "b", "d", "app"},
+		},
+		"stops at first error": {
+			order:     []ID{"c", "a"},
+			failNode:  "a",
+			want:      []ID{"c", "a"},
+			errSubstr: "node a: boom",
+		},
+		"unknown node": {
+			order:     []ID{"a", "missing"},
+			errSubstr: "node order: unknown node: missing",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var ran []ID
+			run := func(id ID) func(ctx context.Context) (any, error) {
+				return func(ctx context.Context) (any, error) {
+					mu.Lock()
+					ran = append(ran, id)
+					mu.Unlock()
+					if id == tt.failNode {
+						return nil, errors.New("boom")
+					}
+					return nil, nil
+				}
+			}
+			nodes := map[ID]node{
+				"a":   makeNode("a", nil, run("a")),
+				"b":   makeNode("b", nil, run("b")),
+				"c":   makeNode("c", nil, run("c")),
+				"d":   makeNode("d", nil, run("d")),
+				"app": makeNode("app", []ID{"a", "b", "c", "d"}, run("app")),
+			}
+
+			_, err := Execute(context.Background(), WithRegistry(nodes), DisableCache(), WithNodeOrder(tt.order))
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tt.errSubstr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(ran, tt.want) {
+				t.Errorf("run order = %v, want %v", ran, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithNodeOrderOutsideSubgraph(t *testing.T) {
+	nodes := map[ID]node{
+		"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return "a", nil }),
+		"b": makeNode("b", nil, func(ctx context.Context) (any, error) { return "b", nil }),
+	}
+
+	results, err := executeForIDs(context.Background(), []ID{"a"},
+		WithRegistry(nodes), DisableCache(), WithNodeOrder([]ID{"b", "a"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results["a"] != "a" {
+		t.Errorf("results = %v, want only a", results)
+	}
+}
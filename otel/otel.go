@@ -0,0 +1,150 @@
+// Package otel traces graft executions with OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grindlemire/graft"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set on every node span.
+const (
+	AttrNodeID   = attribute.Key("graft.node.id")
+	AttrLevel    = attribute.Key("graft.level")
+	AttrCacheHit = attribute.Key("graft.cache_hit")
+)
+
+// remoteKey is the context key under which [PropagateFromHTTP] stores the
+// incoming trace context.
+type remoteKey struct{}
+
+// remote is an incoming trace context extracted from request headers.
+type remote struct {
+	spanContext trace.SpanContext
+	baggage     baggage.Baggage
+}
+
+// WithSpan wraps the execution in a span named spanName, with a child span
+// per node named after its ID. Node spans record the node's level, whether
+// its output came from the cache, and its error, if any. The execution
+// span records the error returned by Execute.
+//
+// The execution span is a child of the span in the context passed to
+// Execute, or of the remote span extracted by [PropagateFromHTTP].
+//
+// Create the option for each execution: spans are tracked per option, so
+// sharing one between concurrent executions mixes their spans.
+//
+// Example:
+//
+//	tracer := otel.Tracer("checkout")
+//	results, err := graft.Execute(ctx, graftotel.WithSpan(tracer, "checkout.graph"))
+func WithSpan(tracer trace.Tracer, spanName string) graft.Option {
+	t := &tracing{tracer: tracer, spans: make(map[graft.ID]trace.Span)}
+
+	return graft.WithHooks(graft.Hooks{
+		BeforeExecute: func(ctx context.Context, nodes []graft.NodeSummary) error {
+			if r, ok := ctx.Value(remoteKey{}).(remote); ok {
+				if r.spanContext.IsValid() {
+					ctx = trace.ContextWithRemoteSpanContext(ctx, r.spanContext)
+				}
+				ctx = baggage.ContextWithBaggage(ctx, r.baggage)
+			}
+			t.mu.Lock()
+			t.ctx, t.span = tracer.Start(ctx, spanName)
+			t.mu.Unlock()
+			return nil
+		},
+		OnStart: func(id graft.ID, level int) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			_, span := t.tracer.Start(t.parent(), string(id), trace.WithAttributes(
+				AttrNodeID.String(string(id)),
+				AttrLevel.Int(level),
+			))
+			t.spans[id] = span
+		},
+		OnCacheHit: func(id graft.ID) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if span, ok := t.spans[id]; ok {
+				span.SetAttributes(AttrCacheHit.Bool(true))
+			}
+		},
+		OnComplete: func(id graft.ID, level int, d time.Duration, err error) {
+			t.mu.Lock()
+			span, ok := t.spans[id]
+			delete(t.spans, id)
+			t.mu.Unlock()
+			if ok {
+				end(span, err)
+			}
+		},
+		AfterExecute: func(ctx context.Context, results graft.Results, err error) {
+			t.mu.Lock()
+			span := t.span
+			t.ctx, t.span = nil, nil
+			t.mu.Unlock()
+			if span != nil {
+				end(span, err)
+			}
+		},
+	})
+}
+
+// tracing holds the spans of an execution traced by [WithSpan].
+type tracing struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	ctx   context.Context // carries the execution span
+	span  trace.Span
+	spans map[graft.ID]trace.Span // open node spans
+}
+
+// parent returns the context node spans are started from.
+func (t *tracing) parent() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+// end records err on span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// PropagateFromHTTP continues the trace of an incoming request: it extracts
+// the trace context and baggage from h with the global propagator (see
+// otel.SetTextMapPropagator), and [WithSpan] starts the execution span as
+// their child.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    results, err := graft.Execute(r.Context(),
+//	        graftotel.PropagateFromHTTP(r.Header),
+//	        graftotel.WithSpan(tracer, "handler.graph"),
+//	    )
+//	}
+func PropagateFromHTTP(h http.Header) graft.Option {
+	ctx := otelapi.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(h))
+	return graft.WithContextValue(remoteKey{}, remote{
+		spanContext: trace.SpanContextFromContext(ctx),
+		baggage:     baggage.FromContext(ctx),
+	})
+}
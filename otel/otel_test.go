@@ -0,0 +1,124 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type configOutput struct{}
+type appOutput struct{}
+
+func TestWithSpan(t *testing.T) {
+	type tc struct {
+		appErr     error
+		wantStatus codes.Code
+	}
+
+	tests := map[string]tc{
+		"success": {
+			wantStatus: codes.Unset,
+		},
+		"node error": {
+			appErr:     errors.New("boom"),
+			wantStatus: codes.Error,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			graft.ResetRegistry()
+			t.Cleanup(graft.ResetRegistry)
+
+			graft.Register(graft.Node[configOutput]{
+				ID:  "config",
+				Run: func(ctx context.Context) (configOutput, error) { return configOutput{}, nil },
+			})
+			graft.Register(graft.Node[appOutput]{
+				ID:        "app",
+				DependsOn: []graft.ID{"config"},
+				Run: func(ctx context.Context) (appOutput, error) {
+					return appOutput{}, tt.appErr
+				},
+			})
+
+			recorder := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+			_, err := graft.Execute(context.Background(), graft.DisableCache(), WithSpan(tracer, "graph"))
+			if (err != nil) != (tt.appErr != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			spans := recorder.Ended()
+			byName := make(map[string]sdktrace.ReadOnlySpan)
+			var names []string
+			for _, s := range spans {
+				byName[s.Name()] = s
+				names = append(names, s.Name())
+			}
+			sort.Strings(names)
+			if len(names) != 3 || names[0] != "app" || names[1] != "config" || names[2] != "graph" {
+				t.Fatalf("spans = %v, want [app config graph]", names)
+			}
+
+			root := byName["graph"]
+			for _, id := range []string{"config", "app"} {
+				if got := byName[id].Parent().SpanID(); got != root.SpanContext().SpanID() {
+					t.Errorf("%s span parent = %s, want the graph span", id, got)
+				}
+			}
+
+			if got := byName["app"].Status().Code; got != tt.wantStatus {
+				t.Errorf("app span status = %v, want %v", got, tt.wantStatus)
+			}
+			if got := root.Status().Code; got != tt.wantStatus {
+				t.Errorf("graph span status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPropagateFromHTTP(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{}, nil },
+	})
+
+	prev := otelapi.GetTextMapPropagator()
+	otelapi.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otelapi.SetTextMapPropagator(prev) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	h := http.Header{}
+	h.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, err := graft.Execute(context.Background(), PropagateFromHTTP(h), WithSpan(tracer, "graph"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, s := range recorder.Ended() {
+		if got := s.SpanContext().TraceID().String(); got != traceID {
+			t.Errorf("%s span trace ID = %s, want %s", s.Name(), got, traceID)
+		}
+		if s.Name() == "graph" && (!s.Parent().IsRemote() || s.Parent().SpanID().String() != "00f067aa0ba902b7") {
+			t.Errorf("graph span parent = %v, want the remote span", s.Parent())
+		}
+	}
+}
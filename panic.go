@@ -0,0 +1,39 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// WithPanicRecovery recovers from panics in node Run functions and reports
+// them as errors from Execute instead of crashing the process. The error
+// names the node and includes the panic value and stack trace.
+//
+// Without this option a panicking node propagates its panic as usual,
+// which is often what you want in development.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.WithPanicRecovery())
+//	if err != nil {
+//	    log.Printf("graph failed: %v", err) // "node db panicked: ..."
+//	}
+func WithPanicRecovery() Option {
+	return func(c *config) {
+		c.recoverPanics = true
+	}
+}
+
+// runNodeRecovered runs a node, converting a panic into an error when panic
+// recovery is enabled.
+func (e *engine) runNodeRecovered(ctx context.Context, nodeID ID, prefetched map[ID]any) (err error) {
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("node %s panicked: %v\n%s", nodeID, r, debug.Stack())
+			}
+		}()
+	}
+	return e.runNode(ctx, nodeID, prefetched)
+}
@@ -0,0 +1,87 @@
+package graft
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithPanicRecovery(t *testing.T) {
+	type tc struct {
+		run     func(ctx context.Context) (any, error)
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"panic with string": {
+			run:     func(ctx context.Context) (any, error) { panic("boom") },
+			wantErr: "node db panicked: boom",
+		},
+		"panic with error": {
+			run: func(ctx context.Context) (any, error) {
+				var m map[string]int
+				m["x"] = 1
+				return nil, nil
+			},
+			wantErr: "node db panicked: assignment to entry in nil map",
+		},
+		"no panic": {
+			run: func(ctx context.Context) (any, error) { return "ok", nil },
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := map[ID]node{
+				"config": makeNode("config", nil, func(ctx context.Context) (any, error) { return "cfg", nil }),
+				"db":     makeNode("db", []ID{"config"}, tt.run),
+			}
+
+			var completedErr error
+			_, err := Execute(context.Background(),
+				WithRegistry(nodes),
+				WithPanicRecovery(),
+				OnNodeComplete(func(id ID, level int, d time.Duration, err error) {
+					if id == "db" {
+						completedErr = err
+					}
+				}),
+			)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), "goroutine") {
+				t.Errorf("expected error to include a stack trace, got %q", err)
+			}
+			if completedErr == nil {
+				t.Errorf("expected OnNodeComplete to receive the panic error")
+			}
+		})
+	}
+}
+
+func TestPanicPropagatesWithoutRecovery(t *testing.T) {
+	nodes := map[ID]node{
+		"db": makeNode("db", nil, func(ctx context.Context) (any, error) { panic("boom") }),
+	}
+
+	e := newEngine(nodes, &config{})
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want boom", r)
+		}
+	}()
+	e.runNodeRecovered(context.Background(), "db", nil)
+	t.Fatal("expected panic")
+}
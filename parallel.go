@@ -0,0 +1,142 @@
+package graft
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WithParallelGroups forces the nodes in each group to run in the same
+// level. Members scheduled earlier are delayed to the level of the latest
+// member, and their dependents are delayed accordingly so dependency order
+// is preserved.
+//
+// No node in a group may depend, directly or transitively, on another node
+// in the same group; execution fails if it does, or if the groups together
+// cannot be scheduled. Group members that are not part of the execution
+// (e.g., outside the subgraph of ExecuteFor) are ignored.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx,
+//	    graft.WithParallelGroups([]graft.ID{"access-log", "audit-log"}),
+//	)
+func WithParallelGroups(groups ...[]ID) Option {
+	return func(c *config) {
+		c.parallelGroups = append(c.parallelGroups, groups...)
+	}
+}
+
+// applyParallelGroups recomputes levels so that every member of each group
+// lands in the same level. Levels only ever move later, and each node stays
+// strictly after all of its dependencies.
+func applyParallelGroups(nodes map[ID]node, levels [][]ID, groups [][]ID) ([][]ID, error) {
+	if len(groups) == 0 {
+		return levels, nil
+	}
+
+	var active [][]ID
+	for _, group := range groups {
+		var members []ID
+		for _, id := range group {
+			if _, ok := nodes[id]; ok {
+				members = append(members, id)
+			}
+		}
+		if len(members) < 2 {
+			continue
+		}
+		if err := checkGroupIndependent(nodes, members); err != nil {
+			return nil, err
+		}
+		active = append(active, members)
+	}
+	if len(active) == 0 {
+		return levels, nil
+	}
+
+	levelOf := make(map[ID]int, len(nodes))
+	for i, level := range levels {
+		for _, id := range level {
+			levelOf[id] = i
+		}
+	}
+
+	// Relax dependency and group constraints until nothing moves. A valid
+	// schedule never needs more levels than there are nodes, so passing that
+	// bound means the groups conflict with each other through dependencies.
+	for changed := true; changed; {
+		changed = false
+		for _, n := range nodes {
+			for _, dep := range n.dependsOn {
+				if levelOf[dep] >= levelOf[n.id] {
+					levelOf[n.id] = levelOf[dep] + 1
+					changed = true
+				}
+			}
+		}
+		for _, members := range active {
+			highest := 0
+			for _, id := range members {
+				highest = max(highest, levelOf[id])
+			}
+			for _, id := range members {
+				if levelOf[id] != highest {
+					levelOf[id] = highest
+					changed = true
+				}
+			}
+		}
+		for id, l := range levelOf {
+			if l >= len(nodes) {
+				return nil, fmt.Errorf("graft: parallel groups conflict with dependency ordering at node %s", id)
+			}
+		}
+	}
+
+	maxLevel := 0
+	for _, l := range levelOf {
+		maxLevel = max(maxLevel, l)
+	}
+	regrouped := make([][]ID, maxLevel+1)
+	for id, l := range levelOf {
+		regrouped[l] = append(regrouped[l], id)
+	}
+
+	// Drop levels emptied by the move and keep output deterministic
+	out := regrouped[:0]
+	for _, level := range regrouped {
+		if len(level) == 0 {
+			continue
+		}
+		sort.Slice(level, func(i, j int) bool { return level[i] < level[j] })
+		out = append(out, level)
+	}
+	return out, nil
+}
+
+// checkGroupIndependent returns an error if any member of a parallel group
+// depends, directly or transitively, on another member of the same group.
+func checkGroupIndependent(nodes map[ID]node, members []ID) error {
+	inGroup := make(map[ID]bool, len(members))
+	for _, id := range members {
+		inGroup[id] = true
+	}
+
+	for _, id := range members {
+		seen := make(map[ID]bool)
+		stack := append([]ID{}, nodes[id].dependsOn...)
+		for len(stack) > 0 {
+			dep := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if inGroup[dep] {
+				return fmt.Errorf("graft: parallel group: node %s depends on %s in the same group", id, dep)
+			}
+			stack = append(stack, nodes[dep].dependsOn...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package graft
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestApplyParallelGroups(t *testing.T) {
+	type tc struct {
+		nodes     map[ID]node
+		groups    [][]ID
+		want      [][]ID
+		errSubstr string
+	}
+
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+
+	tests := map[string]tc{
+		"no groups": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+			},
+			want: [][]ID{{"a"}, {"b"}},
+		},
+		"moves earlier member to latest level": {
+			nodes: map[ID]node{
+				"config":     makeNode("config", nil, noop),
+				"db":         makeNode("db", []ID{"config"}, noop),
+				"access-log": makeNode("access-log", nil, noop),
+				"audit-log":  makeNode("audit-log", []ID{"db"}, noop),
+			},
+			groups: [][]ID{{"access-log", "audit-log"}},
+			want:   [][]ID{{"config"}, {"db"}, {"access-log", "audit-log"}},
+		},
+		"delays dependents of moved member": {
+			nodes: map[ID]node{
+				"a":   makeNode("a", nil, noop),
+				"b":   makeNode("b", []ID{"a"}, noop),
+				"c":   makeNode("c", []ID{"b"}, noop),
+				"x":   makeNode("x", nil, noop),
+				"use": makeNode("use", []ID{"x"}, noop),
+			},
+			groups: [][]ID{{"x", "c"}},
+			want:   [][]ID{{"a"}, {"b"}, {"c", "x"}, {"use"}},
+		},
+		"ignores members outside the graph": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+			},
+			groups: [][]ID{{"a", "missing"}},
+			want:   [][]ID{{"a"}, {"b"}},
+		},
+		"direct dependency in group": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+			},
+			groups:    [][]ID{{"a", "b"}},
+			errSubstr: "node b depends on a in the same group",
+		},
+		"transitive dependency in group": {
+			nodes: map[ID]node{
+				"a": makeNode("a", nil, noop),
+				"b": makeNode("b", []ID{"a"}, noop),
+				"c": makeNode("c", []ID{"b"}, noop),
+			},
+			groups:    [][]ID{{"a", "c"}},
+			errSubstr: "node c depends on a in the same group",
+		},
+		"conflicting groups": {
+			nodes: map[ID]node{
+				"a": makeNode("a", []ID{"c"}, noop),
+				"b": makeNode("b", nil, noop),
+				"c": makeNode("c", nil, noop),
+				"d": makeNode("d", []ID{"b"}, noop),
+			},
+			groups:    [][]ID{{"a", "b"}, {"c", "d"}},
+			errSubstr: "parallel groups conflict with dependency ordering",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			levels, err := topoSortLevels(tt.nodes)
+			if err != nil {
+				t.Fatalf("topoSortLevels: %v", err)
+			}
+
+			got, err := applyParallelGroups(tt.nodes, levels, tt.groups)
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("levels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteWithParallelGroups(t *testing.T) {
+	noop := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"config":     makeNode("config", nil, noop),
+		"access-log": makeNode("access-log", nil, noop),
+		"audit-log":  makeNode("audit-log", []ID{"config"}, noop),
+	}
+
+	var mu sync.Mutex
+	levels := make(map[ID]int)
+	_, err := Execute(context.Background(),
+		WithRegistry(nodes),
+		WithParallelGroups([]ID{"access-log", "audit-log"}),
+		OnNodeStart(func(id ID, level int) {
+			mu.Lock()
+			defer mu.Unlock()
+			levels[id] = level
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[ID]int{"config": 0, "access-log": 1, "audit-log": 1}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+}
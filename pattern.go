@@ -0,0 +1,67 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// ListMatchingNodes returns the sorted IDs of the registered nodes whose ID
+// matches pattern, using [path.Match] semantics: * and ? match any run of
+// characters or a single character, but neither crosses a '/'. An empty
+// slice is returned when nothing matches.
+//
+// Returns [path.ErrBadPattern] if pattern is malformed.
+//
+// Example:
+//
+//	ids, err := graft.ListMatchingNodes("api/v1/*")
+func ListMatchingNodes(pattern string, opts ...Option) ([]ID, error) {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return matchingIDs(cfg.registry, pattern)
+}
+
+// ExecuteForPattern runs every node whose ID matches pattern (see
+// [ListMatchingNodes]) together with their transitive dependencies.
+//
+// Returns an error if pattern is malformed or matches no nodes.
+//
+// Example:
+//
+//	results, err := graft.ExecuteForPattern(ctx, "api/v1/*")
+func ExecuteForPattern(ctx context.Context, pattern string, opts ...Option) (Results, error) {
+	cfg := &config{registry: Registry(), cache: defaultCache}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	targets, err := matchingIDs(cfg.registry, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no nodes match pattern %q", pattern)
+	}
+
+	return executeSubgraph(ctx, cfg, targets)
+}
+
+// matchingIDs returns the sorted IDs in registry that match pattern.
+func matchingIDs(registry map[ID]node, pattern string) ([]ID, error) {
+	// Validate the pattern up front so an empty registry still reports it.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	ids := []ID{}
+	for _, id := range SortedIDs(registry) {
+		if ok, _ := path.Match(pattern, string(id)); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,112 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func patternNodes() map[ID]node {
+	run := func(ctx context.Context) (any, error) { return "ok", nil }
+	return map[ID]node{
+		"config":             makeNode("config", nil, run),
+		"api/v1/users":       makeNode("api/v1/users", []ID{"config"}, run),
+		"api/v1/orders":      makeNode("api/v1/orders", []ID{"config"}, run),
+		"api/v2/users":       makeNode("api/v2/users", []ID{"config"}, run),
+		"api/v1/admin/audit": makeNode("api/v1/admin/audit", nil, run),
+	}
+}
+
+func TestListMatchingNodes(t *testing.T) {
+	type tc struct {
+		pattern string
+		want    []ID
+		wantErr error
+	}
+
+	tests := map[string]tc{
+		"star within segment": {
+			pattern: "api/v1/*",
+			want:    []ID{"api/v1/orders", "api/v1/users"},
+		},
+		"star does not cross separator": {
+			pattern: "api/*",
+			want:    []ID{},
+		},
+		"question mark": {
+			pattern: "api/v?/users",
+			want:    []ID{"api/v1/users", "api/v2/users"},
+		},
+		"literal": {
+			pattern: "config",
+			want:    []ID{"config"},
+		},
+		"no match": {
+			pattern: "db*",
+			want:    []ID{},
+		},
+		"bad pattern": {
+			pattern: "api/[",
+			wantErr: path.ErrBadPattern,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListMatchingNodes(tt.pattern, WithRegistry(patternNodes()))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListMatchingNodes(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteForPattern(t *testing.T) {
+	type tc struct {
+		pattern string
+		want    []ID
+		wantErr bool
+	}
+
+	tests := map[string]tc{
+		"matches with dependencies": {
+			pattern: "api/v1/*",
+			want:    []ID{"api/v1/orders", "api/v1/users", "config"},
+		},
+		"no match": {
+			pattern: "db*",
+			wantErr: true,
+		},
+		"bad pattern": {
+			pattern: "[",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := ExecuteForPattern(context.Background(), tt.pattern, WithRegistry(patternNodes()), DisableCache())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []ID
+			for id := range results {
+				got = append(got, id)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("executed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
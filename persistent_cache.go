@@ -0,0 +1,212 @@
+package graft
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FlushableCache is an optional extension of [Cache] for implementations
+// that buffer writes. When the cache used by Execute/ExecuteFor implements
+// FlushableCache, Flush is called after every successful execution.
+type FlushableCache interface {
+	Cache
+
+	// Flush persists any pending writes.
+	Flush() error
+}
+
+// PersistentCache is a [MemoryCache] that can be saved to and loaded from
+// disk, allowing cached node outputs to survive between process invocations
+// (e.g., for CLI tools that run repeatedly).
+//
+// Values are serialized with encoding/gob. Because entries are stored as
+// interface values, every concrete output type must be registered with
+// [gob.Register] before calling Save or Load.
+//
+// Set, Delete, Clear, and invalidation mark the cache dirty; pending changes are written
+// to the backing file by Flush (called automatically after each execution)
+// or explicitly by Save.
+type PersistentCache struct {
+	mem  *MemoryCache
+	path string
+
+	mu    sync.Mutex // guards dirty
+	dirty bool
+}
+
+// NewPersistentCache creates a persistent cache backed by the file at path.
+// If the file exists its contents are loaded; otherwise the cache starts empty
+// and the file is created on the first flush.
+//
+// Example:
+//
+//	gob.Register(config.Output{})
+//	cache, err := graft.NewPersistentCache(".graft-cache")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	out, _, err := graft.ExecuteFor[app.Output](ctx, graft.WithCache(cache))
+func NewPersistentCache(path string) (*PersistentCache, error) {
+	c := &PersistentCache{
+		mem:  NewMemoryCache(),
+		path: path,
+	}
+	if err := c.Load(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get retrieves a value from the cache.
+func (c *PersistentCache) Get(ctx context.Context, id ID) (any, bool, error) {
+	return c.mem.Get(ctx, id)
+}
+
+// Has reports whether id has a cached value.
+func (c *PersistentCache) Has(ctx context.Context, id ID) (bool, error) {
+	return c.mem.Has(ctx, id)
+}
+
+// Set stores a value in the cache and marks it dirty.
+func (c *PersistentCache) Set(ctx context.Context, id ID, value any) error {
+	if err := c.mem.Set(ctx, id, value); err != nil {
+		return err
+	}
+	c.markDirty()
+	return nil
+}
+
+// Delete removes specific entries from the cache and marks it dirty.
+func (c *PersistentCache) Delete(ids ...ID) {
+	c.mem.Delete(ids...)
+	c.markDirty()
+}
+
+// Clear removes all entries from the cache and marks it dirty.
+func (c *PersistentCache) Clear() {
+	c.mem.Clear()
+	c.markDirty()
+}
+
+// Tag associates a cache entry with tags. Tags are kept in memory only and
+// are not written to the backing file.
+func (c *PersistentCache) Tag(ctx context.Context, id ID, tags ...string) error {
+	return c.mem.Tag(ctx, id, tags...)
+}
+
+// Invalidate removes every entry associated with tag and marks the cache dirty.
+func (c *PersistentCache) Invalidate(tag string) error {
+	return c.InvalidateByTags(tag)
+}
+
+// InvalidateByTags removes every entry associated with any of the tags and
+// marks the cache dirty.
+func (c *PersistentCache) InvalidateByTags(tags ...string) error {
+	if err := c.mem.InvalidateByTags(tags...); err != nil {
+		return err
+	}
+	c.markDirty()
+	return nil
+}
+
+// Stats returns the cache's operation counts. See [MemoryCache.Stats].
+func (c *PersistentCache) Stats() CacheStats {
+	return c.mem.Stats()
+}
+
+// Snapshot returns a copy of all cached values.
+func (c *PersistentCache) Snapshot() map[ID]any {
+	return c.mem.Snapshot()
+}
+
+// Load replaces the cache contents with the entries stored at path. Tags
+// are not persisted, so the loaded entries start untagged.
+func (c *PersistentCache) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("graft: load cache: %w", err)
+	}
+	defer f.Close()
+
+	store := make(map[ID]any)
+	if err := gob.NewDecoder(f).Decode(&store); err != nil {
+		return fmt.Errorf("graft: load cache %s: %w", path, err)
+	}
+
+	// Drop the tags of the replaced entries, or invalidating one of them
+	// would evict a loaded entry with the same ID
+	c.mem.mu.Lock()
+	c.mem.store = store
+	c.mem.tagIndex = make(map[string]map[ID]struct{})
+	c.mem.mu.Unlock()
+	return nil
+}
+
+// Save writes the cache contents to path. The file is written atomically
+// by encoding to a temporary file in the same directory and renaming it.
+func (c *PersistentCache) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("graft: save cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(c.mem.Snapshot()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("graft: save cache %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("graft: save cache %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("graft: save cache %s: %w", path, err)
+	}
+	return nil
+}
+
+// Flush saves the cache to its backing file if there are unsaved changes.
+func (c *PersistentCache) Flush() error {
+	// Clear the flag before saving so writes that race with Save are
+	// picked up by the next flush.
+	c.mu.Lock()
+	dirty := c.dirty
+	c.dirty = false
+	c.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+
+	if err := c.Save(c.path); err != nil {
+		c.markDirty()
+		return err
+	}
+	return nil
+}
+
+// FlushOnContext flushes the cache once ctx is done. Errors from the
+// background flush are discarded; call [PersistentCache.Flush] directly
+// when the error matters.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	cache.FlushOnContext(ctx)
+func (c *PersistentCache) FlushOnContext(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		_ = c.Flush()
+	}()
+}
+
+func (c *PersistentCache) markDirty() {
+	c.mu.Lock()
+	c.dirty = true
+	c.mu.Unlock()
+}
@@ -0,0 +1,252 @@
+package graft
+
+import (
+	"context"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type persistentTestOutput struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(persistentTestOutput{})
+}
+
+func TestNewPersistentCache(t *testing.T) {
+	type tc struct {
+		setup     func(t *testing.T, path string)
+		wantErr   bool
+		wantStore map[ID]any
+	}
+
+	tests := map[string]tc{
+		"missing file starts empty": {
+			setup:     func(t *testing.T, path string) {},
+			wantStore: map[ID]any{},
+		},
+		"existing file is loaded": {
+			setup: func(t *testing.T, path string) {
+				c, err := NewPersistentCache(path)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				_ = c.Set(context.Background(), "a", "value")
+				_ = c.Set(context.Background(), "b", persistentTestOutput{Name: "x", Count: 2})
+				if err := c.Flush(); err != nil {
+					t.Fatalf("Flush error: %v", err)
+				}
+			},
+			wantStore: map[ID]any{
+				"a": "value",
+				"b": persistentTestOutput{Name: "x", Count: 2},
+			},
+		},
+		"corrupt file is an error": {
+			setup: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte("not gob"), 0644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.gob")
+			tt.setup(t, path)
+
+			c, err := NewPersistentCache(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := c.Snapshot()
+			if len(got) != len(tt.wantStore) {
+				t.Errorf("got %d entries, want %d", len(got), len(tt.wantStore))
+			}
+			for k, want := range tt.wantStore {
+				if got[k] != want {
+					t.Errorf("entry[%q] = %v, want %v", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPersistentCacheFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	c, err := NewPersistentCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Clean cache does not write a file
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no file for clean cache, stat err = %v", err)
+	}
+
+	_ = c.Set(context.Background(), "a", 1)
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected file after flush: %v", err)
+	}
+
+	c.Delete("a")
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	reloaded, err := NewPersistentCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found, _ := reloaded.Get(context.Background(), "a"); found {
+		t.Error("deleted entry should not be persisted")
+	}
+}
+
+func TestPersistentCacheFlushOnContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	c, err := NewPersistentCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.FlushOnContext(ctx)
+	_ = c.Set(context.Background(), "a", "value")
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cache was not flushed after context was cancelled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPersistentCacheAcrossExecutions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	var runCount atomic.Int32
+
+	nodes := map[ID]node{
+		"out": {
+			id: "out",
+			run: func(ctx context.Context) (any, error) {
+				runCount.Add(1)
+				return persistentTestOutput{Name: "computed", Count: 1}, nil
+			},
+			cacheable: true,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		// A fresh cache per iteration simulates separate process invocations
+		cache, err := NewPersistentCache(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		results, err := Execute(context.Background(), WithRegistry(nodes), WithCache(cache))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if results["out"] != (persistentTestOutput{Name: "computed", Count: 1}) {
+			t.Errorf("got %v, want computed output", results["out"])
+		}
+	}
+
+	if got := runCount.Load(); got != 1 {
+		t.Errorf("node ran %d times, want 1 (second run should load from disk)", got)
+	}
+}
+
+func TestPersistentCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	c, err := NewPersistentCache(path)
+	if err != nil {
+		t.Fatalf("NewPersistentCache error: %v", err)
+	}
+
+	_ = c.Set(ctx, "config", "value")
+	_ = c.Set(ctx, "db", "pool")
+	_ = c.Tag(ctx, "config", "config")
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	if err := c.Invalidate("config"); err != nil {
+		t.Fatalf("Invalidate error: %v", err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	reloaded, err := NewPersistentCache(path)
+	if err != nil {
+		t.Fatalf("NewPersistentCache error: %v", err)
+	}
+	snapshot := reloaded.Snapshot()
+	if _, ok := snapshot["config"]; ok {
+		t.Error("invalidated entry should not be persisted")
+	}
+	if _, ok := snapshot["db"]; !ok {
+		t.Error("untagged entry should be persisted")
+	}
+}
+
+func TestPersistentCacheLoadDropsTags(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	saved := filepath.Join(dir, "saved.gob")
+	other, err := NewPersistentCache(saved)
+	if err != nil {
+		t.Fatalf("NewPersistentCache error: %v", err)
+	}
+	_ = other.Set(ctx, "config", "loaded")
+	if err := other.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	c, err := NewPersistentCache(filepath.Join(dir, "cache.gob"))
+	if err != nil {
+		t.Fatalf("NewPersistentCache error: %v", err)
+	}
+	_ = c.Set(ctx, "config", "stale")
+	_ = c.Tag(ctx, "config", "env")
+	if err := c.Load(saved); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if err := c.Invalidate("env"); err != nil {
+		t.Fatalf("Invalidate error: %v", err)
+	}
+	got, found, _ := c.Get(ctx, "config")
+	if !found || got != "loaded" {
+		t.Errorf("Get(config) = %v, %v; want loaded, true", got, found)
+	}
+}
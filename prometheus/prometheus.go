@@ -0,0 +1,202 @@
+// Package prometheus exposes graft execution metrics as a Prometheus
+// collector.
+//
+// A [Collector] reports registry gauges when scraped and records per-node
+// execution counts and durations through the hooks installed by its
+// [Collector.Option]:
+//
+//	graft_nodes_registered                    gauge
+//	graft_graph_levels                        gauge
+//	graft_cache_entries                       gauge
+//	graft_cache_hits                          gauge
+//	graft_cache_misses                        gauge
+//	graft_cache_sets                          gauge
+//	graft_cache_deletes                       gauge
+//	graft_node_executions_total{node,status}  counter (status: success, error)
+//	graft_node_cache_hits_total{node}         counter
+//	graft_node_duration_seconds{node}         histogram
+//
+// Alternatively, [PrometheusMetricsSink] adapts the engine's own
+// [graft.MetricsSink] metrics for use with [graft.WithMetrics].
+package prometheus
+
+import (
+	"time"
+
+	"github.com/grindlemire/graft"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector implements prometheus.Collector for graft. Create one with
+// [NewCollector] and pass [Collector.Option] to each execution.
+type Collector struct {
+	opts []graft.Option
+
+	nodesDesc  *prometheus.Desc
+	levelsDesc *prometheus.Desc
+	cacheDesc  *prometheus.Desc
+
+	// Operation counts of graft.DefaultCache (see graft.CacheStats)
+	cacheHitsDesc    *prometheus.Desc
+	cacheMissesDesc  *prometheus.Desc
+	cacheSetsDesc    *prometheus.Desc
+	cacheDeletesDesc *prometheus.Desc
+
+	executions *prometheus.CounterVec
+	cacheHits  *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+
+	// nodes holds the series of every node registered when the collector
+	// was created, so recording them needs no label lookup
+	nodes map[graft.ID]nodeMetrics
+}
+
+// nodeMetrics are the pre-resolved series of one node.
+type nodeMetrics struct {
+	success  prometheus.Counter
+	failure  prometheus.Counter
+	cacheHit prometheus.Counter
+	duration prometheus.Observer
+}
+
+// NewCollector returns a collector for the graph selected by opts (the
+// global registry by default, or [graft.WithRegistry]). Registry gauges are
+// computed from that graph on every scrape; graft_cache_entries counts the
+// entries of [graft.DefaultCache] and graft_cache_hits, _misses, _sets and
+// _deletes report its [graft.DefaultCacheStats]. Those are gauges rather
+// than counters because [graft.MemoryCache.ResetStats] and
+// [graft.ResetDefaultCache] set them back to zero.
+//
+// Example:
+//
+//	c := graftprom.NewCollector()
+//	prometheus.MustRegister(c)
+//	results, err := graft.Execute(ctx, c.Option())
+func NewCollector(opts ...graft.Option) *Collector {
+	c := &Collector{
+		opts: opts,
+		nodesDesc: prometheus.NewDesc("graft_nodes_registered",
+			"Number of nodes in the graft registry.", nil, nil),
+		levelsDesc: prometheus.NewDesc("graft_graph_levels",
+			"Number of topological levels in the graft graph.", nil, nil),
+		cacheDesc: prometheus.NewDesc("graft_cache_entries",
+			"Number of entries in the default graft cache.", nil, nil),
+		cacheHitsDesc: prometheus.NewDesc("graft_cache_hits",
+			"Number of default graft cache lookups that found a value since its stats were last reset.", nil, nil),
+		cacheMissesDesc: prometheus.NewDesc("graft_cache_misses",
+			"Number of default graft cache lookups that found nothing since its stats were last reset.", nil, nil),
+		cacheSetsDesc: prometheus.NewDesc("graft_cache_sets",
+			"Number of values stored in the default graft cache since its stats were last reset.", nil, nil),
+		cacheDeletesDesc: prometheus.NewDesc("graft_cache_deletes",
+			"Number of entries removed from the default graft cache since its stats were last reset.", nil, nil),
+		executions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: graft.MetricNodeExecutions,
+			Help: "Number of node resolutions by outcome.",
+		}, []string{"node", "status"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graft_node_cache_hits_total",
+			Help: "Number of node resolutions served from the cache.",
+		}, []string{"node"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    graft.MetricNodeDuration,
+			Help:    "Time taken to resolve each node.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"node"}),
+		nodes: make(map[graft.ID]nodeMetrics),
+	}
+
+	for _, n := range graft.ListNodes(opts...) {
+		c.nodes[n.ID] = c.newNodeMetrics(n.ID)
+	}
+	return c
+}
+
+// MustRegister creates a collector with [NewCollector], registers it with
+// registerer and returns it. It panics if registration fails.
+//
+// Example:
+//
+//	c := graftprom.MustRegister(prometheus.DefaultRegisterer)
+func MustRegister(registerer prometheus.Registerer, opts ...graft.Option) *Collector {
+	c := NewCollector(opts...)
+	registerer.MustRegister(c)
+	return c
+}
+
+// Option returns the hooks that record node executions into the collector.
+//
+// Example:
+//
+//	out, _, err := graft.ExecuteFor[app.Output](ctx, c.Option())
+func (c *Collector) Option() graft.Option {
+	return graft.WithHooks(graft.Hooks{
+		OnCacheHit: func(id graft.ID) {
+			c.metricsFor(id).cacheHit.Inc()
+		},
+		OnComplete: func(id graft.ID, level int, d time.Duration, err error) {
+			m := c.metricsFor(id)
+			if err != nil {
+				m.failure.Inc()
+			} else {
+				m.success.Inc()
+			}
+			m.duration.Observe(d.Seconds())
+		},
+	})
+}
+
+// metricsFor returns the series of id, resolving them for nodes that were
+// not registered when the collector was created.
+func (c *Collector) metricsFor(id graft.ID) nodeMetrics {
+	if m, ok := c.nodes[id]; ok {
+		return m
+	}
+	return c.newNodeMetrics(id)
+}
+
+// newNodeMetrics resolves the series of id.
+func (c *Collector) newNodeMetrics(id graft.ID) nodeMetrics {
+	node := string(id)
+	return nodeMetrics{
+		success:  c.executions.WithLabelValues(node, "success"),
+		failure:  c.executions.WithLabelValues(node, "error"),
+		cacheHit: c.cacheHits.WithLabelValues(node),
+		duration: c.duration.WithLabelValues(node),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.nodesDesc
+	ch <- c.levelsDesc
+	ch <- c.cacheDesc
+	ch <- c.cacheHitsDesc
+	ch <- c.cacheMissesDesc
+	ch <- c.cacheSetsDesc
+	ch <- c.cacheDeletesDesc
+	c.executions.Describe(ch)
+	c.cacheHits.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector. graft_graph_levels is omitted
+// while the graph is invalid (for example, has a cycle).
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.nodesDesc, prometheus.GaugeValue,
+		float64(len(graft.ListNodes(c.opts...))))
+	if levels, err := graft.Levels(c.opts...); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.levelsDesc, prometheus.GaugeValue, float64(len(levels)))
+	}
+	ch <- prometheus.MustNewConstMetric(c.cacheDesc, prometheus.GaugeValue,
+		float64(len(graft.DefaultCache().Snapshot())))
+
+	stats := graft.DefaultCacheStats()
+	ch <- prometheus.MustNewConstMetric(c.cacheHitsDesc, prometheus.GaugeValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.cacheMissesDesc, prometheus.GaugeValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.cacheSetsDesc, prometheus.GaugeValue, float64(stats.Sets))
+	ch <- prometheus.MustNewConstMetric(c.cacheDeletesDesc, prometheus.GaugeValue, float64(stats.Deletes))
+
+	c.executions.Collect(ch)
+	c.cacheHits.Collect(ch)
+	c.duration.Collect(ch)
+}
@@ -0,0 +1,158 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type configOutput struct{}
+type appOutput struct{}
+
+func registerNodes(t *testing.T, appErr error) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID:        "config",
+		Cacheable: true,
+		Run:       func(ctx context.Context) (configOutput, error) { return configOutput{}, nil },
+	})
+	graft.Register(graft.Node[appOutput]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config"},
+		Run:       func(ctx context.Context) (appOutput, error) { return appOutput{}, appErr },
+	})
+}
+
+func TestCollectorExecutions(t *testing.T) {
+	type tc struct {
+		appErr      error
+		wantSuccess float64
+		wantError   float64
+	}
+
+	tests := map[string]tc{
+		"success": {wantSuccess: 1},
+		"error":   {appErr: errors.New("boom"), wantError: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t, tt.appErr)
+			graft.ResetDefaultCache()
+			t.Cleanup(graft.ResetDefaultCache)
+
+			c := NewCollector()
+			for i := 0; i < 2; i++ {
+				_, _ = graft.Execute(context.Background(), c.Option())
+			}
+
+			if got := testutil.ToFloat64(c.executions.WithLabelValues("config", "success")); got != 2 {
+				t.Errorf("config successes = %v, want 2", got)
+			}
+			if got := testutil.ToFloat64(c.cacheHits.WithLabelValues("config")); got != 1 {
+				t.Errorf("config cache hits = %v, want 1", got)
+			}
+			if got := testutil.ToFloat64(c.executions.WithLabelValues("app", "success")); got != 2*tt.wantSuccess {
+				t.Errorf("app successes = %v, want %v", got, 2*tt.wantSuccess)
+			}
+			if got := testutil.ToFloat64(c.executions.WithLabelValues("app", "error")); got != 2*tt.wantError {
+				t.Errorf("app errors = %v, want %v", got, 2*tt.wantError)
+			}
+			if got := testutil.CollectAndCount(c.duration); got != 2 {
+				t.Errorf("duration series = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCollectorGauges(t *testing.T) {
+	registerNodes(t, nil)
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetDefaultCache)
+
+	reg := prometheus.NewPedanticRegistry()
+	c := MustRegister(reg)
+	if _, err := graft.Execute(context.Background(), c.Option()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	gauges := make(map[string]float64)
+	for _, mf := range families {
+		if mf.GetType().String() == "GAUGE" {
+			gauges[mf.GetName()] = mf.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+
+	want := map[string]float64{
+		"graft_nodes_registered": 2,
+		"graft_graph_levels":     2,
+		"graft_cache_entries":    1,
+	}
+	for name, v := range want {
+		if gauges[name] != v {
+			t.Errorf("%s = %v, want %v", name, gauges[name], v)
+		}
+	}
+}
+
+func TestCollectorCacheStats(t *testing.T) {
+	registerNodes(t, nil)
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetDefaultCache)
+
+	reg := prometheus.NewPedanticRegistry()
+	c := MustRegister(reg)
+	for i := 0; i < 2; i++ {
+		if _, err := graft.Execute(context.Background(), c.Option()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	gather := func() map[string]float64 {
+		t.Helper()
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatalf("Gather: %v", err)
+		}
+		gauges := make(map[string]float64)
+		for _, mf := range families {
+			if mf.GetType().String() == "GAUGE" && len(mf.GetMetric()) == 1 {
+				gauges[mf.GetName()] = mf.GetMetric()[0].GetGauge().GetValue()
+			}
+		}
+		return gauges
+	}
+
+	want := map[string]float64{
+		"graft_cache_hits":    1,
+		"graft_cache_misses":  1,
+		"graft_cache_sets":    1,
+		"graft_cache_deletes": 0,
+	}
+	gauges := gather()
+	for name, v := range want {
+		if got, ok := gauges[name]; !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", name, got, ok, v)
+		}
+	}
+
+	// The stats can be reset, which a counter must never report
+	graft.DefaultCache().ResetStats()
+	gauges = gather()
+	for name := range want {
+		if got, ok := gauges[name]; !ok || got != 0 {
+			t.Errorf("after ResetStats: %s = %v (present %v), want 0", name, got, ok)
+		}
+	}
+}
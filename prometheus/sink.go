@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grindlemire/graft"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsSink implements graft.MetricsSink on top of Prometheus vectors.
+type metricsSink struct {
+	executions *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+// PrometheusMetricsSink returns a [graft.MetricsSink] that records the
+// engine's metrics in vectors registered with registerer:
+//
+//	graft_node_executions_total{node,cache_hit,status}  counter
+//	graft_node_duration_seconds{node}                   histogram
+//
+// Pass it to [graft.WithMetrics]. Calling PrometheusMetricsSink again with
+// the same registerer reuses the registered vectors. It panics if another
+// collector with the same metric names but different labels is already
+// registered, such as a [Collector]; use the sink or a Collector's
+// [Collector.Option] for node metrics, not both on one registerer.
+//
+// Example:
+//
+//	sink := graftprom.PrometheusMetricsSink(prometheus.DefaultRegisterer)
+//	results, err := graft.Execute(ctx, graft.WithMetrics(sink))
+func PrometheusMetricsSink(registerer prometheus.Registerer) graft.MetricsSink {
+	executions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: graft.MetricNodeExecutions,
+		Help: "Number of node resolutions by cache hit and outcome.",
+	}, []string{"node", "cache_hit", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    graft.MetricNodeDuration,
+		Help:    "Time taken to run each node.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"node"})
+
+	return &metricsSink{
+		executions: mustRegisterOrExisting(registerer, executions),
+		duration:   mustRegisterOrExisting(registerer, duration),
+	}
+}
+
+// mustRegisterOrExisting registers c, returning the already registered
+// collector instead if an identical one exists.
+func mustRegisterOrExisting[C prometheus.Collector](registerer prometheus.Registerer, c C) C {
+	err := registerer.Register(c)
+	if err == nil {
+		return c
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(C); ok {
+			return existing
+		}
+	}
+	panic(err)
+}
+
+// IncrCounter implements graft.MetricsSink. Metrics other than
+// graft.MetricNodeExecutions are ignored.
+func (s *metricsSink) IncrCounter(name string, labels map[string]string) {
+	if name != graft.MetricNodeExecutions {
+		return
+	}
+	s.executions.With(prometheus.Labels{
+		"node":      labels["node"],
+		"cache_hit": labels["cache_hit"],
+		"status":    labels["status"],
+	}).Inc()
+}
+
+// RecordDuration implements graft.MetricsSink. Metrics other than
+// graft.MetricNodeDuration are ignored.
+func (s *metricsSink) RecordDuration(name string, d time.Duration, labels map[string]string) {
+	if name != graft.MetricNodeDuration {
+		return
+	}
+	s.duration.With(prometheus.Labels{"node": labels["node"]}).Observe(d.Seconds())
+}
@@ -0,0 +1,75 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusMetricsSink(t *testing.T) {
+	type tc struct {
+		appErr     error
+		wantStatus string
+	}
+
+	tests := map[string]tc{
+		"success": {wantStatus: "success"},
+		"error":   {appErr: errors.New("boom"), wantStatus: "error"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registerNodes(t, tt.appErr)
+			graft.ResetDefaultCache()
+			t.Cleanup(graft.ResetDefaultCache)
+
+			reg := prometheus.NewPedanticRegistry()
+			sink := PrometheusMetricsSink(reg).(*metricsSink)
+			for i := 0; i < 2; i++ {
+				_, _ = graft.Execute(context.Background(), graft.WithMetrics(sink))
+			}
+
+			want := map[[3]string]float64{
+				{"config", "false", "success"}:  1,
+				{"config", "true", "success"}:   1,
+				{"app", "false", tt.wantStatus}: 2,
+			}
+			for labels, v := range want {
+				if got := testutil.ToFloat64(sink.executions.WithLabelValues(labels[:]...)); got != v {
+					t.Errorf("executions%v = %v, want %v", labels, got, v)
+				}
+			}
+			if got := testutil.CollectAndCount(sink.duration); got != 2 {
+				t.Errorf("duration series = %d, want 2", got)
+			}
+			if _, err := reg.Gather(); err != nil {
+				t.Errorf("Gather: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrometheusMetricsSinkReusesRegisteredVectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first := PrometheusMetricsSink(reg).(*metricsSink)
+	second := PrometheusMetricsSink(reg).(*metricsSink)
+	if first.executions != second.executions || first.duration != second.duration {
+		t.Error("second sink should reuse the vectors registered by the first")
+	}
+}
+
+func TestPrometheusMetricsSinkConflictPanics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewCollector(graft.WithRegistry(nil)))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for conflicting metric labels")
+		}
+	}()
+	PrometheusMetricsSink(reg)
+}
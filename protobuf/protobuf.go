@@ -0,0 +1,167 @@
+// Package protobuf converts graft execution results to and from Protocol
+// Buffers so that they can be consumed outside Go.
+//
+// Results are wrapped in a graft.Results envelope message holding one
+// google.protobuf.Any per node, keyed by node ID. Every node output must be
+// a proto.Message; its type URL lets readers in any language decode it
+// without knowing the node's Go type.
+package protobuf
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/grindlemire/graft"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ResultsMessageName is the full name of the envelope message produced by
+// [ResultsToProto]. Its schema is:
+//
+//	syntax = "proto3";
+//	package graft;
+//	import "google/protobuf/any.proto";
+//	message Results {
+//	    map<string, google.protobuf.Any> nodes = 1;
+//	}
+const ResultsMessageName = "graft.Results"
+
+var resultsDesc = sync.OnceValue(func() protoreflect.MessageDescriptor {
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	message := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("graft/results.proto"),
+		Package:    proto.String("graft"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/any.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Results"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("nodes"),
+				JsonName: proto.String("nodes"),
+				Number:   proto.Int32(1),
+				Label:    repeated,
+				Type:     message,
+				TypeName: proto.String(".graft.Results.NodesEntry"),
+			}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("NodesEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: str},
+					{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: message, TypeName: proto.String(".google.protobuf.Any")},
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}
+
+	file, err := protodesc.NewFile(fd, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(fmt.Sprintf("graft protobuf: building %s descriptor: %v", ResultsMessageName, err))
+	}
+	return file.Messages().Get(0)
+})
+
+// ResultsToProto packs every node output in results into a graft.Results
+// envelope (see [ResultsMessageName]) wrapped in an Any. Returns an error
+// if an output is not a proto.Message.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx)
+//	env, err := protobuf.ResultsToProto(results)
+//	b, err := proto.Marshal(env)
+func ResultsToProto(results graft.Results) (*anypb.Any, error) {
+	desc := resultsDesc()
+	env := dynamicpb.NewMessage(desc)
+	nodes := env.Mutable(desc.Fields().ByName("nodes")).Map()
+
+	for id, out := range results {
+		msg, ok := out.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("node %s: output %T is not a proto.Message", id, out)
+		}
+		packed, err := anypb.New(msg)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", id, err)
+		}
+		nodes.Set(protoreflect.ValueOfString(string(id)).MapKey(), protoreflect.ValueOfMessage(packed.ProtoReflect()))
+	}
+
+	return anypb.New(env)
+}
+
+// ProtoToResults is the inverse of [ResultsToProto]. Each node output is
+// unpacked into its concrete Go type, which must be linked into the program
+// (or registered with [NodeMessage]) so that its type URL resolves.
+//
+// Example:
+//
+//	env := &anypb.Any{}
+//	if err := proto.Unmarshal(b, env); err != nil { ... }
+//	results, err := protobuf.ProtoToResults(env)
+//	cfg, err := graft.Result[*configpb.Config](results)
+func ProtoToResults(env *anypb.Any) (graft.Results, error) {
+	desc := resultsDesc()
+	if got := env.MessageName(); got != ResultsMessageName {
+		return nil, fmt.Errorf("envelope is %s, want %s", got, ResultsMessageName)
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(env.GetValue(), msg); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", ResultsMessageName, err)
+	}
+
+	results := make(graft.Results)
+	var err error
+	msg.Get(desc.Fields().ByName("nodes")).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		id := graft.ID(k.String())
+		packed := &anypb.Any{}
+		proto.Merge(packed, v.Message().Interface())
+
+		out, uerr := packed.UnmarshalNew()
+		if uerr != nil {
+			err = fmt.Errorf("node %s: %w", id, uerr)
+			return false
+		}
+		results[id] = out
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// NodeMessage returns n unchanged after making sure the message type T is
+// registered in the global protobuf registry, so that [ProtoToResults] can
+// resolve outputs of this node. Generated message types normally register
+// themselves when their package is initialized; NodeMessage registers T if
+// it is missing and panics if that fails, like graft.Register does for
+// invalid nodes.
+//
+// Example:
+//
+//	graft.Register(protobuf.NodeMessage(graft.Node[*configpb.Config]{
+//	    ID:  "config",
+//	    Run: loadConfig,
+//	}))
+func NodeMessage[T proto.Message](n graft.Node[T]) graft.Node[T] {
+	var zero T
+	mt := zero.ProtoReflect().Type()
+	if _, err := protoregistry.GlobalTypes.FindMessageByName(mt.Descriptor().FullName()); err != nil {
+		if err := protoregistry.GlobalTypes.RegisterMessage(mt); err != nil {
+			panic(fmt.Sprintf("graft protobuf: registering %s: %v", mt.Descriptor().FullName(), err))
+		}
+	}
+	return n
+}
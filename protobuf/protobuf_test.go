@@ -0,0 +1,104 @@
+package protobuf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResultsToProtoRoundTrip(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(NodeMessage(graft.Node[*wrapperspb.StringValue]{
+		ID:  "name",
+		Run: func(ctx context.Context) (*wrapperspb.StringValue, error) { return wrapperspb.String("graft"), nil },
+	}))
+	graft.Register(NodeMessage(graft.Node[*durationpb.Duration]{
+		ID:        "timeout",
+		DependsOn: []graft.ID{"name"},
+		Run: func(ctx context.Context) (*durationpb.Duration, error) {
+			return &durationpb.Duration{Seconds: 30}, nil
+		},
+	}))
+
+	results, err := graft.Execute(context.Background(), graft.DisableCache())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env, err := ResultsToProto(results)
+	if err != nil {
+		t.Fatalf("ResultsToProto: %v", err)
+	}
+	if got := env.MessageName(); got != ResultsMessageName {
+		t.Errorf("envelope type = %s, want %s", got, ResultsMessageName)
+	}
+
+	b, err := proto.Marshal(env)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded := &anypb.Any{}
+	if err := proto.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got, err := ProtoToResults(decoded)
+	if err != nil {
+		t.Fatalf("ProtoToResults: %v", err)
+	}
+	if len(got) != len(results) {
+		t.Fatalf("decoded %d results, want %d", len(got), len(results))
+	}
+	for id, want := range results {
+		if !proto.Equal(got[id].(proto.Message), want.(proto.Message)) {
+			t.Errorf("node %s = %v, want %v", id, got[id], want)
+		}
+	}
+
+	name, err := graft.Result[*wrapperspb.StringValue](got)
+	if err != nil || name.GetValue() != "graft" {
+		t.Errorf("Result[*StringValue] = %v, %v; want graft", name, err)
+	}
+}
+
+func TestResultsToProtoErrors(t *testing.T) {
+	type tc struct {
+		run       func() error
+		errSubstr string
+	}
+
+	tests := map[string]tc{
+		"non-proto output": {
+			run: func() error {
+				_, err := ResultsToProto(graft.Results{"port": 8080})
+				return err
+			},
+			errSubstr: "node port: output int is not a proto.Message",
+		},
+		"wrong envelope": {
+			run: func() error {
+				env, _ := anypb.New(wrapperspb.String("x"))
+				_, err := ProtoToResults(env)
+				return err
+			},
+			errSubstr: "want graft.Results",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+// Package redis provides a graft.Cache backed by Redis, so that several
+// instances of a service can share cached node outputs.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grindlemire/graft"
+	"github.com/redis/go-redis/v9"
+)
+
+// Serializer converts node outputs to and from the bytes stored in Redis.
+// Unmarshal receives a pointer to a value of the output's type, as
+// json.Unmarshal does.
+type Serializer interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// jsonSerializer is the default [Serializer].
+type jsonSerializer struct{}
+
+func (jsonSerializer) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonSerializer) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// CacheOption configures [NewCache].
+type CacheOption func(*Cache)
+
+// WithTTL sets the expiry of every entry written. The default, 0, keeps
+// entries until they are deleted.
+//
+// Example:
+//
+//	cache := graftredis.NewCache(client, graftredis.WithTTL(10*time.Minute))
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithKeyPrefix sets the prefix of the Redis keys, so that several graphs
+// or environments can share a Redis database. The default is "graft:".
+//
+// Example:
+//
+//	cache := graftredis.NewCache(client, graftredis.WithKeyPrefix("billing:graft:"))
+func WithKeyPrefix(prefix string) CacheOption {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// WithSerializer replaces the encoding/json serialization of outputs.
+//
+// Example:
+//
+//	cache := graftredis.NewCache(client, graftredis.WithSerializer(msgpackSerializer{}))
+func WithSerializer(s Serializer) CacheOption {
+	return func(c *Cache) {
+		c.serializer = s
+	}
+}
+
+// Cache is a [graft.Cache] storing node outputs in Redis. It implements
+// [graft.MultiCache] with pipelines and [graft.HasCache] with EXISTS.
+// Create one with [NewCache].
+//
+// Each entry holds the serialized output together with the name of its Go
+// type, so that Get can return a value of the type the node produces. A
+// type is known once a value of it has been stored through this Cache, or
+// when a node in the global registry produces it. Named types are
+// identified by package path and name; unnamed types such as []string by
+// their Go syntax.
+type Cache struct {
+	client     redis.UniversalClient
+	prefix     string
+	ttl        time.Duration
+	serializer Serializer
+
+	types sync.Map // type name -> reflect.Type
+}
+
+var _ graft.MultiCache = (*Cache)(nil)
+var _ graft.HasCache = (*Cache)(nil)
+
+// NewCache returns a cache storing entries through client. It accepts a
+// *redis.Client as well as cluster and ring clients.
+//
+// Example:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	cache := graftredis.NewCache(client, graftredis.WithTTL(time.Hour))
+//	results, err := graft.Execute(ctx, graft.WithCache(cache))
+func NewCache(client redis.UniversalClient, opts ...CacheOption) *Cache {
+	c := &Cache{
+		client:     client,
+		prefix:     "graft:",
+		serializer: jsonSerializer{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get retrieves a value from Redis.
+func (c *Cache) Get(ctx context.Context, id graft.ID) (any, bool, error) {
+	data, err := c.client.Get(ctx, c.key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis get %s: %w", id, err)
+	}
+	v, err := c.decode(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode %s: %w", id, err)
+	}
+	return v, true, nil
+}
+
+// Has reports whether id has a cached value.
+func (c *Cache) Has(ctx context.Context, id graft.ID) (bool, error) {
+	n, err := c.client.Exists(ctx, c.key(id)).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis exists %s: %w", id, err)
+	}
+	return n > 0, nil
+}
+
+// Set stores a value in Redis.
+func (c *Cache) Set(ctx context.Context, id graft.ID, value any) error {
+	data, err := c.encode(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", id, err)
+	}
+	if err := c.client.Set(ctx, c.key(id), data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("redis set %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetMulti retrieves the values for ids with one pipelined round trip.
+// Only hits are present in the returned map.
+func (c *Cache) GetMulti(ctx context.Context, ids []graft.ID) (map[graft.ID]any, error) {
+	cmds := make([]*redis.StringCmd, len(ids))
+	_, err := c.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = p.Get(ctx, c.key(id))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	out := make(map[graft.ID]any, len(ids))
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redis get %s: %w", ids[i], err)
+		}
+		v, err := c.decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", ids[i], err)
+		}
+		out[ids[i]] = v
+	}
+	return out, nil
+}
+
+// SetMulti stores every entry with one pipelined round trip.
+func (c *Cache) SetMulti(ctx context.Context, entries map[graft.ID]any) error {
+	data := make(map[graft.ID][]byte, len(entries))
+	for id, v := range entries {
+		b, err := c.encode(v)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", id, err)
+		}
+		data[id] = b
+	}
+
+	_, err := c.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for id, b := range data {
+			p.Set(ctx, c.key(id), b, c.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the entries for ids.
+func (c *Cache) Delete(ctx context.Context, ids ...graft.ID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = c.key(id)
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+	return nil
+}
+
+// Snapshot returns every entry under the key prefix. It scans the whole
+// keyspace of the database, so use it for debugging rather than on hot
+// paths. Entries that cannot be read or decoded are left out.
+func (c *Cache) Snapshot() map[graft.ID]any {
+	ctx := context.Background()
+	var ids []graft.ID
+	iter := c.client.Scan(ctx, 0, escapeGlob(c.prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		ids = append(ids, graft.ID(strings.TrimPrefix(iter.Val(), c.prefix)))
+	}
+
+	out := map[graft.ID]any{}
+	for _, id := range ids {
+		if v, ok, err := c.Get(ctx, id); err == nil && ok {
+			out[id] = v
+		}
+	}
+	return out
+}
+
+func (c *Cache) key(id graft.ID) string {
+	return c.prefix + string(id)
+}
+
+// encode returns the stored form of v: its type name, a newline, and the
+// serialized value. A nil interface value is stored as an empty line.
+func (c *Cache) encode(v any) ([]byte, error) {
+	if v == nil {
+		return []byte("\n"), nil
+	}
+	typ := reflect.TypeOf(v)
+	name := typeName(typ)
+	c.types.Store(name, typ)
+
+	data, err := c.serializer.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(name+"\n"), data...), nil
+}
+
+// decode reverses encode.
+func (c *Cache) decode(stored []byte) (any, error) {
+	name, data, ok := strings.Cut(string(stored), "\n")
+	if !ok {
+		return nil, errors.New("missing type name")
+	}
+	if name == "" {
+		return nil, nil
+	}
+	typ, err := c.lookupType(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := reflect.New(typ)
+	if err := c.serializer.Unmarshal([]byte(data), ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// lookupType returns the type stored under name, learning the output types
+// of the global registry if it is not known yet.
+func (c *Cache) lookupType(name string) (reflect.Type, error) {
+	if typ, ok := c.types.Load(name); ok {
+		return typ.(reflect.Type), nil
+	}
+	for _, n := range graft.ListNodes() {
+		if typ, err := graft.NodeOutputType(n.ID); err == nil {
+			c.types.Store(typeName(typ), typ)
+		}
+	}
+	if typ, ok := c.types.Load(name); ok {
+		return typ.(reflect.Type), nil
+	}
+	return nil, fmt.Errorf("unknown type %s: no registered node produces it", name)
+}
+
+// typeName identifies typ across processes.
+func typeName(typ reflect.Type) string {
+	if typ.Name() != "" && typ.PkgPath() != "" {
+		return typ.PkgPath() + "." + typ.Name()
+	}
+	return typ.String()
+}
+
+// escapeGlob escapes the characters Redis SCAN patterns treat specially.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,273 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/grindlemire/graft"
+	"github.com/redis/go-redis/v9"
+)
+
+type configOutput struct {
+	Port int
+	Tags []string
+}
+
+type greeter interface{ Greet() string }
+
+type english struct{ Name string }
+
+func (e english) Greet() string { return "hello " + e.Name }
+
+func newCache(t *testing.T, opts ...CacheOption) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewCache(client, opts...), srv
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	type tc struct {
+		value any
+	}
+
+	tests := map[string]tc{
+		"struct":         {value: configOutput{Port: 8080, Tags: []string{"a"}}},
+		"pointer":        {value: &configOutput{Port: 1}},
+		"slice":          {value: []string{"x", "y"}},
+		"map":            {value: map[string]int{"a": 1}},
+		"imported type":  {value: url.URL{Scheme: "https", Host: "example.com"}},
+		"nil":            {value: nil},
+		"basic":          {value: 42},
+		"interface impl": {value: english{Name: "bob"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, _ := newCache(t)
+
+			if err := cache.Set(ctx, "node", tt.value); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			got, ok, err := cache.Get(ctx, "node")
+			if err != nil || !ok {
+				t.Fatalf("Get = (%v, %v, %v), want a hit", got, ok, err)
+			}
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("Get = %#v, want %#v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestCacheSharedBetweenInstances(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+	graft.Register(graft.Node[configOutput]{
+		ID:  "config",
+		Run: func(ctx context.Context) (configOutput, error) { return configOutput{}, nil },
+	})
+
+	ctx := context.Background()
+	writer, srv := newCache(t)
+	if err := writer.Set(ctx, "config", configOutput{Port: 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second instance has never stored a configOutput; it learns the type
+	// from the registry
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	reader := NewCache(client)
+	got, ok, err := reader.Get(ctx, "config")
+	if err != nil || !ok {
+		t.Fatalf("Get = (%v, %v, %v), want a hit", got, ok, err)
+	}
+	if !reflect.DeepEqual(got, configOutput{Port: 9}) {
+		t.Errorf("Get = %#v, want port 9", got)
+	}
+
+	if err := writer.Set(ctx, "other", english{Name: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = reader.Get(ctx, "other")
+	if err == nil || !strings.Contains(err.Error(), "unknown type github.com/grindlemire/graft/redis.english") {
+		t.Errorf("error = %v, want unknown type", err)
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	ctx := context.Background()
+	cache, srv := newCache(t, WithKeyPrefix("app:"), WithTTL(time.Minute))
+
+	if err := cache.Set(ctx, "config", 1); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.Exists("app:config") {
+		t.Fatalf("keys = %v, want app:config", srv.Keys())
+	}
+	if ttl := srv.TTL("app:config"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	srv.FastForward(2 * time.Minute)
+	if _, ok, err := cache.Get(ctx, "config"); ok || err != nil {
+		t.Errorf("Get after expiry = (%v, %v), want a miss", ok, err)
+	}
+}
+
+// countingSerializer is JSON with call counts.
+type countingSerializer struct {
+	marshals, unmarshals atomic.Int32
+}
+
+func (s *countingSerializer) Marshal(v any) ([]byte, error) {
+	s.marshals.Add(1)
+	return json.Marshal(v)
+}
+
+func (s *countingSerializer) Unmarshal(data []byte, v any) error {
+	s.unmarshals.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+func TestWithSerializer(t *testing.T) {
+	ctx := context.Background()
+	s := &countingSerializer{}
+	cache, _ := newCache(t, WithSerializer(s))
+
+	if err := cache.Set(ctx, "a", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cache.Get(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if s.marshals.Load() != 1 || s.unmarshals.Load() != 1 {
+		t.Errorf("marshals, unmarshals = %d, %d, want 1, 1", s.marshals.Load(), s.unmarshals.Load())
+	}
+}
+
+func TestCacheMulti(t *testing.T) {
+	ctx := context.Background()
+	cache, srv := newCache(t)
+
+	if err := cache.SetMulti(ctx, map[graft.ID]any{"a": 1, "b": "two"}); err != nil {
+		t.Fatalf("SetMulti: %v", err)
+	}
+	got, err := cache.GetMulti(ctx, []graft.ID{"a", "b", "missing"})
+	if err != nil {
+		t.Fatalf("GetMulti: %v", err)
+	}
+	want := map[graft.ID]any{"a": 1, "b": "two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMulti = %v, want %v", got, want)
+	}
+
+	srv.Set("graft:corrupt", "no type line")
+	if _, err := cache.GetMulti(ctx, []graft.ID{"a", "corrupt"}); err == nil || !strings.Contains(err.Error(), "decode corrupt") {
+		t.Errorf("GetMulti error = %v, want decode error", err)
+	}
+}
+
+func TestCacheHasDeleteSnapshot(t *testing.T) {
+	ctx := context.Background()
+	cache, srv := newCache(t)
+	srv.Set("unrelated", "x")
+
+	for id, v := range map[graft.ID]any{"a": 1, "b": 2, "c": 3} {
+		if err := cache.Set(ctx, id, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cache.Delete(ctx, "c"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	for id, want := range map[graft.ID]bool{"a": true, "c": false} {
+		has, err := graft.CacheHas(ctx, cache, id)
+		if err != nil || has != want {
+			t.Errorf("CacheHas(%s) = (%v, %v), want %v", id, has, err, want)
+		}
+	}
+	if got, want := cache.Snapshot(), map[graft.ID]any{"a": 1, "b": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot = %v, want %v", got, want)
+	}
+}
+
+func TestCacheWithExecute(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	var runs atomic.Int32
+	graft.Register(graft.Node[configOutput]{
+		ID:        "config",
+		Cacheable: true,
+		Run: func(ctx context.Context) (configOutput, error) {
+			runs.Add(1)
+			return configOutput{Port: 8080}, nil
+		},
+	})
+	graft.Register(graft.Node[greeter]{
+		ID:        "greeter",
+		Cacheable: true,
+		Run: func(ctx context.Context) (greeter, error) {
+			runs.Add(1)
+			return english{Name: "alice"}, nil
+		},
+	})
+	graft.Register(graft.Node[string]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config", "greeter"},
+		Run: func(ctx context.Context) (string, error) {
+			cfg, err := graft.Dep[configOutput](ctx)
+			if err != nil {
+				return "", err
+			}
+			g, err := graft.Dep[greeter](ctx)
+			if err != nil {
+				return "", err
+			}
+			return g.Greet() + " on " + strconv.Itoa(cfg.Port), nil
+		},
+	})
+
+	cache, _ := newCache(t)
+	for i := 0; i < 2; i++ {
+		app, _, err := graft.ExecuteFor[string](context.Background(), graft.WithCache(cache))
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		if app != "hello alice on 8080" {
+			t.Errorf("run %d: app = %q", i, app)
+		}
+	}
+	if runs.Load() != 2 {
+		t.Errorf("cacheable nodes ran %d times, want 2", runs.Load())
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	cache, srv := newCache(t)
+
+	if err := cache.Set(ctx, "fn", func() {}); err == nil || !strings.Contains(err.Error(), "encode fn") {
+		t.Errorf("Set error = %v, want encode error", err)
+	}
+
+	srv.Close()
+	_, _, err := cache.Get(ctx, "a")
+	if err == nil || errors.Is(err, redis.Nil) {
+		t.Errorf("Get error = %v, want a connection error", err)
+	}
+}
@@ -1,6 +1,12 @@
 package graft
 
-import "context"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
 
 // registry holds all registered nodes in type-erased form.
 // It is populated at init time by calls to Register.
@@ -10,6 +16,19 @@ var registry = make(map[ID]node)
 // This enables type-based ExecuteFor without reflection.
 var typeToID = make(map[any]ID)
 
+// registryMu guards registry and typeToID, which [Reload] can modify while
+// executions are running.
+var registryMu sync.RWMutex
+
+// idForType returns the ID of the node whose output type is identified by
+// sentinel, a typed nil pointer such as (*T)(nil).
+func idForType(sentinel any) (ID, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	id, ok := typeToID[sentinel]
+	return id, ok
+}
+
 // Register adds a typed node to the global registry.
 //
 // The type parameter is erased internally for heterogeneous storage.
@@ -17,8 +36,9 @@ var typeToID = make(map[any]ID)
 // all nodes are registered before main() runs. This pattern allows nodes
 // to be self-registering via blank imports.
 //
-// Panics if a node with the same ID is already registered. This catches
-// accidental ID collisions at startup.
+// Panics if the node has an empty ID, if a node with the same ID is already
+// registered, or if the node sets both Run and RunWithContext. This catches
+// forgotten IDs, accidental ID collisions and misconfigured nodes at startup.
 //
 // Example:
 //
@@ -46,24 +66,70 @@ var typeToID = make(map[any]ID)
 //
 //	import _ "myapp/nodes/config"
 func Register[T any](n Node[T]) {
+	if n.ID == "" {
+		panic("graft: node registered with empty ID")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	if _, exists := registry[n.ID]; exists {
 		panic("graft: duplicate node registration: " + string(n.ID))
 	}
 
 	// Type erasure: convert typed Node[T] to internal node with any
-	registry[n.ID] = node{
-		id:        n.ID,
-		dependsOn: n.DependsOn,
-		run: func(ctx context.Context) (any, error) {
-			return n.Run(ctx)
-		},
-		cacheable: n.Cacheable,
-	}
+	registry[n.ID] = eraseNode(n.ID, n)
 
 	// Record type → ID mapping using nil pointer sentinel
 	typeToID[(*T)(nil)] = n.ID
 }
 
+// Reload replaces a registered node in place, for development workflows
+// that update a node's Run function without restarting the process. The
+// node is matched by newNode.ID; if the output type changed, type lookups
+// for ExecuteFor and Dep follow the new type. The node's entry in the
+// default cache is invalidated and its circuit breaker state is cleared.
+//
+// Executions already in progress keep the node they started with, since
+// each execution works on its own copy of the registry.
+//
+// Reload is not meant for production use: nodes that depend on the reloaded
+// node are not re-validated, and other caches may still hold its old output.
+//
+// Returns an error if no node with newNode.ID is registered, or if another
+// node already produces T.
+//
+// Example:
+//
+//	err := graft.Reload(graft.Node[config.Output]{
+//	    ID:  config.ID,
+//	    Run: loadConfigFromDevServer,
+//	})
+func Reload[T any](newNode Node[T]) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[newNode.ID]; !exists {
+		return fmt.Errorf("graft: cannot reload unregistered node %q", newNode.ID)
+	}
+	if id, ok := typeToID[(*T)(nil)]; ok && id != newNode.ID {
+		return fmt.Errorf("graft: cannot reload node %q: output type already produced by node %q", newNode.ID, id)
+	}
+
+	registry[newNode.ID] = eraseNode(newNode.ID, newNode)
+
+	// Drop the previous type mapping in case the output type changed
+	for sentinel, id := range typeToID {
+		if id == newNode.ID {
+			delete(typeToID, sentinel)
+		}
+	}
+	typeToID[(*T)(nil)] = newNode.ID
+
+	defaultCache.Delete(newNode.ID)
+	circuitBreakers.reset(newNode.ID)
+	return nil
+}
+
 // Registry returns a copy of all registered nodes.
 //
 // The returned map is a copy; modifications do not affect the global registry.
@@ -74,6 +140,8 @@ func Register[T any](n Node[T]) {
 //	nodes := graft.Registry()
 //	fmt.Printf("Registered %d nodes\n", len(nodes))
 func Registry() map[ID]node {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	cp := make(map[ID]node, len(registry))
 	for k, v := range registry {
 		cp[k] = v
@@ -81,13 +149,221 @@ func Registry() map[ID]node {
 	return cp
 }
 
-// ResetRegistry clears the global registry.
-// This is primarily useful for test isolation.
+// NodeSummary describes a registered node without exposing its Run function.
+type NodeSummary struct {
+	ID          ID
+	DependsOn   []ID
+	Cacheable   bool
+	Description string
+	Tags        []string
+	Deprecated  string
+}
+
+// String returns a human-readable one-line summary of the node.
+//
+// Example output:
+//
+//	db deps=[config] cacheable tags=[storage] - Postgres connection pool
+func (s NodeSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s deps=%v", s.ID, s.DependsOn)
+	if s.Cacheable {
+		b.WriteString(" cacheable")
+	}
+	if len(s.Tags) > 0 {
+		fmt.Fprintf(&b, " tags=%v", s.Tags)
+	}
+	if s.Deprecated != "" {
+		b.WriteString(" deprecated")
+	}
+	if s.Description != "" {
+		fmt.Fprintf(&b, " - %s", s.Description)
+	}
+	return b.String()
+}
+
+// SortedIDs returns the IDs of the nodes in m in ascending order. Use it
+// wherever a registry is iterated and the order can be observed, since Go
+// map iteration order is random.
+//
+// Example:
+//
+//	for _, id := range graft.SortedIDs(graft.Registry()) {
+//	    fmt.Println(id)
+//	}
+func SortedIDs(m map[ID]node) []ID {
+	ids := make([]ID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ListNodes returns a summary of every registered node, sorted by ID.
+//
+// By default, lists the global registry. Use [WithRegistry] for a custom registry.
+//
+// This is useful for health check endpoints, production debugging, and
+// documentation generators.
+//
+// Example:
+//
+//	for _, n := range graft.ListNodes() {
+//	    fmt.Println(n)
+//	}
+func ListNodes(opts ...Option) []NodeSummary {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return nodeSummaries(cfg.registry)
+}
+
+// NodeOutputType returns the Go type of node id's output: the T of the
+// [Node] it was registered with. It returns an error if id is unknown or
+// was added without a type, for example through a raw [WithRegistry] map.
+//
+// Example:
+//
+//	typ, err := graft.NodeOutputType("config")
+//	fmt.Println(typ) // config.Output
+func NodeOutputType(id ID, opts ...Option) (reflect.Type, error) {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	n, ok := cfg.registry[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown node: %s", id)
+	}
+	if n.outputType == nil {
+		return nil, fmt.Errorf("node %s has no known output type", id)
+	}
+	return n.outputType, nil
+}
+
+// nodeSummaries describes nodes sorted by ID.
+func nodeSummaries(nodes map[ID]node) []NodeSummary {
+	summaries := make([]NodeSummary, 0, len(nodes))
+	for _, id := range SortedIDs(nodes) {
+		n := nodes[id]
+		summaries = append(summaries, NodeSummary{
+			ID:          id,
+			DependsOn:   append([]ID{}, n.dependsOn...),
+			Cacheable:   n.cacheable,
+			Description: n.description,
+			Tags:        append([]string{}, n.tags...),
+			Deprecated:  n.deprecated,
+		})
+	}
+
+	return summaries
+}
+
+// RegistryIssueType classifies a problem found by [ValidateRegistryConsistency].
+type RegistryIssueType string
+
+const (
+	// IssueEmptyID is reported for nodes registered with an empty ID.
+	IssueEmptyID RegistryIssueType = "empty_id"
+	// IssueUnknownDependency is reported when DependsOn names an ID that
+	// is not registered.
+	IssueUnknownDependency RegistryIssueType = "unknown_dependency"
+	// IssueSelfDependency is reported when a node lists itself in DependsOn.
+	IssueSelfDependency RegistryIssueType = "self_dependency"
+	// IssueDuplicateDependency is reported when DependsOn lists the same ID
+	// more than once.
+	IssueDuplicateDependency RegistryIssueType = "duplicate_dependency"
+)
+
+// RegistryIssue describes a structural problem with a registered node.
+type RegistryIssue struct {
+	NodeID    ID
+	IssueType RegistryIssueType
+	Message   string
+}
+
+// String returns the issue formatted as "nodeID: [type] message".
+func (i RegistryIssue) String() string {
+	return fmt.Sprintf("%s: [%s] %s", i.NodeID, i.IssueType, i.Message)
+}
+
+// ValidateRegistryConsistency checks the registry for structural problems
+// that would otherwise only surface at execution time: empty node IDs,
+// dependencies on unregistered nodes, self-dependencies, and duplicate
+// entries in DependsOn. Issues are sorted by node ID.
+//
+// By default, validates the global registry. Use [WithRegistry] for a custom registry.
+//
+// Call it from main() after all init() functions have run:
+//
+//	if issues := graft.ValidateRegistryConsistency(); len(issues) > 0 {
+//	    for _, issue := range issues {
+//	        log.Println(issue)
+//	    }
+//	    log.Fatal("graft: invalid node registry")
+//	}
+func ValidateRegistryConsistency(opts ...Option) []RegistryIssue {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var issues []RegistryIssue
+	for _, id := range SortedIDs(cfg.registry) {
+		n := cfg.registry[id]
+		if id == "" {
+			issues = append(issues, RegistryIssue{
+				NodeID:    id,
+				IssueType: IssueEmptyID,
+				Message:   "node registered with an empty ID",
+			})
+		}
+
+		seen := make(map[ID]bool, len(n.dependsOn))
+		for _, dep := range n.dependsOn {
+			_, known := cfg.registry[dep]
+			switch {
+			case seen[dep]:
+				issues = append(issues, RegistryIssue{
+					NodeID:    id,
+					IssueType: IssueDuplicateDependency,
+					Message:   fmt.Sprintf("dependency %q is listed more than once", dep),
+				})
+				continue
+			case dep == id:
+				issues = append(issues, RegistryIssue{
+					NodeID:    id,
+					IssueType: IssueSelfDependency,
+					Message:   "node depends on itself",
+				})
+			case !known:
+				issues = append(issues, RegistryIssue{
+					NodeID:    id,
+					IssueType: IssueUnknownDependency,
+					Message:   fmt.Sprintf("depends on unknown node %q", dep),
+				})
+			}
+			seen[dep] = true
+		}
+	}
+
+	return issues
+}
+
+// ResetRegistry clears the global registry and the circuit breaker state
+// of every node. This is primarily useful for test isolation.
 func ResetRegistry() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	for k := range registry {
 		delete(registry, k)
 	}
 	for k := range typeToID {
 		delete(typeToID, k)
 	}
+	circuitBreakers.clear()
 }
@@ -2,6 +2,7 @@ package graft
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -343,3 +344,395 @@ func TestTestIsolation(t *testing.T) {
 		t.Fatal("expected empty cache after resetGlobalState")
 	}
 }
+
+func TestSortedIDs(t *testing.T) {
+	type tc struct {
+		nodes map[ID]node
+		want  []ID
+	}
+
+	tests := map[string]tc{
+		"empty": {
+			nodes: map[ID]node{},
+			want:  []ID{},
+		},
+		"sorted": {
+			nodes: map[ID]node{"db": {id: "db"}, "api": {id: "api"}, "config": {id: "config"}},
+			want:  []ID{"api", "config", "db"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SortedIDs(tt.nodes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortedIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListNodes(t *testing.T) {
+	type tc struct {
+		registerNodes func()
+		opts          []Option
+		want          []NodeSummary
+	}
+
+	tests := map[string]tc{
+		"empty registry": {
+			registerNodes: func() {},
+			want:          []NodeSummary{},
+		},
+		"sorted with metadata": {
+			registerNodes: func() {
+				Register(Node[int]{
+					ID:          "db",
+					DependsOn:   []ID{"config"},
+					Cacheable:   true,
+					Description: "connection pool",
+					Tags:        []string{"storage"},
+					Deprecated:  "use pgx",
+					Run:         func(ctx context.Context) (int, error) { return 0, nil },
+				})
+				Register(Node[string]{
+					ID:  "config",
+					Run: func(ctx context.Context) (string, error) { return "", nil },
+				})
+			},
+			want: []NodeSummary{
+				{ID: "config", DependsOn: []ID{}, Tags: []string{}},
+				{
+					ID:          "db",
+					DependsOn:   []ID{"config"},
+					Cacheable:   true,
+					Description: "connection pool",
+					Tags:        []string{"storage"},
+					Deprecated:  "use pgx",
+				},
+			},
+		},
+		"custom registry": {
+			registerNodes: func() {},
+			opts: []Option{WithRegistry(map[ID]node{
+				"b": {id: "b", dependsOn: []ID{"a"}},
+				"a": {id: "a"},
+			})},
+			want: []NodeSummary{
+				{ID: "a", DependsOn: []ID{}, Tags: []string{}},
+				{ID: "b", DependsOn: []ID{"a"}, Tags: []string{}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetGlobalState()
+			defer resetGlobalState()
+			tt.registerNodes()
+
+			got := ListNodes(tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListNodes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeOutputType(t *testing.T) {
+	type output struct{ N int }
+
+	type tc struct {
+		id      ID
+		opts    []Option
+		want    reflect.Type
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"registered node": {
+			id:   "typed",
+			want: reflect.TypeOf(output{}),
+		},
+		"pointer output": {
+			id:   "pointer",
+			want: reflect.TypeOf(&output{}),
+		},
+		"unknown node": {
+			id:      "missing",
+			wantErr: "unknown node: missing",
+		},
+		"untyped node": {
+			id:      "raw",
+			opts:    []Option{WithRegistry(map[ID]node{"raw": {id: "raw"}})},
+			wantErr: "node raw has no known output type",
+		},
+	}
+
+	resetGlobalState()
+	defer resetGlobalState()
+	Register(Node[output]{ID: "typed", Run: func(ctx context.Context) (output, error) { return output{}, nil }})
+	Register(Node[*output]{ID: "pointer", Run: func(ctx context.Context) (*output, error) { return nil, nil }})
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NodeOutputType(tt.id, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NodeOutputType = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeSummaryString(t *testing.T) {
+	type tc struct {
+		summary NodeSummary
+		want    string
+	}
+
+	tests := map[string]tc{
+		"minimal": {
+			summary: NodeSummary{ID: "config"},
+			want:    "config deps=[]",
+		},
+		"all fields": {
+			summary: NodeSummary{
+				ID:          "db",
+				DependsOn:   []ID{"config"},
+				Cacheable:   true,
+				Description: "connection pool",
+				Tags:        []string{"storage", "startup"},
+			},
+			want: "db deps=[config] cacheable tags=[storage startup] - connection pool",
+		},
+		"deprecated": {
+			summary: NodeSummary{ID: "db", Deprecated: "use pgx"},
+			want:    "db deps=[] deprecated",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.summary.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRegistryConsistency(t *testing.T) {
+	type tc struct {
+		nodes map[ID]node
+		want  []RegistryIssue
+	}
+
+	tests := map[string]tc{
+		"valid registry": {
+			nodes: map[ID]node{
+				"config": {id: "config"},
+				"db":     {id: "db", dependsOn: []ID{"config"}},
+			},
+			want: nil,
+		},
+		"empty ID": {
+			nodes: map[ID]node{
+				"": {id: ""},
+			},
+			want: []RegistryIssue{
+				{NodeID: "", IssueType: IssueEmptyID, Message: "node registered with an empty ID"},
+			},
+		},
+		"unknown dependency": {
+			nodes: map[ID]node{
+				"db": {id: "db", dependsOn: []ID{"config"}},
+			},
+			want: []RegistryIssue{
+				{NodeID: "db", IssueType: IssueUnknownDependency, Message: `depends on unknown node "config"`},
+			},
+		},
+		"self dependency": {
+			nodes: map[ID]node{
+				"a": {id: "a", dependsOn: []ID{"a"}},
+			},
+			want: []RegistryIssue{
+				{NodeID: "a", IssueType: IssueSelfDependency, Message: "node depends on itself"},
+			},
+		},
+		"duplicate dependency reported once per repeat": {
+			nodes: map[ID]node{
+				"config": {id: "config"},
+				"db":     {id: "db", dependsOn: []ID{"config", "config"}},
+			},
+			want: []RegistryIssue{
+				{NodeID: "db", IssueType: IssueDuplicateDependency, Message: `dependency "config" is listed more than once`},
+			},
+		},
+		"issues sorted by node ID": {
+			nodes: map[ID]node{
+				"b": {id: "b", dependsOn: []ID{"x"}},
+				"a": {id: "a", dependsOn: []ID{"y"}},
+			},
+			want: []RegistryIssue{
+				{NodeID: "a", IssueType: IssueUnknownDependency, Message: `depends on unknown node "y"`},
+				{NodeID: "b", IssueType: IssueUnknownDependency, Message: `depends on unknown node "x"`},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateRegistryConsistency(WithRegistry(tt.nodes))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateRegistryConsistency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterEmptyIDPanics(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic on empty ID, got none")
+		}
+		if msg, _ := r.(string); msg != "graft: node registered with empty ID" {
+			t.Errorf("unexpected panic message: %v", r)
+		}
+	}()
+
+	Register(Node[string]{
+		Run: func(ctx context.Context) (string, error) { return "", nil },
+	})
+}
+
+func TestRegisterRunAndRunWithContextPanics(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic when both Run and RunWithContext are set")
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, "both Run and RunWithContext") {
+			t.Errorf("unexpected panic message: %v", r)
+		}
+	}()
+
+	Register(Node[string]{
+		ID:  "both",
+		Run: func(ctx context.Context) (string, error) { return "", nil },
+		RunWithContext: func(ctx context.Context, cancel context.CancelFunc) (string, error) {
+			return "", nil
+		},
+	})
+}
+
+type reloadTestConfig struct{ Env string }
+type reloadTestConfigV2 struct{ Env string }
+type reloadTestOther struct{}
+
+func TestReload(t *testing.T) {
+	type tc struct {
+		reload    func() error
+		errSubstr string
+		check     func(t *testing.T)
+	}
+
+	tests := map[string]tc{
+		"replaces run": {
+			reload: func() error {
+				return Reload(Node[reloadTestConfig]{
+					ID:  "config",
+					Run: func(ctx context.Context) (reloadTestConfig, error) { return reloadTestConfig{Env: "reloaded"}, nil },
+				})
+			},
+			check: func(t *testing.T) {
+				cfg, _, err := ExecuteFor[reloadTestConfig](context.Background())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if cfg.Env != "reloaded" {
+					t.Errorf("Env = %q, want reloaded", cfg.Env)
+				}
+			},
+		},
+		"changes output type": {
+			reload: func() error {
+				return Reload(Node[reloadTestConfigV2]{
+					ID:  "config",
+					Run: func(ctx context.Context) (reloadTestConfigV2, error) { return reloadTestConfigV2{Env: "v2"}, nil },
+				})
+			},
+			check: func(t *testing.T) {
+				if _, _, err := ExecuteFor[reloadTestConfig](context.Background()); err == nil {
+					t.Error("expected old output type to be unregistered")
+				}
+				cfg, _, err := ExecuteFor[reloadTestConfigV2](context.Background())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if cfg.Env != "v2" {
+					t.Errorf("Env = %q, want v2", cfg.Env)
+				}
+			},
+		},
+		"unregistered node": {
+			reload: func() error {
+				return Reload(Node[reloadTestConfig]{ID: "missing"})
+			},
+			errSubstr: `cannot reload unregistered node "missing"`,
+		},
+		"output type produced by another node": {
+			reload: func() error {
+				return Reload(Node[reloadTestOther]{ID: "config"})
+			},
+			errSubstr: `output type already produced by node "other"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetGlobalState()
+			t.Cleanup(resetGlobalState)
+
+			Register(Node[reloadTestConfig]{
+				ID:        "config",
+				Cacheable: true,
+				Run:       func(ctx context.Context) (reloadTestConfig, error) { return reloadTestConfig{Env: "original"}, nil },
+			})
+			Register(Node[reloadTestOther]{
+				ID:  "other",
+				Run: func(ctx context.Context) (reloadTestOther, error) { return reloadTestOther{}, nil },
+			})
+
+			// Populate the default cache so that Reload has to invalidate it
+			if _, _, err := ExecuteFor[reloadTestConfig](context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := tt.reload()
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package graft
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// ExecuteN runs the graph n times in sequence and returns the results of
+// every run. Each run is an independent [Execute] call with the same opts,
+// so cacheable nodes are served from the cache after the first run. Stats
+// passed with [WithStats] describe the last completed run; use
+// [OnNodeComplete] or [WithPostExecuteHook] to time each run.
+//
+// If a run fails, the results of the runs before it are returned together
+// with the error.
+//
+// Example:
+//
+//	runs, err := graft.ExecuteN(ctx, 100, graft.DisableCache())
+func ExecuteN(ctx context.Context, n int, opts ...Option) ([]Results, error) {
+	runs := make([]Results, 0, n)
+	for i := 0; i < n; i++ {
+		results, err := Execute(ctx, opts...)
+		if err != nil {
+			return runs, fmt.Errorf("run %d: %w", i+1, err)
+		}
+		runs = append(runs, results)
+	}
+	return runs, nil
+}
+
+// BenchmarkExecute runs the graph b.N times, failing the benchmark on the
+// first error. The timer is reset before the first run.
+//
+// Example:
+//
+//	func BenchmarkGraph(b *testing.B) {
+//	    graft.BenchmarkExecute(b, graft.DisableCache())
+//	}
+func BenchmarkExecute(b *testing.B, opts ...Option) {
+	b.Helper()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Execute(ctx, opts...); err != nil {
+			b.Fatalf("graft.BenchmarkExecute: run %d: %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExecuteN(t *testing.T) {
+	type tc struct {
+		n         int
+		failOn    int64
+		cacheable bool
+		wantRuns  int
+		wantCalls int64
+		wantErr   bool
+	}
+
+	tests := map[string]tc{
+		"zero runs": {
+			n: 0,
+		},
+		"uncached runs every time": {
+			n:         3,
+			wantRuns:  3,
+			wantCalls: 3,
+		},
+		"cacheable node runs once": {
+			n:         3,
+			cacheable: true,
+			wantRuns:  3,
+			wantCalls: 1,
+		},
+		"stops at first error": {
+			n:         3,
+			failOn:    2,
+			wantRuns:  1,
+			wantCalls: 2,
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int64
+			n := makeNode("a", nil, func(ctx context.Context) (any, error) {
+				if calls.Add(1) == tt.failOn {
+					return nil, errors.New("boom")
+				}
+				return "ok", nil
+			})
+			n.cacheable = tt.cacheable
+
+			runs, err := ExecuteN(context.Background(), tt.n,
+				WithRegistry(map[ID]node{"a": n}), WithCache(NewMemoryCache()))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(runs) != tt.wantRuns {
+				t.Errorf("got %d runs, want %d", len(runs), tt.wantRuns)
+			}
+			for i, r := range runs {
+				if r["a"] != "ok" {
+					t.Errorf("run %d: a = %v, want ok", i, r["a"])
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Run called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func BenchmarkExecuteChain(b *testing.B) {
+	run := func(ctx context.Context) (any, error) { return nil, nil }
+	nodes := map[ID]node{
+		"a": makeNode("a", nil, run),
+		"b": makeNode("b", []ID{"a"}, run),
+		"c": makeNode("c", []ID{"b"}, run),
+	}
+	BenchmarkExecute(b, WithRegistry(nodes), DisableCache())
+}
@@ -0,0 +1,92 @@
+package graft
+
+// replay holds the previous run seeded into an execution by [WithReplay].
+type replay struct {
+	previous Results
+	dirty    []ID
+}
+
+// WithReplay seeds the execution with the results of a previous run and
+// re-executes only the dirty nodes and the nodes that transitively depend on
+// them. Every other node resolves to its output in previous without running.
+//
+// Nodes missing from previous are treated as dirty. Dirty nodes bypass the
+// cache, since their cached output is exactly what is being replaced.
+//
+// Example:
+//
+//	before, _ := graft.Execute(ctx)
+//
+//	// Re-run config and everything downstream of it, reusing the rest
+//	after, err := graft.Execute(ctx, graft.WithReplay(before, "config"))
+func WithReplay(previous Results, dirty ...ID) Option {
+	return func(c *config) {
+		c.replay = &replay{previous: previous, dirty: dirty}
+	}
+}
+
+// dirtySet returns the nodes that must re-execute: the dirty nodes, nodes
+// with no previous output, and everything that transitively depends on them.
+func (r *replay) dirtySet(nodes map[ID]node) map[ID]bool {
+	dependents := make(map[ID][]ID)
+	for _, n := range nodes {
+		for _, dep := range n.dependsOn {
+			dependents[dep] = append(dependents[dep], n.id)
+		}
+	}
+
+	var stack []ID
+	for _, id := range r.dirty {
+		if _, ok := nodes[id]; ok {
+			stack = append(stack, id)
+		}
+	}
+	for id := range nodes {
+		if _, ok := r.previous[id]; !ok {
+			stack = append(stack, id)
+		}
+	}
+
+	dirty := make(map[ID]bool)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if dirty[id] {
+			continue
+		}
+		dirty[id] = true
+		stack = append(stack, dependents[id]...)
+	}
+	return dirty
+}
+
+// seedReplay stores the previous output of every clean node and returns the
+// levels with only the dirty nodes left to execute.
+func (e *engine) seedReplay(levels [][]ID) [][]ID {
+	dirty := e.replay.dirtySet(e.nodes)
+
+	ignore := make(map[ID]bool, len(e.ignoreCacheFor)+len(dirty))
+	for id, v := range e.ignoreCacheFor {
+		ignore[id] = v
+	}
+	for id := range dirty {
+		ignore[id] = true
+	}
+	e.ignoreCacheFor = ignore
+
+	var remaining [][]ID
+	for _, level := range levels {
+		var run []ID
+		for _, id := range level {
+			if dirty[id] {
+				run = append(run, id)
+				continue
+			}
+			e.results[id] = e.replay.previous[id]
+		}
+		if len(run) > 0 {
+			remaining = append(remaining, run)
+		}
+	}
+	return remaining
+}
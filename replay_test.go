@@ -0,0 +1,123 @@
+package graft
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestWithReplay(t *testing.T) {
+	type tc struct {
+		previous Results
+		dirty    []ID
+		wantRan  []ID
+	}
+
+	// config -> db -> app, config -> cache
+	previous := Results{"config": "old-config", "db": "old-db", "cache": "old-cache", "app": "old-app"}
+
+	tests := map[string]tc{
+		"nothing dirty": {
+			previous: previous,
+			wantRan:  []ID{},
+		},
+		"dirty root re-runs all dependents": {
+			previous: previous,
+			dirty:    []ID{"config"},
+			wantRan:  []ID{"app", "cache", "config", "db"},
+		},
+		"dirty middle node": {
+			previous: previous,
+			dirty:    []ID{"db"},
+			wantRan:  []ID{"app", "db"},
+		},
+		"dirty leaf": {
+			previous: previous,
+			dirty:    []ID{"cache"},
+			wantRan:  []ID{"cache"},
+		},
+		"missing previous output is dirty": {
+			previous: Results{"config": "old-config", "cache": "old-cache", "app": "old-app"},
+			wantRan:  []ID{"app", "db"},
+		},
+		"unknown dirty ID is ignored": {
+			previous: previous,
+			dirty:    []ID{"missing"},
+			wantRan:  []ID{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			ran := []ID{}
+			run := func(id ID) func(ctx context.Context) (any, error) {
+				return func(ctx context.Context) (any, error) {
+					mu.Lock()
+					ran = append(ran, id)
+					mu.Unlock()
+					return "new-" + string(id), nil
+				}
+			}
+			nodes := map[ID]node{
+				"config": makeNode("config", nil, run("config")),
+				"db":     makeNode("db", []ID{"config"}, run("db")),
+				"cache":  makeNode("cache", []ID{"config"}, run("cache")),
+				"app":    makeNode("app", []ID{"db"}, run("app")),
+			}
+
+			results, err := Execute(context.Background(),
+				WithRegistry(nodes),
+				WithReplay(tt.previous, tt.dirty...),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sort.Slice(ran, func(i, j int) bool { return ran[i] < ran[j] })
+			if !reflect.DeepEqual(ran, tt.wantRan) {
+				t.Errorf("ran = %v, want %v", ran, tt.wantRan)
+			}
+
+			reran := make(map[ID]bool)
+			for _, id := range tt.wantRan {
+				reran[id] = true
+			}
+			for id := range nodes {
+				want := tt.previous[id]
+				if reran[id] {
+					want = "new-" + string(id)
+				}
+				if results[id] != want {
+					t.Errorf("results[%s] = %v, want %v", id, results[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithReplayBypassesCacheForDirtyNodes(t *testing.T) {
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: func(ctx context.Context) (any, error) {
+			return "fresh", nil
+		}},
+	}
+	cache := NewMemoryCache()
+	if err := cache.Set(context.Background(), "config", "stale"); err != nil {
+		t.Fatalf("cache set: %v", err)
+	}
+
+	results, err := Execute(context.Background(),
+		WithRegistry(nodes),
+		WithCache(cache),
+		WithReplay(Results{"config": "previous"}, "config"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results["config"] != "fresh" {
+		t.Errorf("results[config] = %v, want fresh", results["config"])
+	}
+}
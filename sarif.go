@@ -0,0 +1,175 @@
+package graft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+// SARIF rule IDs reported by [AnalyzeDirSARIF].
+const (
+	// SARIFRuleUndeclaredDep is reported for each dependency a node uses via
+	// Dep[T] without listing it in DependsOn.
+	SARIFRuleUndeclaredDep = "graft/undeclared-dep"
+	// SARIFRuleUnusedDep is reported for each dependency a node lists in
+	// DependsOn but never uses.
+	SARIFRuleUnusedDep = "graft/unused-dep"
+	// SARIFRuleDependencyCycle is reported for each cycle a node participates in.
+	SARIFRuleDependencyCycle = "graft/dependency-cycle"
+)
+
+// sarifRules describes every rule in the tool.driver.rules section.
+var sarifRules = []sarifRule{
+	{ID: SARIFRuleUndeclaredDep, ShortDescription: sarifMessage{Text: "Dependency used but not declared in DependsOn"}},
+	{ID: SARIFRuleUnusedDep, ShortDescription: sarifMessage{Text: "Dependency declared in DependsOn but never used"}},
+	{ID: SARIFRuleDependencyCycle, ShortDescription: sarifMessage{Text: "Node participates in a dependency cycle"}},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// AnalyzeDirSARIF runs [AnalyzeDir] and reports the issues as a SARIF 2.1.0
+// document, suitable for uploading to GitHub Code Scanning. Each undeclared
+// dependency, unused dependency and cycle becomes one SARIF result pointing
+// at the node's graft.Register call.
+//
+// File paths are made relative to the working directory so that running the
+// analysis from the repository root produces repository-relative locations.
+//
+// Example:
+//
+//	data, err := graft.AnalyzeDirSARIF("./nodes")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("graft.sarif", data, 0o644)
+func AnalyzeDirSARIF(dir string) ([]byte, error) {
+	results, err := AnalyzeDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return sarifReport(results, root)
+}
+
+// sarifReport converts analysis results into a SARIF document with file
+// locations relative to root.
+func sarifReport(results []typeaware.Result, root string) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "graft",
+			InformationURI: "https://github.com/grindlemire/graft",
+			Rules:          sarifRules,
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, r := range results {
+		loc := sarifNodeLocation(r, root)
+		for _, dep := range r.Undeclared {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    SARIFRuleUndeclaredDep,
+				Level:     "error",
+				Message:   sarifMessage{Text: fmt.Sprintf("node %q uses dependency %q via graft.Dep but does not declare it in DependsOn", r.NodeID, dep)},
+				Locations: []sarifLocation{loc},
+			})
+		}
+		for _, dep := range r.Unused {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    SARIFRuleUnusedDep,
+				Level:     "warning",
+				Message:   sarifMessage{Text: fmt.Sprintf("node %q declares dependency %q in DependsOn but never uses it", r.NodeID, dep)},
+				Locations: []sarifLocation{loc},
+			})
+		}
+		for _, cycle := range r.Cycles {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    SARIFRuleDependencyCycle,
+				Level:     "error",
+				Message:   sarifMessage{Text: fmt.Sprintf("node %q is part of dependency cycle %s", r.NodeID, strings.Join(cycle, " → "))},
+				Locations: []sarifLocation{loc},
+			})
+		}
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+// sarifNodeLocation returns the location of a node's registration. Files
+// under root are reported relative to %SRCROOT%; others use a file URI.
+func sarifNodeLocation(r typeaware.Result, root string) sarifLocation {
+	artifact := sarifArtifactLocation{URI: "file://" + filepath.ToSlash(r.File)}
+	if rel, err := filepath.Rel(root, r.File); err == nil && !strings.HasPrefix(rel, "..") {
+		artifact = sarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: "%SRCROOT%"}
+	}
+
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact}}
+	if r.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: r.Line}
+	}
+	return loc
+}
@@ -0,0 +1,111 @@
+package graft
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/graft/internal/typeaware"
+)
+
+func TestSARIFReport(t *testing.T) {
+	type wantResult struct {
+		ruleID string
+		level  string
+		uri    string
+		line   int
+	}
+
+	type tc struct {
+		results []typeaware.Result
+		want    []wantResult
+	}
+
+	tests := map[string]tc{
+		"no issues": {
+			results: []typeaware.Result{{NodeID: "config", File: "/repo/nodes/config.go", Line: 10}},
+			want:    []wantResult{},
+		},
+		"one result per issue": {
+			results: []typeaware.Result{
+				{
+					NodeID:     "app",
+					File:       "/repo/nodes/app.go",
+					Line:       12,
+					Undeclared: []string{"db"},
+					Unused:     []string{"cache", "config"},
+				},
+				{
+					NodeID: "a",
+					File:   "/repo/nodes/a.go",
+					Line:   7,
+					Cycles: [][]string{{"a", "b", "a"}},
+				},
+			},
+			want: []wantResult{
+				{ruleID: SARIFRuleUndeclaredDep, level: "error", uri: "nodes/app.go", line: 12},
+				{ruleID: SARIFRuleUnusedDep, level: "warning", uri: "nodes/app.go", line: 12},
+				{ruleID: SARIFRuleUnusedDep, level: "warning", uri: "nodes/app.go", line: 12},
+				{ruleID: SARIFRuleDependencyCycle, level: "error", uri: "nodes/a.go", line: 7},
+			},
+		},
+		"file outside root": {
+			results: []typeaware.Result{{NodeID: "x", File: "/elsewhere/x.go", Unused: []string{"y"}}},
+			want: []wantResult{
+				{ruleID: SARIFRuleUnusedDep, level: "warning", uri: "file:///elsewhere/x.go"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := sarifReport(tt.results, "/repo")
+			if err != nil {
+				t.Fatalf("sarifReport error: %v", err)
+			}
+
+			var doc sarifLog
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if doc.Version != "2.1.0" || len(doc.Runs) != 1 {
+				t.Fatalf("unexpected document header: version=%q runs=%d", doc.Version, len(doc.Runs))
+			}
+			if len(doc.Runs[0].Tool.Driver.Rules) != len(sarifRules) {
+				t.Errorf("expected %d rules, got %d", len(sarifRules), len(doc.Runs[0].Tool.Driver.Rules))
+			}
+
+			got := []wantResult{}
+			for _, r := range doc.Runs[0].Results {
+				loc := r.Locations[0].PhysicalLocation
+				w := wantResult{ruleID: r.RuleID, level: r.Level, uri: loc.ArtifactLocation.URI}
+				if loc.Region != nil {
+					w.line = loc.Region.StartLine
+				}
+				if r.Message.Text == "" {
+					t.Errorf("result %s has no message", r.RuleID)
+				}
+				got = append(got, w)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSARIFReportEmptyResultsArray(t *testing.T) {
+	data, err := sarifReport(nil, "/repo")
+	if err != nil {
+		t.Fatalf("sarifReport error: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	run := raw["runs"].([]any)[0].(map[string]any)
+	if _, ok := run["results"].([]any); !ok {
+		t.Errorf("results must be an array, got %T", run["results"])
+	}
+}
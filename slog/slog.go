@@ -0,0 +1,62 @@
+// Package slog logs graft node lifecycle events with the standard library's
+// log/slog package.
+//
+// Every record uses the same structured keys: node_id, level, duration_ms,
+// cache_hit and error. The graft/zerolog module emits the same records
+// through github.com/rs/zerolog.
+package slog
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grindlemire/graft"
+)
+
+// Structured keys used in every record.
+const (
+	KeyNodeID     = "node_id"
+	KeyLevel      = "level"
+	KeyDurationMS = "duration_ms"
+	KeyCacheHit   = "cache_hit"
+	KeyError      = "error"
+)
+
+// WithLogger logs each node as it runs: a Debug "starting" record before
+// the node is resolved, then an Info "completed" record with its duration
+// and whether it was served from the cache, or an Error "failed" record
+// that also carries the error.
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	results, err := graft.Execute(ctx, graftslog.WithLogger(logger))
+func WithLogger(log *slog.Logger) graft.Option {
+	// Cache hits are reported before completion; remember them so the
+	// completion record can include cache_hit
+	var hits sync.Map // graft.ID -> struct{}
+
+	return graft.WithHooks(graft.Hooks{
+		OnStart: func(id graft.ID, level int) {
+			log.Debug("starting", KeyNodeID, string(id), KeyLevel, level)
+		},
+		OnCacheHit: func(id graft.ID) {
+			hits.Store(id, struct{}{})
+		},
+		OnComplete: func(id graft.ID, level int, d time.Duration, err error) {
+			_, hit := hits.LoadAndDelete(id)
+			attrs := []any{
+				KeyNodeID, string(id),
+				KeyLevel, level,
+				KeyDurationMS, d.Milliseconds(),
+				KeyCacheHit, hit,
+			}
+			if err != nil {
+				log.Error("failed", append(attrs, KeyError, err)...)
+				return
+			}
+			log.Info("completed", attrs...)
+		},
+	})
+}
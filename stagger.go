@@ -0,0 +1,59 @@
+package graft
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// WithStagger spaces out the starts of the nodes within a level: each node
+// is launched d after the previous one, so the i-th node of a level starts
+// after i*d. Use it when the nodes of a level call the same external
+// service and should not hit it all at once.
+//
+// Nodes still run concurrently; only their starts are delayed. The delay
+// is skipped if the context is done.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.WithStagger(50*time.Millisecond))
+func WithStagger(d time.Duration) Option {
+	return func(c *config) {
+		c.stagger = func() time.Duration { return d }
+	}
+}
+
+// WithRandomStagger is like [WithStagger] with a random delay in [0, max)
+// before each launch, chosen independently per node.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx, graft.WithRandomStagger(100*time.Millisecond))
+func WithRandomStagger(max time.Duration) Option {
+	return func(c *config) {
+		c.stagger = func() time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return rand.N(max)
+		}
+	}
+}
+
+// staggerLaunch waits before launching a level's next node, or returns the
+// context's error if it is done first.
+func (e *engine) staggerLaunch(ctx context.Context) error {
+	d := e.stagger()
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+package graft
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithStagger(t *testing.T) {
+	type tc struct {
+		opt        Option
+		minSpacing time.Duration
+		maxTotal   time.Duration
+	}
+
+	const nodeRun = 100 * time.Millisecond
+
+	tests := map[string]tc{
+		"fixed stagger spaces starts": {
+			opt:        WithStagger(40 * time.Millisecond),
+			minSpacing: 35 * time.Millisecond,
+			maxTotal:   2*nodeRun + 80*time.Millisecond, // still overlapping
+		},
+		"random stagger keeps nodes concurrent": {
+			opt:      WithRandomStagger(20 * time.Millisecond),
+			maxTotal: 2*nodeRun + 40*time.Millisecond,
+		},
+		"zero stagger": {
+			opt:      WithStagger(0),
+			maxTotal: 2 * nodeRun,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var starts []time.Time
+			run := func(ctx context.Context) (any, error) {
+				mu.Lock()
+				starts = append(starts, time.Now())
+				mu.Unlock()
+				time.Sleep(nodeRun)
+				return nil, nil
+			}
+			nodes := map[ID]node{
+				"a": makeNode("a", nil, run),
+				"b": makeNode("b", nil, run),
+				"c": makeNode("c", nil, run),
+			}
+
+			begin := time.Now()
+			if _, err := Execute(context.Background(), WithRegistry(nodes), DisableCache(), tt.opt); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if total := time.Since(begin); total > tt.maxTotal {
+				t.Errorf("execution took %v, want at most %v", total, tt.maxTotal)
+			}
+
+			sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+			for i := 1; i < len(starts); i++ {
+				if gap := starts[i].Sub(starts[i-1]); gap < tt.minSpacing {
+					t.Errorf("start %d followed the previous one after %v, want at least %v", i, gap, tt.minSpacing)
+				}
+			}
+		})
+	}
+}
+
+func TestWithStaggerContextCancelled(t *testing.T) {
+	nodes := map[ID]node{
+		"a": makeNode("a", nil, func(ctx context.Context) (any, error) { return nil, nil }),
+		"b": makeNode("b", nil, func(ctx context.Context) (any, error) { return nil, nil }),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	_, err := Execute(ctx, WithRegistry(nodes), DisableCache(), WithStagger(time.Hour))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(begin); d > time.Second {
+		t.Errorf("cancelled execution took %v", d)
+	}
+}
@@ -0,0 +1,124 @@
+package graft
+
+import (
+	"sort"
+	"time"
+)
+
+// ExecutionStats records timing information from a single execution.
+// Populate it by passing a pointer to [WithStats].
+type ExecutionStats struct {
+	// Total is the wall-clock duration of the whole execution.
+	Total time.Duration
+
+	// Nodes holds per-node statistics for every node that completed.
+	Nodes map[ID]NodeStats
+
+	// FallbackErrors holds the original error of every node that resolved
+	// to its [WithFallback] value instead.
+	FallbackErrors map[ID]error
+}
+
+// NodeStats records how a single node was resolved during execution.
+type NodeStats struct {
+	// Duration is how long the node's Run function took. It is zero for
+	// cache hits.
+	Duration time.Duration
+
+	// CacheHit is true if the output was served from the cache.
+	CacheHit bool
+
+	// DependsOn lists the node's declared dependencies.
+	DependsOn []ID
+}
+
+// WithStats records execution timing into stats. Any previous contents of
+// stats are replaced when execution starts.
+//
+// Example:
+//
+//	var stats graft.ExecutionStats
+//	results, err := graft.Execute(ctx, graft.WithStats(&stats))
+//	path, total := stats.CriticalPath()
+//	fmt.Printf("critical path %v took %s\n", path, total)
+func WithStats(stats *ExecutionStats) Option {
+	return func(c *config) {
+		c.stats = stats
+	}
+}
+
+// CriticalPath returns the chain of dependent nodes with the greatest
+// combined duration, ordered from the root dependency to the final node,
+// along with that combined duration. This is the path that bounds the
+// execution time regardless of parallelism.
+//
+// Ties are broken by node ID for deterministic output.
+func (s ExecutionStats) CriticalPath() ([]ID, time.Duration) {
+	if len(s.Nodes) == 0 {
+		return nil, 0
+	}
+
+	// finish[id] is the longest combined duration of any chain ending at id
+	finish := make(map[ID]time.Duration, len(s.Nodes))
+	prev := make(map[ID]ID, len(s.Nodes))
+	var visit func(id ID) time.Duration
+	visit = func(id ID) time.Duration {
+		if d, ok := finish[id]; ok {
+			return d
+		}
+		ns := s.Nodes[id]
+
+		deps := append([]ID{}, ns.DependsOn...)
+		sort.Slice(deps, func(i, j int) bool { return deps[i] < deps[j] })
+
+		var longest time.Duration
+		for _, dep := range deps {
+			if _, ok := s.Nodes[dep]; !ok {
+				continue
+			}
+			if d := visit(dep); d > longest || prev[id] == "" {
+				longest = d
+				prev[id] = dep
+			}
+		}
+
+		finish[id] = longest + ns.Duration
+		return finish[id]
+	}
+
+	ids := make([]ID, 0, len(s.Nodes))
+	for id := range s.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var end ID
+	var total time.Duration
+	for i, id := range ids {
+		if d := visit(id); i == 0 || d > total {
+			end, total = id, d
+		}
+	}
+
+	var path []ID
+	for id := end; id != ""; id = prev[id] {
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, total
+}
+
+// recordStats stores a node's statistics. Callers must hold e.mu.
+func (e *engine) recordStats(id ID, d time.Duration, cacheHit bool) {
+	if e.stats == nil {
+		return
+	}
+	e.stats.Nodes[id] = NodeStats{
+		Duration:  d,
+		CacheHit:  cacheHit,
+		DependsOn: append([]ID{}, e.nodes[id].dependsOn...),
+	}
+}
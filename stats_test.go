@@ -0,0 +1,105 @@
+package graft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithStatsOption(t *testing.T) {
+	cache := NewMemoryCache()
+	_ = cache.Set(context.Background(), "config", "cached")
+
+	nodes := map[ID]node{
+		"config": {id: "config", cacheable: true, run: func(ctx context.Context) (any, error) { return "fresh", nil }},
+		"db": makeNode("db", []ID{"config"}, func(ctx context.Context) (any, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "db", nil
+		}),
+	}
+
+	var stats ExecutionStats
+	if _, err := Execute(context.Background(), WithRegistry(nodes), WithCache(cache), WithStats(&stats)); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	if len(stats.Nodes) != 2 {
+		t.Fatalf("got %d node stats, want 2", len(stats.Nodes))
+	}
+	if cfg := stats.Nodes["config"]; !cfg.CacheHit || cfg.Duration != 0 {
+		t.Errorf("config stats = %+v, want cache hit with zero duration", cfg)
+	}
+	db := stats.Nodes["db"]
+	if db.CacheHit || db.Duration < 5*time.Millisecond {
+		t.Errorf("db stats = %+v, want executed with duration >= 5ms", db)
+	}
+	if !reflect.DeepEqual(db.DependsOn, []ID{"config"}) {
+		t.Errorf("db DependsOn = %v, want [config]", db.DependsOn)
+	}
+	if stats.Total < db.Duration {
+		t.Errorf("Total = %v, want >= %v", stats.Total, db.Duration)
+	}
+}
+
+func TestExecutionStatsCriticalPath(t *testing.T) {
+	type tc struct {
+		nodes     map[ID]NodeStats
+		wantPath  []ID
+		wantTotal time.Duration
+	}
+
+	ms := time.Millisecond
+	tests := map[string]tc{
+		"empty": {
+			nodes: nil,
+		},
+		"linear chain": {
+			nodes: map[ID]NodeStats{
+				"a": {Duration: 10 * ms},
+				"b": {Duration: 20 * ms, DependsOn: []ID{"a"}},
+				"c": {Duration: 5 * ms, DependsOn: []ID{"b"}},
+			},
+			wantPath:  []ID{"a", "b", "c"},
+			wantTotal: 35 * ms,
+		},
+		"diamond takes slower branch": {
+			nodes: map[ID]NodeStats{
+				"config": {Duration: 10 * ms},
+				"cache":  {Duration: 5 * ms, DependsOn: []ID{"config"}},
+				"db":     {Duration: 50 * ms, DependsOn: []ID{"config"}},
+				"app":    {Duration: 10 * ms, DependsOn: []ID{"cache", "db"}},
+			},
+			wantPath:  []ID{"config", "db", "app"},
+			wantTotal: 70 * ms,
+		},
+		"independent slow node": {
+			nodes: map[ID]NodeStats{
+				"a":    {Duration: 10 * ms},
+				"b":    {Duration: 10 * ms, DependsOn: []ID{"a"}},
+				"slow": {Duration: 100 * ms},
+			},
+			wantPath:  []ID{"slow"},
+			wantTotal: 100 * ms,
+		},
+		"ties broken by ID": {
+			nodes: map[ID]NodeStats{
+				"a": {},
+				"b": {},
+			},
+			wantPath: []ID{"a"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, total := ExecutionStats{Nodes: tt.nodes}.CriticalPath()
+			if !reflect.DeepEqual(path, tt.wantPath) {
+				t.Errorf("path = %v, want %v", path, tt.wantPath)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
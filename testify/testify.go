@@ -0,0 +1,67 @@
+// Package testify checks graft execution results with the testify assert
+// and require packages.
+package testify
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertResultEqual extracts the output of type T from results with
+// [graft.Result] and asserts that it equals expected, printing a diff on
+// mismatch. It returns whether the assertion succeeded.
+//
+// Example:
+//
+//	results, err := graft.Execute(ctx)
+//	require.NoError(t, err)
+//	testify.AssertResultEqual(t, results, config.Output{Port: 8080})
+func AssertResultEqual[T any](t testing.TB, results graft.Results, expected T, msgAndArgs ...any) bool {
+	t.Helper()
+
+	actual, err := graft.Result[T](results)
+	if !assert.NoError(t, err, msgAndArgs...) {
+		return false
+	}
+	return assert.Equal(t, expected, actual, msgAndArgs...)
+}
+
+// RequireResultEqual is like [AssertResultEqual] but stops the test on
+// failure.
+//
+// Example:
+//
+//	testify.RequireResultEqual(t, results, config.Output{Port: 8080})
+func RequireResultEqual[T any](t testing.TB, results graft.Results, expected T, msgAndArgs ...any) {
+	t.Helper()
+
+	actual, err := graft.Result[T](results)
+	require.NoError(t, err, msgAndArgs...)
+	require.Equal(t, expected, actual, msgAndArgs...)
+}
+
+// AssertResultMatch extracts the output of type T from results and asserts
+// that matcher returns true for it. On failure msg is reported along with
+// the output. It returns whether the assertion succeeded.
+//
+// Example:
+//
+//	testify.AssertResultMatch(t, results, func(db db.Output) bool {
+//	    return db.Pool != nil
+//	}, "db pool should be initialized")
+func AssertResultMatch[T any](t testing.TB, results graft.Results, matcher func(T) bool, msg string) bool {
+	t.Helper()
+
+	actual, err := graft.Result[T](results)
+	if !assert.NoError(t, err, msg) {
+		return false
+	}
+	if !matcher(actual) {
+		return assert.Fail(t, msg, fmt.Sprintf("result %T did not match: %#v", actual, actual))
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package testify
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type configOutput struct {
+	Port int
+}
+
+type unregisteredOutput struct{}
+
+// recordT records failures instead of failing the test.
+type recordT struct {
+	testing.TB
+	errors []string
+	failed bool
+}
+
+func (r *recordT) Helper() {}
+
+func (r *recordT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordT) FailNow() {
+	r.failed = true
+	runtime.Goexit()
+}
+
+func executeConfig(t *testing.T) graft.Results {
+	t.Helper()
+
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[configOutput]{
+		ID: "config",
+		Run: func(ctx context.Context) (configOutput, error) {
+			return configOutput{Port: 8080}, nil
+		},
+	})
+
+	results, err := graft.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return results
+}
+
+func TestAssertResultEqual(t *testing.T) {
+	type tc struct {
+		check  func(t *recordT, results graft.Results) bool
+		wantOK bool
+	}
+
+	tests := map[string]tc{
+		"equal": {
+			check: func(t *recordT, results graft.Results) bool {
+				return AssertResultEqual(t, results, configOutput{Port: 8080})
+			},
+			wantOK: true,
+		},
+		"not equal": {
+			check: func(t *recordT, results graft.Results) bool {
+				return AssertResultEqual(t, results, configOutput{Port: 9090})
+			},
+		},
+		"unregistered type": {
+			check: func(t *recordT, results graft.Results) bool {
+				return AssertResultEqual(t, results, unregisteredOutput{})
+			},
+		},
+		"match": {
+			check: func(t *recordT, results graft.Results) bool {
+				return AssertResultMatch(t, results, func(c configOutput) bool { return c.Port > 0 }, "port set")
+			},
+			wantOK: true,
+		},
+		"no match": {
+			check: func(t *recordT, results graft.Results) bool {
+				return AssertResultMatch(t, results, func(c configOutput) bool { return c.Port == 0 }, "port unset")
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := executeConfig(t)
+
+			rt := &recordT{TB: t}
+			ok := tt.check(rt, results)
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.wantOK == (len(rt.errors) > 0) {
+				t.Errorf("errors = %v, want errors: %v", rt.errors, !tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRequireResultEqual(t *testing.T) {
+	type tc struct {
+		expected   configOutput
+		wantFailed bool
+	}
+
+	tests := map[string]tc{
+		"equal":     {expected: configOutput{Port: 8080}},
+		"not equal": {expected: configOutput{Port: 9090}, wantFailed: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := executeConfig(t)
+
+			// FailNow exits the goroutine, so run the check in its own
+			rt := &recordT{TB: t}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				RequireResultEqual(rt, results, tt.expected)
+			}()
+			<-done
+
+			if rt.failed != tt.wantFailed {
+				t.Errorf("failed = %v, want %v (errors: %v)", rt.failed, tt.wantFailed, rt.errors)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package graft
 
 import (
+	"fmt"
+	"go/token"
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/grindlemire/graft/internal/typeaware"
@@ -11,6 +15,14 @@ import (
 type AssertOpts struct {
 	Verbose bool // prints node summaries (DeclaredDeps, UsedDeps, Status)
 	Debug   bool // prints AST-level tracing (file walking, composite literals, etc.)
+
+	NoUnreachable         bool // fails if any node is registered but never depended on or executed
+	WarnAsError           bool // fails on analysis warnings instead of logging them
+	ForbidNestedExecution bool // fails if any node calls graft.Execute or graft.ExecuteFor in Run
+
+	MinDeps   int        // fails if any node declares fewer deps; 0 means no minimum
+	MaxDeps   int        // fails if any node declares more deps; 0 means no maximum
+	ExactDeps map[ID]int // fails if a node does not declare exactly this many deps
 }
 
 // AssertOption is a functional option for configuring AssertDepsValid.
@@ -28,6 +40,63 @@ func WithDebugTesting() AssertOption {
 	return func(o *AssertOpts) { o.Debug = true }
 }
 
+// WithAssertNoUnreachable fails the assertion if any node is unreachable:
+// no other node depends on it and the analyzed code never executes it via
+// graft.ExecuteFor[T] or graft.Execute.
+func WithAssertNoUnreachable() AssertOption {
+	return func(o *AssertOpts) { o.NoUnreachable = true }
+}
+
+// WithWarnAsError promotes analysis warnings (see [AnalysisResult.Warnings])
+// to test failures. By default warnings are only logged.
+func WithWarnAsError() AssertOption {
+	return func(o *AssertOpts) { o.WarnAsError = true }
+}
+
+// WithForbidNestedExecution fails the assertion if any node's Run function
+// calls graft.Execute or graft.ExecuteFor[T]. Nested executions build a
+// sub-graph whose dependencies the analyzer cannot attribute to the node.
+func WithForbidNestedExecution() AssertOption {
+	return func(o *AssertOpts) { o.ForbidNestedExecution = true }
+}
+
+// WithMinDeps fails the assertion if any node declares fewer than n
+// dependencies in DependsOn.
+//
+// Example:
+//
+//	graft.AssertDepsValid(t, ".", graft.WithMinDeps(1))
+func WithMinDeps(n int) AssertOption {
+	return func(o *AssertOpts) { o.MinDeps = n }
+}
+
+// WithMaxDeps fails the assertion if any node declares more than n
+// dependencies in DependsOn. Use it to keep nodes from accumulating
+// dependencies until they do everything.
+//
+// Example:
+//
+//	graft.AssertDepsValid(t, ".", graft.WithMaxDeps(5))
+func WithMaxDeps(n int) AssertOption {
+	return func(o *AssertOpts) { o.MaxDeps = n }
+}
+
+// WithExactDeps fails the assertion unless the node id declares exactly
+// count dependencies in DependsOn, or if no node id is found. It can be
+// passed several times for different nodes.
+//
+// Example:
+//
+//	graft.AssertDepsValid(t, ".", graft.WithExactDeps("api", 3))
+func WithExactDeps(id ID, count int) AssertOption {
+	return func(o *AssertOpts) {
+		if o.ExactDeps == nil {
+			o.ExactDeps = make(map[ID]int)
+		}
+		o.ExactDeps[id] = count
+	}
+}
+
 // AssertDepsValid is a test helper that validates all graft.Node dependency
 // declarations in the specified directory match their actual usage.
 //
@@ -39,6 +108,8 @@ func WithDebugTesting() AssertOption {
 //   - Any node uses Dep[T](ctx) without declaring the corresponding dependency in DependsOn
 //   - Any node declares a dependency in DependsOn but never uses it
 //
+// Analysis warnings are logged but only fail the test with [WithWarnAsError].
+//
 // Basic usage in your test file:
 //
 //	func TestNodeDependencies(t *testing.T) {
@@ -60,9 +131,32 @@ func WithDebugTesting() AssertOption {
 // Example failure output:
 //
 //	graft.AssertDepsValid: db (nodes/db/db.go): undeclared deps: [cache]
-//	  → node "db" uses Dep[cache.Output](ctx) but does not declare "cache" in DependsOn
+//	  → nodes/db/db.go:42:10: node "db" uses Dep[cache.Output](ctx) but does not declare "cache" in DependsOn
 func AssertDepsValid(t testing.TB, dir string, opts ...AssertOption) {
 	t.Helper()
+	assertDepsValid(t, "graft.AssertDepsValid", dir, AnalyzeOptions{}, opts)
+}
+
+// AssertDepsValidStrict is like [AssertDepsValid] but also analyzes the
+// _test.go files in dir, so undeclared dependencies in nodes registered by
+// tests fail the test too. Node[T] values passed to graft.Patch[T] are
+// checked as overrides: a mock must declare every dependency it uses, but
+// may ignore dependencies it declares.
+//
+// Example:
+//
+//	func TestNodeDependencies(t *testing.T) {
+//	    graft.AssertDepsValidStrict(t, ".")
+//	}
+func AssertDepsValidStrict(t testing.TB, dir string, opts ...AssertOption) {
+	t.Helper()
+	assertDepsValid(t, "graft.AssertDepsValidStrict", dir, AnalyzeOptions{IncludeTests: true}, opts)
+}
+
+// assertDepsValid implements AssertDepsValid and AssertDepsValidStrict,
+// prefixing every message with name.
+func assertDepsValid(t testing.TB, name, dir string, analyzeOpts AnalyzeOptions, opts []AssertOption) {
+	t.Helper()
 
 	cfg := &AssertOpts{}
 	for _, opt := range opts {
@@ -77,14 +171,14 @@ func AssertDepsValid(t testing.TB, dir string, opts ...AssertOption) {
 		}()
 	}
 
-	results, err := AnalyzeDir(dir)
+	results, err := AnalyzeDirWithOptions(dir, analyzeOpts)
 	if err != nil {
-		t.Fatalf("graft.AssertDepsValid: failed to analyze directory %q: %v", dir, err)
+		t.Fatalf("%s: failed to analyze directory %q: %v", name, dir, err)
 	}
 
 	// Verbose output: show each node's dependency summary
 	if cfg.Verbose {
-		t.Logf("graft.AssertDepsValid: analyzing %q - found %d node(s)", dir, len(results))
+		t.Logf("%s: analyzing %q - found %d node(s)", name, dir, len(results))
 		for _, r := range results {
 			sortedDeclared := make([]string, len(r.DeclaredDeps))
 			copy(sortedDeclared, r.DeclaredDeps)
@@ -115,26 +209,117 @@ func AssertDepsValid(t testing.TB, dir string, opts ...AssertOption) {
 		}
 
 		failed = true
-		t.Errorf("graft.AssertDepsValid: %s", r.String())
+		t.Errorf("%s: %s", name, r.String())
 
 		// Provide detailed breakdown
 		if len(r.Undeclared) > 0 {
 			for _, dep := range r.Undeclared {
-				t.Errorf("  → node %q uses Dep[%s.Output](ctx) but does not declare %q in DependsOn", r.NodeID, dep, dep)
+				t.Errorf("  → %snode %q uses Dep[%s.Output](ctx) but does not declare %q in DependsOn", positionPrefix(r.UsedDepPositions, dep), r.NodeID, dep, dep)
 			}
 		}
 		if len(r.Unused) > 0 {
 			for _, dep := range r.Unused {
-				t.Errorf("  → node %q declares %q in DependsOn but never uses it", r.NodeID, dep)
+				t.Errorf("  → %snode %q declares %q in DependsOn but never uses it", positionPrefix(r.DeclaredDepPositions, dep), r.NodeID, dep)
+			}
+		}
+	}
+
+	if cfg.NoUnreachable {
+		for _, r := range results {
+			if !r.IsUnreachable {
+				continue
+			}
+
+			failed = true
+			t.Errorf("%s: %s (%s): unreachable node", name, r.NodeID, r.File)
+			t.Errorf("  → no node depends on %q and it is never executed via ExecuteFor or Execute", r.NodeID)
+		}
+	}
+
+	if cfg.ForbidNestedExecution {
+		for _, r := range results {
+			if !r.HasNestedExecution {
+				continue
+			}
+
+			failed = true
+			t.Errorf("%s: %s (%s): nested execution", name, r.NodeID, r.File)
+			t.Errorf("  → node %q calls graft.Execute or graft.ExecuteFor inside Run; its dependencies are untracked", r.NodeID)
+		}
+	}
+
+	if cfg.MinDeps > 0 || cfg.MaxDeps > 0 || len(cfg.ExactDeps) > 0 {
+		if assertDepCounts(t, name, cfg, results) {
+			failed = true
+		}
+	}
+
+	for _, r := range results {
+		for _, w := range r.Warnings {
+			if cfg.WarnAsError {
+				failed = true
+				t.Errorf("%s: %s (%s): warning: %s", name, r.NodeID, r.File, w)
+			} else {
+				t.Logf("%s: %s (%s): warning: %s", name, r.NodeID, r.File, w)
 			}
 		}
 	}
 
 	if !failed && len(results) > 0 && !cfg.Verbose {
-		t.Logf("graft.AssertDepsValid: validated %d node(s) - all dependencies correct", len(results))
+		t.Logf("%s: validated %d node(s) - all dependencies correct", name, len(results))
 	}
 }
 
+// assertDepCounts reports nodes whose number of declared dependencies
+// violates the MinDeps, MaxDeps or ExactDeps limits, and returns true if
+// any did.
+func assertDepCounts(t testing.TB, name string, cfg *AssertOpts, results []typeaware.Result) bool {
+	t.Helper()
+
+	var failed bool
+	found := make(map[ID]bool)
+	for _, r := range results {
+		n := len(r.DeclaredDeps)
+		if cfg.MinDeps > 0 && n < cfg.MinDeps {
+			failed = true
+			t.Errorf("%s: %s (%s): declares %d dep(s), fewer than the minimum of %d", name, r.NodeID, r.File, n, cfg.MinDeps)
+		}
+		if cfg.MaxDeps > 0 && n > cfg.MaxDeps {
+			failed = true
+			t.Errorf("%s: %s (%s): declares %d dep(s), more than the maximum of %d", name, r.NodeID, r.File, n, cfg.MaxDeps)
+		}
+		if want, ok := cfg.ExactDeps[ID(r.NodeID)]; ok {
+			found[ID(r.NodeID)] = true
+			if n != want {
+				failed = true
+				t.Errorf("%s: %s (%s): declares %d dep(s), want exactly %d", name, r.NodeID, r.File, n, want)
+			}
+		}
+	}
+
+	var missing []ID
+	for id := range cfg.ExactDeps {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	for _, id := range sortedIDs(missing) {
+		failed = true
+		t.Errorf("%s: %s: node not found for exact dep count", name, id)
+	}
+
+	return failed
+}
+
+// positionPrefix returns "file:line:col: " for dep's position, or "" if it
+// is unknown.
+func positionPrefix(positions map[string]token.Position, dep string) string {
+	if pos, ok := positions[dep]; ok {
+		return pos.String() + ": "
+	}
+	return ""
+}
+
 // CheckDepsValid is like [AssertDepsValid] but returns results instead of failing.
 //
 // This is useful for custom validation logic, reporting, or CI integration
@@ -154,3 +339,226 @@ func AssertDepsValid(t testing.TB, dir string, opts ...AssertOption) {
 func CheckDepsValid(dir string) ([]typeaware.Result, error) {
 	return AnalyzeDir(dir)
 }
+
+// AssertRegistryValid is a test helper that fails the test for every issue
+// reported by [ValidateRegistryConsistency].
+//
+// Example:
+//
+//	func TestRegistry(t *testing.T) {
+//	    graft.AssertRegistryValid(t)
+//	}
+//
+// Example failure output:
+//
+//	graft.AssertRegistryValid: db: [unknown_dependency] depends on unknown node "cache"
+func AssertRegistryValid(t testing.TB, opts ...Option) {
+	t.Helper()
+
+	for _, issue := range ValidateRegistryConsistency(opts...) {
+		t.Errorf("graft.AssertRegistryValid: %s", issue)
+	}
+}
+
+// AssertGraphAcyclic is a test helper that fails the test if the dependency
+// graph contains a cycle or a dependency on an unregistered node. Unlike
+// [AssertDepsValid], it inspects the registry directly and needs no source
+// analysis, so it is cheap enough to run in every test suite.
+//
+// By default, checks the global registry. Use [WithRegistry] for a custom registry.
+//
+// Example:
+//
+//	func TestGraph(t *testing.T) {
+//	    graft.AssertGraphAcyclic(t)
+//	}
+//
+// Example failure output:
+//
+//	graft.AssertGraphAcyclic: cycle detected: api -> auth -> api
+func AssertGraphAcyclic(t testing.TB, opts ...Option) {
+	t.Helper()
+
+	if err := CheckGraphAcyclic(opts...); err != nil {
+		t.Fatalf("graft.AssertGraphAcyclic: %v", err)
+	}
+}
+
+// CheckGraphAcyclic is like [AssertGraphAcyclic] but returns the problem as
+// an error instead of failing a test.
+//
+// Example:
+//
+//	if err := graft.CheckGraphAcyclic(); err != nil {
+//	    log.Fatal(err)
+//	}
+func CheckGraphAcyclic(opts ...Option) error {
+	cfg := &config{registry: Registry()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	for _, id := range SortedIDs(cfg.registry) {
+		for _, dep := range cfg.registry[id].dependsOn {
+			if _, ok := cfg.registry[dep]; !ok {
+				return fmt.Errorf("node %s depends on unknown node %s", id, dep)
+			}
+		}
+	}
+
+	if cycle := findCycle(cfg.registry); cycle != nil {
+		path := make([]string, len(cycle))
+		for i, id := range cycle {
+			path[i] = string(id)
+		}
+		return fmt.Errorf("cycle detected: %s", strings.Join(path, " -> "))
+	}
+
+	return nil
+}
+
+// AssertNodeExists is a test helper that fails the test if no node with
+// output type T is registered. Use it to catch a node package whose import
+// (and therefore its init registration) was accidentally removed.
+//
+// Example:
+//
+//	import _ "myapp/nodes/db"
+//
+//	func TestNodesRegistered(t *testing.T) {
+//	    graft.AssertNodeExists[db.Output](t)
+//	}
+func AssertNodeExists[T any](t testing.TB) {
+	t.Helper()
+
+	if _, ok := registeredNode[T](); !ok {
+		t.Fatalf("graft.AssertNodeExists: no node registered for type %s", typeName[T]())
+	}
+}
+
+// AssertNodeID is like [AssertNodeExists] but also fails the test if the
+// node registered for output type T does not have ID wantID.
+//
+// Example:
+//
+//	graft.AssertNodeID[db.Output](t, "db")
+func AssertNodeID[T any](t testing.TB, wantID ID) {
+	t.Helper()
+
+	n, ok := registeredNode[T]()
+	if !ok {
+		t.Fatalf("graft.AssertNodeID: no node registered for type %s", typeName[T]())
+		return
+	}
+	if n.id != wantID {
+		t.Fatalf("graft.AssertNodeID: node for type %s has ID %q, want %q", typeName[T](), n.id, wantID)
+	}
+}
+
+// AssertNodeDependsOn is like [AssertNodeExists] but also fails the test
+// unless the node registered for output type T declares exactly deps in
+// DependsOn. Order is ignored.
+//
+// Example:
+//
+//	graft.AssertNodeDependsOn[api.Output](t, "config", "db")
+func AssertNodeDependsOn[T any](t testing.TB, deps ...ID) {
+	t.Helper()
+
+	n, ok := registeredNode[T]()
+	if !ok {
+		t.Fatalf("graft.AssertNodeDependsOn: no node registered for type %s", typeName[T]())
+		return
+	}
+
+	got := sortedIDs(n.dependsOn)
+	want := sortedIDs(deps)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("graft.AssertNodeDependsOn: node %s depends on %v, want %v", n.id, got, want)
+	}
+}
+
+// CompareResults compares two sets of results node by node. Values are
+// equal if they are [reflect.DeepEqual] or, failing that, format the same
+// with %v (so pointers to equal values and values holding funcs compare
+// equal). diffs maps each differing node ID to "want X got Y", where a
+// node present in only one map is shown as <missing>.
+//
+// Example:
+//
+//	before, _ := graft.Execute(ctx)
+//	after, _ := graft.Execute(ctx, graft.PatchValue(config.Output{Debug: true}))
+//	if equal, diffs := graft.CompareResults(before, after); !equal {
+//	    fmt.Println(diffs)
+//	}
+func CompareResults(a, b map[ID]any) (equal bool, diffs map[ID]string) {
+	diffs = make(map[ID]string)
+	for id, want := range a {
+		got, ok := b[id]
+		if !ok {
+			diffs[id] = fmt.Sprintf("want %v got <missing>", want)
+			continue
+		}
+		if !resultValuesEqual(want, got) {
+			diffs[id] = fmt.Sprintf("want %v got %v", want, got)
+		}
+	}
+	for id, got := range b {
+		if _, ok := a[id]; !ok {
+			diffs[id] = fmt.Sprintf("want <missing> got %v", got)
+		}
+	}
+	return len(diffs) == 0, diffs
+}
+
+// resultValuesEqual reports whether two node outputs are equal for
+// [CompareResults].
+func resultValuesEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b) || fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// AssertResultsEqual is a test helper that fails the test once for every
+// node whose output differs between want and got, as reported by
+// [CompareResults].
+//
+// Example:
+//
+//	func TestPipeline(t *testing.T) {
+//	    got, _ := graft.Execute(ctx)
+//	    graft.AssertResultsEqual(t, graft.Results{"config": config.Output{Env: "test"}}, got)
+//	}
+//
+// Example failure output:
+//
+//	graft.AssertResultsEqual: config: want {test} got {prod}
+func AssertResultsEqual(t testing.TB, want, got map[ID]any) {
+	t.Helper()
+
+	_, diffs := CompareResults(want, got)
+	ids := make([]ID, 0, len(diffs))
+	for id := range diffs {
+		ids = append(ids, id)
+	}
+	for _, id := range sortedIDs(ids) {
+		t.Errorf("graft.AssertResultsEqual: %s: %s", id, diffs[id])
+	}
+}
+
+// registeredNode returns the globally registered node whose output type is T.
+func registeredNode[T any]() (node, bool) {
+	id, ok := idForType((*T)(nil))
+	if !ok {
+		return node{}, false
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	n, ok := registry[id]
+	return n, ok
+}
+
+// sortedIDs returns a sorted copy of ids.
+func sortedIDs(ids []ID) []ID {
+	out := append([]ID{}, ids...)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
@@ -1,8 +1,10 @@
 package graft
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -46,11 +48,14 @@ func setupTestModule(t *testing.T, files map[string]string) string {
 		t.Fatalf("failed to get absolute path: %v", err)
 	}
 
-	// Create go.mod file with replace directive to use local graft package
-	goMod := `module testmodule
-
-go 1.21
-
+	// Create go.mod file with replace directive to use local graft package.
+	// It copies graft's own go directive, requirements and go.sum so that
+	// the module loads with the default -mod=readonly, as it does in CI.
+	rootMod, err := os.ReadFile(filepath.Join(graftPath, "go.mod"))
+	if err != nil {
+		t.Fatalf("failed to read go.mod: %v", err)
+	}
+	goMod := strings.Replace(string(rootMod), "module github.com/grindlemire/graft\n", "module testmodule\n", 1) + `
 require github.com/grindlemire/graft v0.0.0-00010101000000-000000000000
 
 replace github.com/grindlemire/graft => ` + graftPath + `
@@ -58,6 +63,13 @@ replace github.com/grindlemire/graft => ` + graftPath + `
 	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644); err != nil {
 		t.Fatalf("failed to write go.mod: %v", err)
 	}
+	goSum, err := os.ReadFile(filepath.Join(graftPath, "go.sum"))
+	if err != nil {
+		t.Fatalf("failed to read go.sum: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.sum"), goSum, 0644); err != nil {
+		t.Fatalf("failed to write go.sum: %v", err)
+	}
 
 	// Write all provided files
 	for filename, content := range files {
@@ -292,3 +304,666 @@ func TestAssertDepsValidVerboseWithIssues(t *testing.T) {
 		t.Errorf("expected detailed error messages about unused deps, got: %v", mock.errors)
 	}
 }
+
+func TestWithAssertNoUnreachableOption(t *testing.T) {
+	opts := &AssertOpts{}
+	opt := WithAssertNoUnreachable()
+	opt(opts)
+
+	if !opts.NoUnreachable {
+		t.Error("WithAssertNoUnreachable should set NoUnreachable to true")
+	}
+}
+
+func TestAssertDepsValidNoUnreachable(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type Orphan struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[Orphan]{
+		ID:  "orphan",
+		Run: func(ctx context.Context) (Orphan, error) { return Orphan{}, nil },
+	})
+}
+
+func main() {
+	_, _, _ = graft.ExecuteFor[Config](context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	// Without the option unreachable nodes are not an error
+	mock := &mockT{}
+	AssertDepsValid(mock, tmpDir)
+	if len(mock.errors) > 0 {
+		t.Errorf("expected no errors without option, got %v", mock.errors)
+	}
+
+	mock = &mockT{}
+	AssertDepsValid(mock, tmpDir, WithAssertNoUnreachable())
+	foundUnreachable := false
+	for _, err := range mock.errors {
+		if strings.Contains(err, "unreachable") {
+			foundUnreachable = true
+			break
+		}
+	}
+	if !foundUnreachable {
+		t.Errorf("expected unreachable error, got: %v", mock.errors)
+	}
+}
+
+func TestWithWarnAsErrorOption(t *testing.T) {
+	opts := &AssertOpts{}
+	opt := WithWarnAsError()
+	opt(opts)
+
+	if !opts.WarnAsError {
+		t.Error("WithWarnAsError should set WarnAsError to true")
+	}
+}
+
+func TestAssertDepsValidWarnAsError(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:        "config",
+		Cacheable: true,
+		Run:       func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+}
+
+func main() {
+	_, _, _ = graft.ExecuteFor[Config](context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+	if len(results) != 1 || !results[0].HasWarnings() {
+		t.Fatalf("expected a warning for cacheable node without deps, got %+v", results)
+	}
+
+	// Without the option warnings are logged, not errors
+	mock := &mockT{}
+	AssertDepsValid(mock, tmpDir)
+	if len(mock.errors) > 0 {
+		t.Errorf("expected no errors without option, got %v", mock.errors)
+	}
+	foundLog := false
+	for _, log := range mock.logs {
+		if strings.Contains(log, "warning") {
+			foundLog = true
+			break
+		}
+	}
+	if !foundLog {
+		t.Errorf("expected warning to be logged, got: %v", mock.logs)
+	}
+
+	mock = &mockT{}
+	AssertDepsValid(mock, tmpDir, WithWarnAsError())
+	foundWarning := false
+	for _, err := range mock.errors {
+		if strings.Contains(err, "warning") {
+			foundWarning = true
+			break
+		}
+	}
+	if !foundWarning {
+		t.Errorf("expected warning error, got: %v", mock.errors)
+	}
+}
+
+func TestWithForbidNestedExecutionOption(t *testing.T) {
+	opts := &AssertOpts{}
+	opt := WithForbidNestedExecution()
+	opt(opts)
+
+	if !opts.ForbidNestedExecution {
+		t.Error("WithForbidNestedExecution should set ForbidNestedExecution to true")
+	}
+}
+
+func TestAssertDepsValidForbidNestedExecution(t *testing.T) {
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type App struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[App]{
+		ID:  "app",
+		Run: runApp,
+	})
+}
+
+func runApp(ctx context.Context) (App, error) {
+	if err := loadConfig(ctx); err != nil {
+		return App{}, err
+	}
+	return App{}, nil
+}
+
+func loadConfig(ctx context.Context) error {
+	_, _, err := graft.ExecuteFor[Config](ctx)
+	return err
+}
+
+func main() {
+	_, _, _ = graft.ExecuteFor[App](context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	results, err := AnalyzeDir(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDir error: %v", err)
+	}
+	nested := make(map[string]bool)
+	for _, r := range results {
+		nested[r.NodeID] = r.HasNestedExecution
+	}
+	if !nested["app"] || nested["config"] {
+		t.Fatalf("expected only app to have nested execution, got %v", nested)
+	}
+
+	// Without the option nested execution is only a warning
+	mock := &mockT{}
+	AssertDepsValid(mock, tmpDir)
+	if len(mock.errors) > 0 {
+		t.Errorf("expected no errors without option, got %v", mock.errors)
+	}
+
+	mock = &mockT{}
+	AssertDepsValid(mock, tmpDir, WithForbidNestedExecution())
+	foundNested := false
+	for _, err := range mock.errors {
+		if strings.Contains(err, "nested execution") {
+			foundNested = true
+			break
+		}
+	}
+	if !foundNested {
+		t.Errorf("expected nested execution error, got: %v", mock.errors)
+	}
+}
+
+func TestAssertDepsValidDepCounts(t *testing.T) {
+	type tc struct {
+		opts      []AssertOption
+		wantError string
+	}
+
+	code := `package main
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+type App struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:        "db",
+		DependsOn: []graft.ID{"config"},
+		Run: func(ctx context.Context) (DB, error) {
+			_, err := graft.Dep[Config](ctx)
+			return DB{}, err
+		},
+	})
+	graft.Register(graft.Node[App]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config", "db"},
+		Run: func(ctx context.Context) (App, error) {
+			if _, err := graft.Dep[Config](ctx); err != nil {
+				return App{}, err
+			}
+			_, err := graft.Dep[DB](ctx)
+			return App{}, err
+		},
+	})
+}
+
+func main() {
+	graft.Execute(context.Background())
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"main.go": code,
+	})
+
+	tests := map[string]tc{
+		"within limits": {
+			opts: []AssertOption{WithMaxDeps(2), WithExactDeps("db", 1), WithExactDeps("app", 2)},
+		},
+		"max exceeded": {
+			opts:      []AssertOption{WithMaxDeps(1)},
+			wantError: "more than the maximum",
+		},
+		"min not met": {
+			opts:      []AssertOption{WithMinDeps(1)},
+			wantError: "fewer than the minimum",
+		},
+		"exact mismatch": {
+			opts:      []AssertOption{WithExactDeps("app", 1)},
+			wantError: "want exactly",
+		},
+		"exact unknown node": {
+			opts:      []AssertOption{WithExactDeps("missing", 0)},
+			wantError: "node not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mock := &mockT{}
+			AssertDepsValid(mock, tmpDir, tt.opts...)
+
+			if tt.wantError == "" {
+				if len(mock.errors) > 0 {
+					t.Errorf("expected no errors, got %v", mock.errors)
+				}
+				return
+			}
+
+			var found int
+			for _, err := range mock.errors {
+				if strings.Contains(err, tt.wantError) {
+					found++
+				}
+			}
+			if found != 1 {
+				t.Errorf("expected one error containing %q, got: %v", tt.wantError, mock.errors)
+			}
+		})
+	}
+}
+
+func TestAssertRegistryValid(t *testing.T) {
+	type tc struct {
+		nodes      map[ID]node
+		wantErrors int
+	}
+
+	tests := map[string]tc{
+		"valid registry": {
+			nodes: map[ID]node{
+				"config": {id: "config"},
+				"db":     {id: "db", dependsOn: []ID{"config"}},
+			},
+			wantErrors: 0,
+		},
+		"one error per issue": {
+			nodes: map[ID]node{
+				"db":  {id: "db", dependsOn: []ID{"config", "cache"}},
+				"app": {id: "app", dependsOn: []ID{"app"}},
+			},
+			wantErrors: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &mockT{}
+			AssertRegistryValid(m, WithRegistry(tt.nodes))
+
+			if !m.helperCalled {
+				t.Error("expected t.Helper() to be called")
+			}
+			if len(m.errors) != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %v", len(m.errors), tt.wantErrors, m.errors)
+			}
+		})
+	}
+}
+
+func TestCheckGraphAcyclic(t *testing.T) {
+	type tc struct {
+		nodes   map[ID]node
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"empty graph": {
+			nodes: map[ID]node{},
+		},
+		"diamond": {
+			nodes: map[ID]node{
+				"config": {id: "config"},
+				"db":     {id: "db", dependsOn: []ID{"config"}},
+				"cache":  {id: "cache", dependsOn: []ID{"config"}},
+				"api":    {id: "api", dependsOn: []ID{"db", "cache"}},
+			},
+		},
+		"self cycle": {
+			nodes: map[ID]node{
+				"a": {id: "a", dependsOn: []ID{"a"}},
+			},
+			wantErr: "cycle detected: a -> a",
+		},
+		"three node cycle": {
+			nodes: map[ID]node{
+				"root": {id: "root"},
+				"a":    {id: "a", dependsOn: []ID{"root", "c"}},
+				"b":    {id: "b", dependsOn: []ID{"a"}},
+				"c":    {id: "c", dependsOn: []ID{"b"}},
+			},
+			wantErr: "cycle detected: a -> c -> b -> a",
+		},
+		"unknown dependency": {
+			nodes: map[ID]node{
+				"db": {id: "db", dependsOn: []ID{"config"}},
+			},
+			wantErr: "node db depends on unknown node config",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckGraphAcyclic(WithRegistry(tt.nodes))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CheckGraphAcyclic() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssertGraphAcyclic(t *testing.T) {
+	type tc struct {
+		nodes      map[ID]node
+		wantFatals int
+	}
+
+	tests := map[string]tc{
+		"acyclic": {
+			nodes: map[ID]node{
+				"a": {id: "a"},
+				"b": {id: "b", dependsOn: []ID{"a"}},
+			},
+			wantFatals: 0,
+		},
+		"cycle": {
+			nodes: map[ID]node{
+				"a": {id: "a", dependsOn: []ID{"b"}},
+				"b": {id: "b", dependsOn: []ID{"a"}},
+			},
+			wantFatals: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &mockT{}
+			AssertGraphAcyclic(m, WithRegistry(tt.nodes))
+
+			if !m.helperCalled {
+				t.Error("expected t.Helper() to be called")
+			}
+			if len(m.fatals) != tt.wantFatals {
+				t.Errorf("got %d fatals, want %d: %v", len(m.fatals), tt.wantFatals, m.fatals)
+			}
+		})
+	}
+}
+
+type assertNodeTestConfig struct{}
+type assertNodeTestDB struct{}
+type assertNodeTestMissing struct{}
+
+func TestAssertNodeHelpers(t *testing.T) {
+	resetGlobalState()
+	defer resetGlobalState()
+
+	Register(Node[assertNodeTestConfig]{
+		ID:  "config",
+		Run: func(ctx context.Context) (assertNodeTestConfig, error) { return assertNodeTestConfig{}, nil },
+	})
+	Register(Node[assertNodeTestDB]{
+		ID:        "db",
+		DependsOn: []ID{"config", "secrets"},
+		Run:       func(ctx context.Context) (assertNodeTestDB, error) { return assertNodeTestDB{}, nil },
+	})
+
+	type tc struct {
+		assert     func(t testing.TB)
+		wantFatals int
+	}
+
+	tests := map[string]tc{
+		"exists": {
+			assert: func(t testing.TB) { AssertNodeExists[assertNodeTestDB](t) },
+		},
+		"does not exist": {
+			assert:     func(t testing.TB) { AssertNodeExists[assertNodeTestMissing](t) },
+			wantFatals: 1,
+		},
+		"id matches": {
+			assert: func(t testing.TB) { AssertNodeID[assertNodeTestDB](t, "db") },
+		},
+		"id mismatch": {
+			assert:     func(t testing.TB) { AssertNodeID[assertNodeTestDB](t, "database") },
+			wantFatals: 1,
+		},
+		"id for missing type": {
+			assert:     func(t testing.TB) { AssertNodeID[assertNodeTestMissing](t, "missing") },
+			wantFatals: 1,
+		},
+		"deps match in any order": {
+			assert: func(t testing.TB) { AssertNodeDependsOn[assertNodeTestDB](t, "secrets", "config") },
+		},
+		"no deps": {
+			assert: func(t testing.TB) { AssertNodeDependsOn[assertNodeTestConfig](t) },
+		},
+		"deps mismatch": {
+			assert:     func(t testing.TB) { AssertNodeDependsOn[assertNodeTestDB](t, "config") },
+			wantFatals: 1,
+		},
+		"deps for missing type": {
+			assert:     func(t testing.TB) { AssertNodeDependsOn[assertNodeTestMissing](t) },
+			wantFatals: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &mockT{}
+			tt.assert(m)
+
+			if !m.helperCalled {
+				t.Error("expected t.Helper() to be called")
+			}
+			if len(m.fatals) != tt.wantFatals {
+				t.Errorf("got %d fatals, want %d: %v", len(m.fatals), tt.wantFatals, m.fatals)
+			}
+		})
+	}
+}
+
+func TestAssertDepsValidStrict(t *testing.T) {
+	code := `package nodes
+
+import (
+	"context"
+
+	"github.com/grindlemire/graft"
+)
+
+type Config struct{}
+type DB struct{}
+
+func init() {
+	graft.Register(graft.Node[Config]{
+		ID:  "config",
+		Run: func(ctx context.Context) (Config, error) { return Config{}, nil },
+	})
+	graft.Register(graft.Node[DB]{
+		ID:  "db",
+		Run: func(ctx context.Context) (DB, error) { return DB{}, nil },
+	})
+}
+`
+	testCode := `package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+func TestDB(t *testing.T) {
+	_, _, err := graft.ExecuteFor[DB](context.Background(),
+		graft.Patch[DB](graft.Node[DB]{
+			Run: func(ctx context.Context) (DB, error) {
+				_, err := graft.Dep[Config](ctx)
+				return DB{}, err
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+`
+	tmpDir := setupTestModule(t, map[string]string{
+		"nodes.go":      code,
+		"nodes_test.go": testCode,
+	})
+
+	mock := &mockT{}
+	AssertDepsValid(mock, tmpDir)
+	if len(mock.errors) != 0 || len(mock.fatals) != 0 {
+		t.Errorf("AssertDepsValid should ignore test files, got errors: %v fatals: %v", mock.errors, mock.fatals)
+	}
+
+	mock = &mockT{}
+	AssertDepsValidStrict(mock, tmpDir)
+	if len(mock.fatals) != 0 {
+		t.Fatalf("unexpected fatals: %v", mock.fatals)
+	}
+	foundUndeclared := false
+	for _, err := range mock.errors {
+		if strings.Contains(err, "does not declare") {
+			foundUndeclared = true
+		}
+	}
+	if !foundUndeclared {
+		t.Errorf("expected undeclared dep error for the patched node, got: %v", mock.errors)
+	}
+}
+
+func TestCompareResults(t *testing.T) {
+	type point struct{ X, Y int }
+
+	type tc struct {
+		a, b      map[ID]any
+		wantEqual bool
+		wantDiffs map[ID]string
+	}
+
+	tests := map[string]tc{
+		"equal": {
+			a:         map[ID]any{"config": point{1, 2}, "name": "x"},
+			b:         map[ID]any{"config": point{1, 2}, "name": "x"},
+			wantEqual: true,
+			wantDiffs: map[ID]string{},
+		},
+		"pointers to equal values": {
+			a:         map[ID]any{"config": &point{1, 2}},
+			b:         map[ID]any{"config": &point{1, 2}},
+			wantEqual: true,
+			wantDiffs: map[ID]string{},
+		},
+		"differing value": {
+			a:         map[ID]any{"config": point{1, 2}},
+			b:         map[ID]any{"config": point{1, 3}},
+			wantDiffs: map[ID]string{"config": "want {1 2} got {1 3}"},
+		},
+		"missing on either side": {
+			a:         map[ID]any{"db": 1},
+			b:         map[ID]any{"cache": 2},
+			wantDiffs: map[ID]string{"db": "want 1 got <missing>", "cache": "want <missing> got 2"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			equal, diffs := CompareResults(tt.a, tt.b)
+			if equal != tt.wantEqual {
+				t.Errorf("equal = %v, want %v", equal, tt.wantEqual)
+			}
+			if !reflect.DeepEqual(diffs, tt.wantDiffs) {
+				t.Errorf("diffs = %v, want %v", diffs, tt.wantDiffs)
+			}
+		})
+	}
+}
+
+func TestAssertResultsEqual(t *testing.T) {
+	mock := &mockT{}
+	AssertResultsEqual(mock, Results{"a": 1, "b": 2, "c": 3}, Results{"a": 1, "b": 20})
+	if len(mock.errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", mock.errors)
+	}
+
+	mock = &mockT{}
+	AssertResultsEqual(mock, Results{"a": 1}, Results{"a": 1})
+	if len(mock.errors) != 0 {
+		t.Errorf("expected no errors, got %v", mock.errors)
+	}
+}
@@ -0,0 +1,238 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/grindlemire/graft"
+)
+
+// Import paths of the packages every generated file uses.
+const (
+	graftPath     = "github.com/grindlemire/graft"
+	graftwirePath = "github.com/grindlemire/graft/wire"
+	wirePath      = "github.com/google/wire"
+)
+
+// GenerateProviders writes a Go source file for package pkgName to w with a
+// Wire provider for each node in nodeIDs and a ProviderSet combining them
+// with [ProvideResults]. Each provider takes [graft.Results] and returns
+// the node's output, using the output type the node was registered with
+// (see [graft.NodeOutputType]):
+//
+//	func provideConfig(results graft.Results) (config.Output, error) {
+//	    return graft.Result[config.Output](results)
+//	}
+//
+// Output types must be nameable from another package: named types,
+// pointers, slices, arrays and maps of them. The file must not be generated
+// into a package that defines one of the output types, since it imports
+// them. GenerateProviders returns an error for unknown nodes and types it
+// cannot name.
+//
+// Example:
+//
+//	//go:generate go run ./cmd/gen-graft-providers
+//
+//	// cmd/gen-graft-providers/main.go
+//	f, _ := os.Create("graft_providers.go")
+//	defer f.Close()
+//	err := graftwire.GenerateProviders(f, "app", "config", "db")
+func GenerateProviders(w io.Writer, pkgName string, nodeIDs ...graft.ID) error {
+	g := &generator{
+		imports: map[string]string{
+			graftPath:     "graft",
+			graftwirePath: "graftwire",
+			wirePath:      "wire",
+		},
+		names: make(map[string]bool),
+	}
+
+	var providers []provider
+	for _, id := range nodeIDs {
+		typ, err := graft.NodeOutputType(id)
+		if err != nil {
+			return err
+		}
+		expr, err := g.typeExpr(typ)
+		if err != nil {
+			return fmt.Errorf("node %s: %w", id, err)
+		}
+		providers = append(providers, provider{
+			id:   id,
+			name: g.providerName(id),
+			typ:  expr,
+		})
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by graftwire.GenerateProviders. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	buf.WriteString("import (\n")
+	for _, p := range paths {
+		fmt.Fprintf(&buf, "\t%s %q\n", g.imports[p], p)
+	}
+	buf.WriteString(")\n\n")
+
+	names := []string{"graftwire.ProvideResults"}
+	for _, p := range providers {
+		names = append(names, p.name)
+	}
+	buf.WriteString("// ProviderSet provides graft.Results and the outputs of the generated providers.\n")
+	fmt.Fprintf(&buf, "var ProviderSet = wire.NewSet(%s)\n", strings.Join(names, ", "))
+
+	for _, p := range providers {
+		fmt.Fprintf(&buf, "\n// %s returns the output of graft node %q.\n", p.name, p.id)
+		fmt.Fprintf(&buf, "func %s(results graft.Results) (%s, error) {\n", p.name, p.typ)
+		fmt.Fprintf(&buf, "\treturn graft.Result[%s](results)\n}\n", p.typ)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated providers: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// provider is one generated provider function.
+type provider struct {
+	id   graft.ID
+	name string // function name
+	typ  string // output type expression
+}
+
+// generator tracks the imports and identifiers of a generated file.
+type generator struct {
+	imports map[string]string // import path -> local name
+	names   map[string]bool   // provider function names in use
+}
+
+// typeExpr returns the Go expression for t, adding the imports it needs.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if strings.Contains(t.Name(), "[") {
+			return "", fmt.Errorf("cannot name generic type %s", t)
+		}
+		if t.PkgPath() == "" {
+			return t.Name(), nil // predeclared, e.g. string or error
+		}
+		if !token.IsExported(t.Name()) {
+			return "", fmt.Errorf("cannot name unexported type %s", t)
+		}
+		return g.importName(t.PkgPath()) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+	}
+	return "", fmt.Errorf("cannot name type %s", t)
+}
+
+// majorVersion matches the major version element of a module path such as
+// example.com/lib/v2; gopkgVersion matches the suffix of gopkg.in/yaml.v3.
+var (
+	majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+	gopkgVersion = regexp.MustCompile(`\.v[0-9]+$`)
+)
+
+// importName returns the local name for the package at importPath,
+// choosing one that does not collide with other imports.
+func (g *generator) importName(importPath string) string {
+	if name, ok := g.imports[importPath]; ok {
+		return name
+	}
+
+	base := path.Base(importPath)
+	if majorVersion.MatchString(base) && path.Dir(importPath) != "." {
+		base = path.Base(path.Dir(importPath))
+	}
+	base = gopkgVersion.ReplaceAllString(base, "")
+	name := identifier(base, false)
+
+	// results is the generated providers' parameter name
+	taken := map[string]bool{"results": true}
+	for _, n := range g.imports {
+		taken[n] = true
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.imports[importPath] = unique
+	return unique
+}
+
+// providerName returns an unused function name for node id's provider,
+// e.g. provideHttpClient for "http-client".
+func (g *generator) providerName(id graft.ID) string {
+	name := "provide" + identifier(string(id), true)
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// identifier turns s into a Go identifier by dropping characters that are
+// not letters or digits. With title set, each word starts upper case;
+// otherwise the result is lower case, as package names are. An empty
+// result, or one starting with a digit, gets a "pkg" or "Node" prefix.
+func identifier(s string, title bool) string {
+	var b strings.Builder
+	upper := title
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = title
+			continue
+		}
+		switch {
+		case upper:
+			r = unicode.ToUpper(r)
+		case !title:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+
+	out := b.String()
+	if out == "" || unicode.IsDigit([]rune(out)[0]) {
+		if title {
+			return "Node" + out
+		}
+		return "pkg" + out
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package wire
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/graft"
+)
+
+// unexportedOutput cannot be named by generated code in another package.
+type unexportedOutput struct{}
+
+func register[T any](id graft.ID) {
+	graft.Register(graft.Node[T]{
+		ID:  id,
+		Run: func(ctx context.Context) (T, error) { var zero T; return zero, nil },
+	})
+}
+
+func TestGenerateProviders(t *testing.T) {
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+	register[*url.URL]("base-url")
+	register[time.Duration]("timeout")
+	register[[]string]("tags")
+	register[map[string][2]*url.URL]("routes")
+
+	var buf bytes.Buffer
+	if err := GenerateProviders(&buf, "app", "base-url", "timeout", "tags", "routes"); err != nil {
+		t.Fatalf("GenerateProviders: %v", err)
+	}
+
+	want := `// Code generated by graftwire.GenerateProviders. DO NOT EDIT.
+
+package app
+
+import (
+	wire "github.com/google/wire"
+	graft "github.com/grindlemire/graft"
+	graftwire "github.com/grindlemire/graft/wire"
+	url "net/url"
+	time "time"
+)
+
+// ProviderSet provides graft.Results and the outputs of the generated providers.
+var ProviderSet = wire.NewSet(graftwire.ProvideResults, provideBaseUrl, provideTimeout, provideTags, provideRoutes)
+
+// provideBaseUrl returns the output of graft node "base-url".
+func provideBaseUrl(results graft.Results) (*url.URL, error) {
+	return graft.Result[*url.URL](results)
+}
+
+// provideTimeout returns the output of graft node "timeout".
+func provideTimeout(results graft.Results) (time.Duration, error) {
+	return graft.Result[time.Duration](results)
+}
+
+// provideTags returns the output of graft node "tags".
+func provideTags(results graft.Results) ([]string, error) {
+	return graft.Result[[]string](results)
+}
+
+// provideRoutes returns the output of graft node "routes".
+func provideRoutes(results graft.Results) (map[string][2]*url.URL, error) {
+	return graft.Result[map[string][2]*url.URL](results)
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("generated source mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateProvidersErrors(t *testing.T) {
+	type tc struct {
+		ids     []graft.ID
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"unknown node": {
+			ids:     []graft.ID{"missing"},
+			wantErr: "unknown node: missing",
+		},
+		"unexported type": {
+			ids:     []graft.ID{"hidden"},
+			wantErr: "node hidden: cannot name unexported type wire.unexportedOutput",
+		},
+		"unnamed func type": {
+			ids:     []graft.ID{"handler"},
+			wantErr: "node handler: cannot name type func()",
+		},
+	}
+
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+	register[unexportedOutput]("hidden")
+	register[func()]("handler")
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateProviders(&buf, "app", tt.ids...)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("nothing should be written on error, got:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestImportNameCollisions(t *testing.T) {
+	g := &generator{imports: map[string]string{graftPath: "graft"}}
+
+	got := []string{
+		g.importName("example.com/a/config"),
+		g.importName("example.com/b/config"),
+		g.importName("example.com/lib/v2"),
+		g.importName("gopkg.in/yaml.v3"),
+		g.importName("example.com/graft"),
+		g.importName("example.com/results"),
+		g.importName("example.com/a/config"),
+	}
+	want := []string{"config", "config2", "lib", "yaml", "graft2", "results2", "config"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("import names = %v, want %v", got, want)
+	}
+}
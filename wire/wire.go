@@ -0,0 +1,91 @@
+// Package wire connects graft to github.com/google/wire injectors.
+//
+// Wire resolves providers at code generation time by reading Go source, so
+// graft nodes cannot be handed to it as values at run time. Instead:
+//
+//   - [ProvideResults] is a Wire provider that executes the graft graph, and
+//     [Set] is a provider set containing it.
+//   - [GenerateProviders] writes a Go file with one named provider per node,
+//     each taking [graft.Results] and returning the node's output type, plus
+//     a ProviderSet for use in wire.Build.
+//   - [WireContext] carries values built by a Wire injector into a graft
+//     execution, where node Run functions read them with [Injected].
+package wire
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/wire"
+	"github.com/grindlemire/graft"
+)
+
+// Set is a Wire provider set containing [ProvideResults].
+//
+// Example:
+//
+//	func initApp(ctx context.Context) (*App, error) {
+//	    wire.Build(graftwire.Set, provideConfig, NewApp)
+//	    return nil, nil
+//	}
+var Set = wire.NewSet(ProvideResults)
+
+// ProvideResults executes the graft graph in the global registry with the
+// default cache and returns its results. It is a Wire provider for
+// [graft.Results]; the providers written by [GenerateProviders] depend on
+// it.
+//
+// Example:
+//
+//	var appSet = wire.NewSet(graftwire.ProvideResults, provideConfig)
+func ProvideResults(ctx context.Context) (graft.Results, error) {
+	return graft.Execute(ctx)
+}
+
+// injectedKey is the context key for values added by WireContext.
+type injectedKey struct{}
+
+// WireContext returns a copy of ctx carrying values, such as those a Wire
+// injector built, so that graft node Run functions can read them with
+// [Injected]. Values are keyed by their dynamic type; a later value of the
+// same type replaces an earlier one, including one from a parent context.
+// Nil values are ignored.
+//
+// Example:
+//
+//	db, cleanup, err := initDB(ctx) // generated by Wire
+//	ctx = graftwire.WireContext(ctx, db)
+//	results, err := graft.Execute(ctx)
+func WireContext(ctx context.Context, values ...any) context.Context {
+	injected := make(map[reflect.Type]any)
+	if parent, ok := ctx.Value(injectedKey{}).(map[reflect.Type]any); ok {
+		for typ, v := range parent {
+			injected[typ] = v
+		}
+	}
+	for _, v := range values {
+		if v != nil {
+			injected[reflect.TypeOf(v)] = v
+		}
+	}
+	return context.WithValue(ctx, injectedKey{}, injected)
+}
+
+// Injected returns the value of type T added to ctx by [WireContext]. The
+// type must match exactly: a *sql.DB added to the context is not found by
+// Injected[any] or an interface it implements.
+//
+// Example:
+//
+//	Run: func(ctx context.Context) (Output, error) {
+//	    db, ok := graftwire.Injected[*sql.DB](ctx)
+//	    if !ok {
+//	        return Output{}, errors.New("no *sql.DB injected")
+//	    }
+//	    ...
+//	}
+func Injected[T any](ctx context.Context) (T, bool) {
+	injected, _ := ctx.Value(injectedKey{}).(map[reflect.Type]any)
+	v, ok := injected[reflect.TypeOf((*T)(nil)).Elem()].(T)
+	return v, ok
+}
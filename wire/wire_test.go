@@ -0,0 +1,91 @@
+package wire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grindlemire/graft"
+)
+
+type dbHandle struct{ dsn string }
+
+type appOutput struct{ dsn string }
+
+func TestWireContext(t *testing.T) {
+	graft.ResetRegistry()
+	graft.ResetDefaultCache()
+	t.Cleanup(graft.ResetRegistry)
+	t.Cleanup(graft.ResetDefaultCache)
+
+	graft.Register(graft.Node[appOutput]{
+		ID: "app",
+		Run: func(ctx context.Context) (appOutput, error) {
+			db, ok := Injected[*dbHandle](ctx)
+			if !ok {
+				t.Error("*dbHandle not injected")
+				return appOutput{}, nil
+			}
+			return appOutput{dsn: db.dsn}, nil
+		},
+	})
+
+	ctx := WireContext(context.Background(), &dbHandle{dsn: "postgres://"}, nil)
+	results, err := ProvideResults(ctx)
+	if err != nil {
+		t.Fatalf("ProvideResults: %v", err)
+	}
+	out, err := graft.Result[appOutput](results)
+	if err != nil {
+		t.Fatalf("Result: %v", err)
+	}
+	if out.dsn != "postgres://" {
+		t.Errorf("dsn = %q, want %q", out.dsn, "postgres://")
+	}
+}
+
+func TestInjected(t *testing.T) {
+	type tc struct {
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}
+
+	parent := WireContext(context.Background(), "parent", 1)
+
+	tests := map[string]tc{
+		"no values": {
+			ctx: context.Background(),
+		},
+		"exact type": {
+			ctx:    WireContext(context.Background(), "value"),
+			want:   "value",
+			wantOK: true,
+		},
+		"inherits parent values": {
+			ctx:    WireContext(parent, 2),
+			want:   "parent",
+			wantOK: true,
+		},
+		"later value replaces earlier": {
+			ctx:    WireContext(parent, "child"),
+			want:   "child",
+			wantOK: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := Injected[string](tt.ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Injected[string] = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := Injected[any](WireContext(context.Background(), "value")); ok {
+		t.Error("Injected[any] should not match a string value")
+	}
+	if got, _ := Injected[int](WireContext(parent, 2)); got != 2 {
+		t.Errorf("Injected[int] = %d, want 2", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Package yaml serializes graft node metadata as YAML for review and
+// non-Go tooling.
+//
+// The documents have the same shape as [graft.RegistryToJSON] output and
+// are validated with the same rules as [graft.ValidateRegistryJSON].
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grindlemire/graft"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeSpec is one node in a registry document.
+type nodeSpec struct {
+	ID          graft.ID   `json:"id" yaml:"id"`
+	DependsOn   []graft.ID `json:"depends_on" yaml:"depends_on"`
+	Cacheable   bool       `json:"cacheable" yaml:"cacheable"`
+	Description string     `json:"description" yaml:"description"`
+	Tags        []string   `json:"tags" yaml:"tags"`
+}
+
+// RegistryToYAML serializes node metadata (id, depends_on, cacheable,
+// description, tags) as a YAML sequence sorted by ID. Run functions are
+// not included.
+//
+// By default, serializes the global registry. Use [graft.WithRegistry] for
+// a custom registry.
+//
+// Example:
+//
+//	data, err := graftyaml.RegistryToYAML()
+//	os.WriteFile("graph.yaml", data, 0o644)
+//
+// Example output:
+//
+//	# one entry per node, sorted by id
+//	- id: db
+//	  depends_on:
+//	    - config
+//	  cacheable: true
+//	  description: Postgres connection pool
+//	  tags:
+//	    - storage
+func RegistryToYAML(opts ...graft.Option) ([]byte, error) {
+	data, err := graft.RegistryToJSON(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var specs []nodeSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(specs); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ValidateRegistryYAML checks that data describes a valid dependency graph
+// in the format produced by [RegistryToYAML], using the rules of
+// [graft.ValidateRegistryJSON]. Unknown fields are rejected. An empty
+// document describes an empty graph.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("graph.yaml")
+//	if err := graftyaml.ValidateRegistryYAML(data); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateRegistryYAML(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	specs := []nodeSpec{}
+	if err := dec.Decode(&specs); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode registry: %w", err)
+	}
+
+	data, err := json.Marshal(specs)
+	if err != nil {
+		return err
+	}
+	return graft.ValidateRegistryJSON(data)
+}
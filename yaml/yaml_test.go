@@ -0,0 +1,164 @@
+package yaml
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"gopkg.in/yaml.v3"
+)
+
+func registerNodes(t *testing.T) {
+	t.Helper()
+	graft.ResetRegistry()
+	t.Cleanup(graft.ResetRegistry)
+
+	graft.Register(graft.Node[string]{
+		ID:          "config",
+		Cacheable:   true,
+		Description: "App config",
+		Tags:        []string{"core"},
+		Run:         func(ctx context.Context) (string, error) { return "", nil },
+	})
+	graft.Register(graft.Node[int]{
+		ID:          "db",
+		DependsOn:   []graft.ID{"config"},
+		Description: `Postgres "primary": yes`,
+		Tags:        []string{"null", "core"},
+		Run:         func(ctx context.Context) (int, error) { return 0, nil },
+	})
+	graft.Register(graft.Node[bool]{
+		ID:        "app",
+		DependsOn: []graft.ID{"config", "db"},
+		Run:       func(ctx context.Context) (bool, error) { return false, nil },
+	})
+}
+
+func TestRegistryToYAML(t *testing.T) {
+	registerNodes(t)
+
+	data, err := RegistryToYAML()
+	if err != nil {
+		t.Fatalf("RegistryToYAML error: %v", err)
+	}
+
+	want := `- id: app
+  depends_on:
+    - config
+    - db
+  cacheable: false
+  description: ""
+  tags: []
+- id: config
+  depends_on: []
+  cacheable: true
+  description: App config
+  tags:
+    - core
+- id: db
+  depends_on:
+    - config
+  cacheable: false
+  description: 'Postgres "primary": yes'
+  tags:
+    - "null"
+    - core
+`
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+	if err := ValidateRegistryYAML(data); err != nil {
+		t.Errorf("output does not validate: %v", err)
+	}
+
+	var specs []nodeSpec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if got := specs[2]; got.Description != `Postgres "primary": yes` || got.Tags[0] != "null" {
+		t.Errorf("strings did not round-trip: %+v", got)
+	}
+}
+
+func TestRegistryToYAMLEmpty(t *testing.T) {
+	data, err := RegistryToYAML(graft.WithRegistry(nil))
+	if err != nil {
+		t.Fatalf("RegistryToYAML error: %v", err)
+	}
+	if string(data) != "[]\n" {
+		t.Errorf("got %q, want %q", data, "[]\n")
+	}
+}
+
+func TestValidateRegistryYAML(t *testing.T) {
+	type tc struct {
+		yaml    string
+		wantErr string
+	}
+
+	tests := map[string]tc{
+		"block and flow style": {
+			yaml: `# reviewed graph
+- id: config
+  cacheable: true
+- id: db
+  depends_on: [config]
+  tags:
+  - 'storage'
+`,
+		},
+		"empty document": {
+			yaml: "",
+		},
+		"unknown dependency": {
+			yaml: `- id: db
+  depends_on: [config]
+`,
+			wantErr: "node db depends on unknown node config",
+		},
+		"cycle": {
+			yaml: `- id: a
+  depends_on: [b]
+- id: b
+  depends_on: [a]
+`,
+			wantErr: "cycle detected: a -> b -> a",
+		},
+		"duplicate id": {
+			yaml:    "- id: a\n- id: a\n",
+			wantErr: "duplicate node id a",
+		},
+		"missing id": {
+			yaml:    "- id: a\n- cacheable: true\n",
+			wantErr: "node 2 has no id",
+		},
+		"unknown field": {
+			yaml:    "- id: a\n  run: main.go\n",
+			wantErr: "field run not found",
+		},
+		"not a sequence": {
+			yaml:    "nodes:\n  - id: a\n",
+			wantErr: "decode registry",
+		},
+		"invalid cacheable": {
+			yaml:    "- id: a\n  cacheable: maybe\n",
+			wantErr: "decode registry",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRegistryYAML([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
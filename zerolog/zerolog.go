@@ -0,0 +1,68 @@
+// Package zerolog logs graft node lifecycle events with
+// github.com/rs/zerolog.
+//
+// It emits the same records, with the same structured keys, as the
+// graft/slog package: node_id, level, duration_ms, cache_hit and error.
+//
+// zerolog writes the record's severity under [zerolog.LevelFieldName],
+// which is also "level" by default. Set it to another name, such as
+// "severity", to keep both in JSON output.
+package zerolog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grindlemire/graft"
+	graftslog "github.com/grindlemire/graft/slog"
+	"github.com/rs/zerolog"
+)
+
+// Structured keys used in every record, shared with graft/slog.
+const (
+	KeyNodeID     = graftslog.KeyNodeID
+	KeyLevel      = graftslog.KeyLevel
+	KeyDurationMS = graftslog.KeyDurationMS
+	KeyCacheHit   = graftslog.KeyCacheHit
+	KeyError      = graftslog.KeyError
+)
+
+// WithLogger logs each node as it runs: a Debug "starting" event before
+// the node is resolved, then an Info "completed" event with its duration
+// and whether it was served from the cache, or an Error "failed" event
+// that also carries the error.
+//
+// Example:
+//
+//	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+//	results, err := graft.Execute(ctx, graftzerolog.WithLogger(logger))
+func WithLogger(log zerolog.Logger) graft.Option {
+	// Cache hits are reported before completion; remember them so the
+	// completion event can include cache_hit
+	var hits sync.Map // graft.ID -> struct{}
+
+	return graft.WithHooks(graft.Hooks{
+		OnStart: func(id graft.ID, level int) {
+			log.Debug().Str(KeyNodeID, string(id)).Int(KeyLevel, level).Msg("starting")
+		},
+		OnCacheHit: func(id graft.ID) {
+			hits.Store(id, struct{}{})
+		},
+		OnComplete: func(id graft.ID, level int, d time.Duration, err error) {
+			_, hit := hits.LoadAndDelete(id)
+			ev := log.Info()
+			if err != nil {
+				ev = log.Error()
+			}
+			ev = ev.Str(KeyNodeID, string(id)).
+				Int(KeyLevel, level).
+				Int64(KeyDurationMS, d.Milliseconds()).
+				Bool(KeyCacheHit, hit)
+			if err != nil {
+				ev.AnErr(KeyError, err).Msg("failed")
+				return
+			}
+			ev.Msg("completed")
+		},
+	})
+}
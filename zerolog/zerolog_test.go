@@ -0,0 +1,104 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grindlemire/graft"
+	"github.com/rs/zerolog"
+)
+
+type configOutput struct{}
+type appOutput struct{}
+
+func TestWithLogger(t *testing.T) {
+	type tc struct {
+		appErr    error
+		wantMsgs  []string
+		wantError string
+	}
+
+	tests := map[string]tc{
+		"success": {
+			wantMsgs: []string{"starting", "completed", "starting", "completed"},
+		},
+		"failure": {
+			appErr:    errors.New("boom"),
+			wantMsgs:  []string{"starting", "completed", "starting", "failed"},
+			wantError: "node app: boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			graft.ResetRegistry()
+			graft.ResetDefaultCache()
+			t.Cleanup(graft.ResetRegistry)
+
+			graft.Register(graft.Node[configOutput]{
+				ID:        "config",
+				Cacheable: true,
+				Run:       func(ctx context.Context) (configOutput, error) { return configOutput{}, nil },
+			})
+			graft.Register(graft.Node[appOutput]{
+				ID:        "app",
+				DependsOn: []graft.ID{"config"},
+				Run:       func(ctx context.Context) (appOutput, error) { return appOutput{}, tt.appErr },
+			})
+
+			cache := graft.NewMemoryCache()
+			if err := cache.Set(context.Background(), "config", configOutput{}); err != nil {
+				t.Fatalf("cache set: %v", err)
+			}
+
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf).Level(zerolog.DebugLevel)
+			_, err := graft.Execute(context.Background(), graft.WithCache(cache), WithLogger(logger))
+			if (err != nil) != (tt.appErr != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var records []map[string]any
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var rec map[string]any
+				if err := dec.Decode(&rec); err != nil {
+					t.Fatalf("decoding log record: %v", err)
+				}
+				records = append(records, rec)
+			}
+
+			if len(records) != len(tt.wantMsgs) {
+				t.Fatalf("got %d records, want %d: %v", len(records), len(tt.wantMsgs), records)
+			}
+			for i, rec := range records {
+				if rec["message"] != tt.wantMsgs[i] {
+					t.Errorf("record %d msg = %v, want %s", i, rec["message"], tt.wantMsgs[i])
+				}
+				if _, ok := rec[KeyNodeID]; !ok {
+					t.Errorf("record %d missing %s", i, KeyNodeID)
+				}
+				if _, ok := rec[KeyLevel]; !ok {
+					t.Errorf("record %d missing %s", i, KeyLevel)
+				}
+			}
+
+			configDone, appDone := records[1], records[3]
+			if configDone[KeyCacheHit] != true {
+				t.Errorf("config %s = %v, want true", KeyCacheHit, configDone[KeyCacheHit])
+			}
+			if appDone[KeyCacheHit] != false {
+				t.Errorf("app %s = %v, want false", KeyCacheHit, appDone[KeyCacheHit])
+			}
+			if _, ok := appDone[KeyDurationMS]; !ok {
+				t.Errorf("app record missing %s", KeyDurationMS)
+			}
+			if tt.wantError != "" && appDone[KeyError] != tt.wantError {
+				t.Errorf("app %s = %v, want %s", KeyError, appDone[KeyError], tt.wantError)
+			}
+		})
+	}
+}